@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"image/color"
@@ -512,7 +513,7 @@ func (a *App) performConversion() error {
 	a.appendLog("🔄 执行转换...")
 	
 	// 执行转换
-	err = conv.Convert(content, a.outputPath.Text)
+	err = conv.Convert(context.Background(), content, a.outputPath.Text)
 	if err != nil {
 		return fmt.Errorf("转换失败: %w", err)
 	}
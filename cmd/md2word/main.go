@@ -1,13 +1,19 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"md2word/internal/config"
 	"md2word/internal/converter"
+	"md2word/internal/i18n"
 )
 
 // 由 -ldflags "-X main.Version=... -X main.BuildTime=..." 注入
@@ -16,19 +22,40 @@ var (
 	BuildTime = "unknown"
 )
 
+// varFlags 可重复的 -var name=value 参数
+type varFlags map[string]string
+
+func (v varFlags) String() string {
+	return fmt.Sprint(map[string]string(v))
+}
+
+func (v varFlags) Set(s string) error {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("格式应为 name=value: %q", s)
+	}
+	v[name] = value
+	return nil
+}
+
 func main() {
 	var (
 		inputFile  string
 		outputFile string
 		configFile string
+		variables  = varFlags{}
+		watch      bool
 	)
 
-	flag.StringVar(&inputFile, "i", "", "输入Markdown文件路径")
-	flag.StringVar(&inputFile, "input", "", "输入Markdown文件路径")
-	flag.StringVar(&outputFile, "o", "", "输出DOCX文件路径")
-	flag.StringVar(&outputFile, "output", "", "输出DOCX文件路径")
+	flag.StringVar(&inputFile, "i", "", "输入Markdown文件或目录路径")
+	flag.StringVar(&inputFile, "input", "", "输入Markdown文件或目录路径")
+	flag.StringVar(&outputFile, "o", "", "输出DOCX文件路径 (输入为目录时为输出目录)")
+	flag.StringVar(&outputFile, "output", "", "输出DOCX文件路径 (输入为目录时为输出目录)")
 	flag.StringVar(&configFile, "c", "", "配置文件路径")
 	flag.StringVar(&configFile, "config", "", "配置文件路径")
+	flag.BoolVar(&watch, "w", false, "监视模式: 输入文件或其引用的图片变化后自动重新转换, Ctrl+C 退出")
+	flag.BoolVar(&watch, "watch", false, "监视模式: 输入文件或其引用的图片变化后自动重新转换, Ctrl+C 退出")
+	flag.Var(variables, "var", "替换正文中 {{name}} 的变量, 格式 name=value, 可重复")
 	flag.Parse()
 
 	if inputFile == "" {
@@ -37,10 +64,20 @@ func main() {
 		os.Exit(1)
 	}
 
+	info, err := os.Stat(inputFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "读取文件失败: %v\n", err)
+		os.Exit(1)
+	}
 	if outputFile == "" {
-		// 默认输出文件名
-		ext := filepath.Ext(inputFile)
-		outputFile = inputFile[:len(inputFile)-len(ext)] + ".docx"
+		if info.IsDir() {
+			// 目录默认输出到源目录，与各 Markdown 文件并列
+			outputFile = inputFile
+		} else {
+			// 默认输出文件名
+			ext := filepath.Ext(inputFile)
+			outputFile = inputFile[:len(inputFile)-len(ext)] + ".docx"
+		}
 	}
 
 	// 加载配置：-c > ./config.yaml > $EXE_DIR/config.yaml > 内置默认
@@ -49,21 +86,81 @@ func main() {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
 	}
-	fmt.Printf("加载配置: %s\n", source)
+	lang := cfg.Lang
+	fmt.Println(i18n.T(lang, i18n.LoadedConfig, source))
+
+	// 转换；Ctrl+C 中止正在进行的渲染与下载
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	conv := converter.NewConverter(cfg)
+	conv.SetVariables(variables)
+
+	if info.IsDir() {
+		if watch {
+			fmt.Fprintln(os.Stderr, i18n.T(lang, i18n.WatchFileOnly))
+			os.Exit(1)
+		}
+		convertDir(ctx, lang, conv, inputFile, outputFile)
+		return
+	}
+	if watch {
+		watchFile(ctx, lang, conv, inputFile, outputFile)
+		return
+	}
 
 	// 读取Markdown文件
 	mdContent, err := os.ReadFile(inputFile)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "读取文件失败: %v\n", err)
+		fmt.Fprintln(os.Stderr, i18n.T(lang, i18n.ReadFailed, err))
 		os.Exit(1)
 	}
 
-	// 转换
-	conv := converter.NewConverter(cfg)
-	if err := conv.Convert(mdContent, outputFile); err != nil {
-		fmt.Fprintf(os.Stderr, "转换失败: %v\n", err)
+	if err := conv.Convert(ctx, mdContent, outputFile); err != nil {
+		if errors.Is(err, context.Canceled) {
+			fmt.Fprintln(os.Stderr, i18n.T(lang, i18n.ConvertCancel))
+			os.Exit(130)
+		}
+		fmt.Fprintln(os.Stderr, i18n.Errorf(lang, i18n.ConvertFailed, err))
+		os.Exit(1)
+	}
+
+	fmt.Println(i18n.T(lang, i18n.ConvertSuccess, inputFile, outputFile))
+}
+
+// convertDir 批量转换目录，逐个报告失败的文件，存在失败时以非零状态退出
+func convertDir(ctx context.Context, lang string, conv *converter.Converter, srcDir, dstDir string) {
+	report, err := conv.ConvertDir(ctx, srcDir, dstDir)
+	if errors.Is(err, context.Canceled) {
+		fmt.Fprintln(os.Stderr, i18n.T(lang, i18n.ConvertCancel))
+		os.Exit(130)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, i18n.Errorf(lang, i18n.ConvertFailed, err))
 		os.Exit(1)
 	}
 
-	fmt.Printf("转换成功: %s -> %s\n", inputFile, outputFile)
+	failed := report.Failed()
+	for _, res := range failed {
+		fmt.Fprintln(os.Stderr, i18n.T(lang, i18n.FileFailed, res.Source, res.Err))
+	}
+	fmt.Println(i18n.T(lang, i18n.ConvertDone, len(report.Results)-len(failed), len(failed), srcDir, dstDir))
+	if len(failed) > 0 {
+		os.Exit(1)
+	}
+}
+
+// watchFile 监视并持续转换单个文件，直到 Ctrl+C
+func watchFile(ctx context.Context, lang string, conv *converter.Converter, inputFile, outputFile string) {
+	fmt.Println(i18n.T(lang, i18n.Watching, inputFile))
+	err := conv.Watch(ctx, inputFile, outputFile, func(err error) {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[%s] %v\n", time.Now().Format("15:04:05"), i18n.Errorf(lang, i18n.ConvertFailed, err))
+			return
+		}
+		fmt.Printf("[%s] %s\n", time.Now().Format("15:04:05"), i18n.T(lang, i18n.ConvertSuccess, inputFile, outputFile))
+	})
+	if err != nil && !errors.Is(err, context.Canceled) {
+		fmt.Fprintln(os.Stderr, i18n.T(lang, i18n.WatchFailed, err))
+		os.Exit(1)
+	}
 }
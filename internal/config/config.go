@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"unicode"
 
 	"gopkg.in/yaml.v3"
+
+	"md2word/internal/i18n"
 )
 
 //go:embed default.yaml
@@ -14,27 +17,40 @@ var defaultConfigData []byte
 
 // StyleConfig 样式配置
 type StyleConfig struct {
-	Font            string  `yaml:"font"`
-	Size            float64 `yaml:"size"`
-	Bold            bool    `yaml:"bold"`
-	Italic          bool    `yaml:"italic"`
-	Color           string  `yaml:"color"`
-	Background      string  `yaml:"background"`
-	LineSpacing     int     `yaml:"lineSpacing"`     // 行间距 (twips) - 已弃用，使用 SpaceBefore/SpaceAfter
-	LineHeight      int     `yaml:"lineHeight"`      // 行高 (twips, 240=1倍, 360=1.5倍)
-	SpaceBefore     int     `yaml:"spaceBefore"`     // 段前间距 (twips, 20=1pt)
-	SpaceAfter      int     `yaml:"spaceAfter"`      // 段后间距 (twips)
-	FirstLineIndent int     `yaml:"firstLineIndent"` // 首行缩进 (twips, 210=10.5pt=1字符(五号))
+	Font            string   `yaml:"font"`
+	Size            Points   `yaml:"size"`
+	Bold            bool     `yaml:"bold"`
+	Italic          bool     `yaml:"italic"`
+	SmallCaps       bool     `yaml:"smallCaps"`     // 小型大写字母（标题样式）
+	LetterSpacing   Twips    `yaml:"letterSpacing"` // 字符间距调整 (twips, 正数加宽、负数紧缩; 标题样式)
+	Color           string   `yaml:"color"`
+	Background      string   `yaml:"background"`      // 底色: 正文/标题为段落底纹, 行内代码为文字底纹, 代码块为单元格底色
+	LineSpacing     Twips    `yaml:"lineSpacing"`     // 行间距 (twips) - 已弃用，使用 SpaceBefore/SpaceAfter
+	LineHeight      Twips    `yaml:"lineHeight"`      // 行高 (twips, 240=1倍, 360=1.5倍)
+	SpaceBefore     Twips    `yaml:"spaceBefore"`     // 段前间距 (twips, 20=1pt)
+	SpaceAfter      Twips    `yaml:"spaceAfter"`      // 段后间距 (twips)
+	FirstLineIndent Twips    `yaml:"firstLineIndent"` // 首行缩进 (twips, 210=10.5pt=1字符(五号))
+	FontFallback    []string `yaml:"fontFallback"`    // 字体缺失时的回退字体链，写入 fontTable.xml
 }
 
 // TableConfig 表格配置
 type TableConfig struct {
-	Font       string  `yaml:"font"`
-	Size       float64 `yaml:"size"`
-	Borders    bool    `yaml:"borders"`
-	HeaderBold bool    `yaml:"headerBold"`
+	Font             string     `yaml:"font"`
+	Size             Points     `yaml:"size"`
+	Borders          bool       `yaml:"borders"`
+	HeaderBold       bool       `yaml:"headerBold"`
+	HeaderBackground string     `yaml:"headerBackground"` // Markdown 表格表头行的底色，如 "#F2F2F2"；为空不加底纹
+	Overflow         string     `yaml:"overflow"`         // 超出页面宽度时的处理: scale, rotate, shrinkFont; 为空不处理
+	Width            TableWidth `yaml:"width"`            // 表格宽度: 页面内容宽度的百分比或长度; 为空按内容自动
 }
 
+// table.overflow 可选值
+const (
+	TableOverflowScale      = "scale"
+	TableOverflowRotate     = "rotate"
+	TableOverflowShrinkFont = "shrinkFont"
+)
+
 // MermaidConfig Mermaid配置
 type MermaidConfig struct {
 	Enabled bool   `yaml:"enabled"`
@@ -43,44 +59,339 @@ type MermaidConfig struct {
 	Width   int    `yaml:"width"`  // 渲染宽度
 	Height  int    `yaml:"height"` // 渲染高度
 	Scale   int    `yaml:"scale"`  // 渲染缩放倍数
+	Format  string `yaml:"format"` // 嵌入格式: png, svg（矢量图并附带 PNG 后备图）
+
+	FixedWidth int `yaml:"fixedWidth"` // 流程图的默认固定宽度（像素），代码块的 width= 优先；0 表示按自然尺寸
+
+	OnlineFallback bool `yaml:"onlineFallback"` // 找不到浏览器时改用 mermaid.ink 在线渲染（需 network.allowExternal）
+
+	ThemeVariables map[string]string `yaml:"themeVariables"` // 传给 mermaid.initialize 的主题变量，如 primaryColor
+	CSS            string            `yaml:"css"`            // 注入流程图的自定义 CSS（themeCSS）
 }
 
+// mermaid.format 可选值
+const (
+	MermaidFormatPNG = "png"
+	MermaidFormatSVG = "svg"
+)
+
 // MathConfig 数学公式配置
 type MathConfig struct {
-	Enabled bool   `yaml:"enabled"`
-	Render  string `yaml:"render"` // "mathjax" or "image"
+	Enabled             bool   `yaml:"enabled"`
+	Render              string `yaml:"render"`              // "mathjax" or "image"
+	Transparent         bool   `yaml:"transparent"`         // 把公式图片的白色背景转为透明，与彩色页面或正文底色融合
+	InlineBaselineShift Points `yaml:"inlineBaselineShift"` // 行内公式图片相对基线的垂直偏移（磅），负数下移
+}
+
+// CodeConfig 代码块行为配置
+type CodeConfig struct {
+	IndentedAsCode bool   `yaml:"indentedAsCode"` // 缩进代码块按代码渲染；false 时按旧行为重新解析为 Markdown
+	HighlightStyle string `yaml:"highlightStyle"` // 代码块高亮使用的 Chroma 样式名，如 github、github-dark
+}
+
+// LinkConfig 超链接样式配置
+type LinkConfig struct {
+	Color     string `yaml:"color"`
+	Underline bool   `yaml:"underline"` // 链接文字加下划线
+
+	ExternalIndicator string `yaml:"externalIndicator"` // 附加在外部链接后的标记（如 "↗"），为空时不加
+}
+
+// ListConfig 列表样式配置
+type ListConfig struct {
+	OrderedFormats    []string `yaml:"orderedFormats"`    // 有序列表各级序号格式，更深的层级沿用最后一项
+	ContinueNumbering bool     `yaml:"continueNumbering"` // 被段落、代码块等打断后，从 1 开始的有序列表接续上一个列表的序号
+	SpaceBefore       Twips    `yaml:"spaceBefore"`       // 列表第一项的段前间距 (twips)
+	SpaceAfter        Twips    `yaml:"spaceAfter"`        // 列表最后一段（含嵌套列表）的段后间距 (twips)
+}
+
+// TaskListConfig 任务列表 (- [ ] / - [x]) 配置
+type TaskListConfig struct {
+	Layout          string `yaml:"layout"`          // 排版方式: inline (复选框代替项目符号), table (复选框与文字两列的无边框表格)
+	StrikeCompleted bool   `yaml:"strikeCompleted"` // 已完成 ([x]) 的任务项文字加删除线
+}
+
+// styles.taskList.layout 可选值
+const (
+	TaskListInline = "inline"
+	TaskListTable  = "table"
+)
+
+// DropCapConfig 首字下沉配置
+type DropCapConfig struct {
+	Enabled bool `yaml:"enabled"` // 一级标题后第一个正文段落的首字下沉
+	Lines   int  `yaml:"lines"`   // 下沉的行数 (1~10)
+}
+
+// list.orderedFormats 可选值
+const (
+	ListFormatDecimal    = "decimal"
+	ListFormatLowerAlpha = "lower-alpha"
+	ListFormatUpperAlpha = "upper-alpha"
+	ListFormatLowerRoman = "lower-roman"
+	ListFormatUpperRoman = "upper-roman"
+)
+
+// PaletteConfig 主题调色板
+type PaletteConfig struct {
+	Accent string `yaml:"accent"` // 强调色：未单独设置颜色的各级标题由此派生，级别越深颜色越浅; 为空不着色
+}
+
+// ParagraphsConfig 段落配置
+type ParagraphsConfig struct {
+	PreserveLineBreaks bool   `yaml:"preserveLineBreaks"` // 段内软换行转为 Word 换行符而非合并为一行
+	CJKLineBreak       bool   `yaml:"cjkLineBreak"`       // 合并软换行时，两侧均为中日韩字符则不插入空格
+	Style              string `yaml:"style"`              // 正文段落引用的样式 ID，为空时为 BodyText（按 styles.body 生成）
+	KeepEmpty          bool   `yaml:"keepEmpty"`          // 保留转换后没有内容的段落（如只有空 HTML 标签），输出为空段落用于留白
+}
+
+// HeadingsConfig 标题行为配置
+type HeadingsConfig struct {
+	KeepWithNext   bool `yaml:"keepWithNext"`   // 标题样式设置与下段同页，避免标题单独留在页尾
+	MaxStyledLevel int  `yaml:"maxStyledLevel"` // 按标题样式输出的最深级别 (1~9)，更深的标题输出为加粗正文；0 表示不限制
+}
+
+// ReviewConfig 审阅相关配置
+type ReviewConfig struct {
+	Author string `yaml:"author"` // 批注与修订的作者名称
+}
+
+// CriticMarkupConfig CriticMarkup 审阅标记配置
+type CriticMarkupConfig struct {
+	Enabled bool `yaml:"enabled"` // 解析 {++新增++}、{--删除--}、{~~原文~>新文~~}、{==高亮==}、{>>批注<<}
+}
+
+// WikiLinksConfig Obsidian 风格链接配置
+type WikiLinksConfig struct {
+	Enabled bool `yaml:"enabled"` // 解析 [[标题]]、[[标题|显示文字]] 链接与 ![[图片]] 嵌入
+}
+
+// IndexConfig 索引配置
+type IndexConfig struct {
+	Enabled bool `yaml:"enabled"` // 解析 {index: 术语} 索引项与 {INDEX} 索引位置
+}
+
+// VariablesConfig {{name}} 占位符配置
+type VariablesConfig struct {
+	Strict bool `yaml:"strict"` // 存在未定义的变量时转换失败；否则保留原文
+}
+
+// AccessibilityConfig 无障碍检查配置（Section 508 / WCAG）
+type AccessibilityConfig struct {
+	Check  bool `yaml:"check"`  // 检查图片替代文字、标题层级跳跃与表格表头，问题作为警告输出
+	Strict bool `yaml:"strict"` // 存在无障碍问题时转换失败（隐含 check）
+}
+
+// ChromeConfig 渲染流程图所用浏览器的启动配置
+type ChromeConfig struct {
+	NoSandbox *bool    `yaml:"noSandbox"` // 关闭沙箱 (--no-sandbox)；未设置时仅在以 root 运行时关闭
+	Flags     []string `yaml:"flags"`     // 额外的启动参数，如 --disable-dev-shm-usage
+}
+
+// ErrorsConfig 渲染失败时的占位显示
+type ErrorsConfig struct {
+	PlaceholderText  map[string]string `yaml:"placeholderText"`  // 各类内容的占位标题，键为 mermaid、math、image
+	PlaceholderColor string            `yaml:"placeholderColor"` // 占位的底色
+}
+
+// CaptionsConfig 题注配置
+type CaptionsConfig struct {
+	Labels map[string]string `yaml:"labels"` // 题注标签，键为 figure、table、equation；未配置的按 lang 使用内置文字
+
+	FigurePosition string `yaml:"figurePosition"` // 图片题注的位置: above, below
+	TablePosition  string `yaml:"tablePosition"`  // 表格题注的位置: above, below
+}
+
+// captions.figurePosition / tablePosition 可选值
+const (
+	CaptionAbove = "above"
+	CaptionBelow = "below"
+)
+
+// NetworkConfig 外部网络访问配置
+type NetworkConfig struct {
+	AllowExternal bool `yaml:"allowExternal"` // 允许把公式、流程图发送到外部在线服务渲染
+}
+
+// RenderConfig 图片类内容（公式、流程图）的渲染配置
+type RenderConfig struct {
+	Workers int `yaml:"workers"` // 并发渲染的最大任务数, 0 表示 CPU 核数, 1 表示顺序渲染
 }
 
 // ImageConfig 图片配置
 type ImageConfig struct {
-	MaxWidth        int `yaml:"maxWidth"`
-	DownloadTimeout int `yaml:"downloadTimeout"`
+	MaxWidth        int    `yaml:"maxWidth"`
+	DownloadTimeout int    `yaml:"downloadTimeout"`
+	Cache           bool   `yaml:"cache"`        // 缓存下载的网络图片
+	CacheDir        string `yaml:"cacheDir"`     // 缓存目录, 为空时使用系统临时目录下的 md2word-image-cache
+	CacheTTL        int    `yaml:"cacheTTL"`     // 缓存有效期 (秒), 响应未给出缓存头时使用; 0 表示不过期
+	CacheMaxSize    int    `yaml:"cacheMaxSize"` // 缓存目录大小上限 (MB), 超出时淘汰最早下载的图片; 0 表示不限制
+	Optimize        bool   `yaml:"optimize"`     // 把照片类 PNG 重新编码为 JPEG 以减小文件
+	JPEGQuality     int    `yaml:"jpegQuality"`  // 重新编码 JPEG 的质量 (1-100)
+	MaxPixels       int    `yaml:"maxPixels"`    // 嵌入图片的最大像素数 (宽×高), 超出时等比缩小; 0 表示不限制
+	MaxBytes        int    `yaml:"maxBytes"`     // 嵌入图片的最大字节数, 超出时重新压缩并等比缩小; 0 表示不限制
+	SVG             string `yaml:"svg"`          // SVG 图片的处理方式: embed, rasterize, reject
+	Align           string `yaml:"align"`        // 独占一段的图片的对齐方式: inline, center, left, right
+}
+
+// images.align 可选值
+const (
+	ImageAlignInline = "inline" // 不设置对齐，随正文排版
+	ImageAlignCenter = "center"
+	ImageAlignLeft   = "left"
+	ImageAlignRight  = "right"
+)
+
+// images.svg 可选值
+const (
+	ImageSVGEmbed     = "embed"     // 嵌入 SVG，能转换时附带 PNG 后备图
+	ImageSVGRasterize = "rasterize" // 只嵌入转换后的 PNG
+	ImageSVGReject    = "reject"    // 不嵌入，以占位代替
+)
+
+// PageNumberConfig 页码配置
+type PageNumberConfig struct {
+	Enabled bool   `yaml:"enabled"` // 在页脚居中显示页码
+	Format  string `yaml:"format"`  // 首节页码格式: decimal, upperRoman, lowerRoman, upperLetter, lowerLetter
+	Start   int    `yaml:"start"`   // 首节起始页码, 0 表示默认
+}
+
+// HeaderConfig 页眉配置
+type HeaderConfig struct {
+	Text           string `yaml:"text"`           // 每页居中显示的页眉文字, 为空表示无页眉
+	DifferentFirst bool   `yaml:"differentFirst"` // 首页使用单独的页眉页脚 (如封面不显示页眉和页码)
+	FirstPage      string `yaml:"firstPage"`      // 首页页眉文字, 为空时首页页眉空白; 仅 differentFirst 时生效
+	EvenAndOdd     bool   `yaml:"evenAndOdd"`     // 奇偶页不同: text 用于奇数页, evenPage 用于偶数页 (双面打印)
+	EvenPage       string `yaml:"evenPage"`       // 偶数页页眉文字, 为空时偶数页页眉空白; 仅 evenAndOdd 时生效
+}
+
+// MarginsConfig 页边距配置
+type MarginsConfig struct {
+	Mirror bool  `yaml:"mirror"` // 对称页边距: 左右边距按内侧/外侧排列, 用于双面装订
+	Gutter Twips `yaml:"gutter"` // 装订线宽度 (twips), 加在左侧 (对称时为内侧) 边距上, 内容区相应变窄
+}
+
+// LineNumbersConfig 正文行号配置
+type LineNumbersConfig struct {
+	Enabled bool   `yaml:"enabled"` // 在左侧页边距显示行号
+	CountBy int    `yaml:"countBy"` // 每隔几行显示一次行号, 0 视为 1
+	Restart string `yaml:"restart"` // 重新编号: continuous (全文连续), newPage (每页), newSection (每节); 为空表示 continuous
+}
+
+// line numbers restart 可选值
+const (
+	LineNumbersContinuous = "continuous"
+	LineNumbersNewPage    = "newPage"
+	LineNumbersNewSection = "newSection"
+)
+
+// PageConfig 页面配置
+type PageConfig struct {
+	Background  string            `yaml:"background"` // 页面背景色
+	Margins     MarginsConfig     `yaml:"margins"`
+	Header      HeaderConfig      `yaml:"header"`
+	PageNumber  PageNumberConfig  `yaml:"pageNumber"`
+	LineNumbers LineNumbersConfig `yaml:"lineNumbers"`
+}
+
+// SettingsConfig 文档设置 (settings.xml)
+type SettingsConfig struct {
+	Zoom                  int   `yaml:"zoom"`                  // 打开时的缩放比例 (%)
+	DefaultTabStop        Twips `yaml:"defaultTabStop"`        // 默认制表位间距 (twips)
+	UpdateFields          bool  `yaml:"updateFields"`          // 打开时刷新域(目录、题注编号等)，Word 会弹出确认提示
+	HideSpellingErrors    bool  `yaml:"hideSpellingErrors"`    // 隐藏拼写错误波浪线
+	HideGrammaticalErrors bool  `yaml:"hideGrammaticalErrors"` // 隐藏语法错误波浪线
+	CompatibilityMode     int   `yaml:"compatibilityMode"`     // 兼容模式: 15=Word 2013+, 14=Word 2010
+}
+
+// TOCConfig 目录/导航窗格配置
+type TOCConfig struct {
+	MaxLevel     int    `yaml:"maxLevel"`     // 进入导航窗格和目录的最深标题级别 (1-9)
+	AnchorPrefix string `yaml:"anchorPrefix"` // 标题书签名的前缀，只能包含字母、数字与下划线
+}
+
+// FontFileConfig 要嵌入的字体文件 (TrueType)
+type FontFileConfig struct {
+	Font       string `yaml:"font"`       // 字体名称，需与样式中使用的名称一致
+	Regular    string `yaml:"regular"`    // 常规字形文件路径
+	Bold       string `yaml:"bold"`       // 粗体字形文件路径
+	Italic     string `yaml:"italic"`     // 斜体字形文件路径
+	BoldItalic string `yaml:"boldItalic"` // 粗斜体字形文件路径
+}
+
+// FontsConfig 字体嵌入配置
+type FontsConfig struct {
+	Embed bool             `yaml:"embed"`
+	Files []FontFileConfig `yaml:"files"`
 }
 
 // Config 完整配置
 type Config struct {
-	Styles struct {
-		Body      StyleConfig `yaml:"body"`
-		Heading1  StyleConfig `yaml:"heading1"`
-		Heading2  StyleConfig `yaml:"heading2"`
-		Heading3  StyleConfig `yaml:"heading3"`
-		Heading4  StyleConfig `yaml:"heading4"`
-		Heading5  StyleConfig `yaml:"heading5"`
-		Heading6  StyleConfig `yaml:"heading6"`
-		Heading7  StyleConfig `yaml:"heading7"`
-		Heading8  StyleConfig `yaml:"heading8"`
-		Heading9  StyleConfig `yaml:"heading9"`
-		Code      StyleConfig `yaml:"code"`
-		CodeBlock StyleConfig `yaml:"codeBlock"`
+	Theme         string        `yaml:"theme"`         // 主题预设: light, dark
+	Lang          string        `yaml:"lang"`          // 提示、错误、占位与题注文字的语言: zh, en
+	Density       string        `yaml:"density"`       // 排版密度预设: compact, normal, relaxed
+	FontScale     float64       `yaml:"fontScale"`     // 全部字号的缩放倍数（如 1.5 用于大字版），0 表示不缩放
+	AllowRawOOXML bool          `yaml:"allowRawOOXML"` // 允许 ```ooxml 代码块原样插入文档
+	Streaming     bool          `yaml:"streaming"`     // 流式写入：元素与图片边生成边落盘，适合超大文档
+	TempDir       string        `yaml:"tempDir"`       // 渲染流程图等临时文件的目录，为空时使用系统临时目录
+	EmbedSource   bool          `yaml:"embedSource"`   // 把 Markdown 源文件存入 DOCX 的自定义 XML 部件，便于之后还原
+	Palette       PaletteConfig `yaml:"palette"`
+	Styles        struct {
+		Body      StyleConfig    `yaml:"body"`
+		Heading1  StyleConfig    `yaml:"heading1"`
+		Heading2  StyleConfig    `yaml:"heading2"`
+		Heading3  StyleConfig    `yaml:"heading3"`
+		Heading4  StyleConfig    `yaml:"heading4"`
+		Heading5  StyleConfig    `yaml:"heading5"`
+		Heading6  StyleConfig    `yaml:"heading6"`
+		Heading7  StyleConfig    `yaml:"heading7"`
+		Heading8  StyleConfig    `yaml:"heading8"`
+		Heading9  StyleConfig    `yaml:"heading9"`
+		Code      StyleConfig    `yaml:"code"`
+		CodeBlock StyleConfig    `yaml:"codeBlock"`
+		Link      LinkConfig     `yaml:"link"`
+		List      ListConfig     `yaml:"list"`
+		TaskList  TaskListConfig `yaml:"taskList"`
+		DropCap   DropCapConfig  `yaml:"dropCap"`
 	} `yaml:"styles"`
-	Table   TableConfig   `yaml:"table"`
-	Mermaid MermaidConfig `yaml:"mermaid"`
-	Math    MathConfig    `yaml:"math"`
-	Images  ImageConfig   `yaml:"images"`
+	Page          PageConfig          `yaml:"page"`
+	Settings      SettingsConfig      `yaml:"settings"`
+	Fonts         FontsConfig         `yaml:"fonts"`
+	TOC           TOCConfig           `yaml:"toc"`
+	Table         TableConfig         `yaml:"table"`
+	Paragraphs    ParagraphsConfig    `yaml:"paragraphs"`
+	Headings      HeadingsConfig      `yaml:"headings"`
+	Code          CodeConfig          `yaml:"code"`
+	Mermaid       MermaidConfig       `yaml:"mermaid"`
+	Math          MathConfig          `yaml:"math"`
+	Images        ImageConfig         `yaml:"images"`
+	Render        RenderConfig        `yaml:"render"`
+	Network       NetworkConfig       `yaml:"network"`
+	Chrome        ChromeConfig        `yaml:"chrome"`
+	Errors        ErrorsConfig        `yaml:"errors"`
+	Captions      CaptionsConfig      `yaml:"captions"`
+	Review        ReviewConfig        `yaml:"review"`
+	CriticMarkup  CriticMarkupConfig  `yaml:"criticmarkup"`
+	WikiLinks     WikiLinksConfig     `yaml:"wikiLinks"`
+	Index         IndexConfig         `yaml:"index"`
+	Variables     VariablesConfig     `yaml:"variables"`
+	Accessibility AccessibilityConfig `yaml:"accessibility"`
+
+	// Abbreviations 缩写词 → 全称：正文中首次出现时展开为 "缩写 (全称)"
+	Abbreviations map[string]string `yaml:"abbreviations"`
 }
 
 // DefaultConfig 返回默认配置
 func DefaultConfig() *Config {
+	cfg := baseConfig()
+	if err := cfg.ApplyTheme(cfg.Theme); err != nil {
+		panic(fmt.Sprintf("internal error: invalid theme in embedded default config: %v", err))
+	}
+	return cfg
+}
+
+// baseConfig 解析内嵌的 default.yaml，尚未应用主题预设
+func baseConfig() *Config {
 	var cfg Config
 	if err := yaml.Unmarshal(defaultConfigData, &cfg); err != nil {
 		// 如果解析嵌入的配置失败，这通常是编译时的错误（default.yaml内容有问题）
@@ -97,10 +408,28 @@ func LoadConfig(path string) (*Config, error) {
 		return nil, err
 	}
 
-	cfg := DefaultConfig()
+	// 用户配置叠加在内置默认配置之上，最后由主题预设补齐仍为空的颜色类配置项，
+	// 这样用户显式填写的值优先，而留空的项跟随所选主题。
+	// 排版密度预设改写的是内置默认间距，须在叠加用户配置之前应用
+	cfg := baseConfig()
+	var preset struct {
+		Density string `yaml:"density"`
+	}
+	if err := yaml.Unmarshal(data, &preset); err != nil {
+		return nil, err
+	}
+	if err := cfg.ApplyDensity(preset.Density); err != nil {
+		return nil, err
+	}
 	if err := yaml.Unmarshal(data, cfg); err != nil {
 		return nil, err
 	}
+	if err := cfg.ApplyTheme(cfg.Theme); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
 
 	return cfg, nil
 }
@@ -148,28 +477,160 @@ func LoadConfigWithFallback(customPath string) (*Config, string, error) {
 	return DefaultConfig(), used, nil
 }
 
+// frontMatter Markdown front matter 中可覆盖配置的部分
+type frontMatter struct {
+	Style yaml.Node `yaml:"style"` // 与 styles 结构相同，只需写出要覆盖的项
+}
+
+// WithFrontMatter 返回叠加了 front matter 中 style 样式覆盖的配置副本，c 本身不变；
+// 没有 style 时返回 c。front matter 的其他字段忽略
+func (c *Config) WithFrontMatter(data []byte) (*Config, error) {
+	var fm frontMatter
+	if err := yaml.Unmarshal(data, &fm); err != nil {
+		return nil, fmt.Errorf("解析 front matter 失败: %w", err)
+	}
+	if fm.Style.Kind == 0 {
+		return c, nil
+	}
+	cfg := *c
+	if err := fm.Style.Decode(&cfg.Styles); err != nil {
+		return nil, fmt.Errorf("front matter style: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("front matter style: %w", err)
+	}
+	return &cfg, nil
+}
+
+// ScaleFont 按 fontScale 缩放字号（磅）
+func (c *Config) ScaleFont(pt float64) float64 {
+	if c.FontScale <= 0 {
+		return pt
+	}
+	return pt * c.FontScale
+}
+
+// InlineCodeFont 返回行内代码的字体与字号（磅，已按 fontScale 缩放），未配置时为 Consolas 10.5
+func (c *Config) InlineCodeFont() (string, float64) {
+	font, size := c.Styles.Code.Font, float64(c.Styles.Code.Size)
+	if font == "" {
+		font = "Consolas"
+	}
+	if size == 0 {
+		size = 10.5
+	}
+	return font, c.ScaleFont(size)
+}
+
+// Validate 检查取值受限的配置项
+func (c *Config) Validate() error {
+	if c.Headings.MaxStyledLevel < 0 || c.Headings.MaxStyledLevel > 9 {
+		return fmt.Errorf("无效的 headings.maxStyledLevel: %d (应在 1~9 之间)", c.Headings.MaxStyledLevel)
+	}
+	if c.FontScale < 0 {
+		return fmt.Errorf("无效的 fontScale: %v (应大于 0)", c.FontScale)
+	}
+	if !i18n.Supported(c.Lang) {
+		return fmt.Errorf("无效的 lang: %q (可选: zh, en)", c.Lang)
+	}
+	switch c.Table.Overflow {
+	case "", TableOverflowScale, TableOverflowRotate, TableOverflowShrinkFont:
+	default:
+		return fmt.Errorf("无效的 table.overflow: %q (可选: scale, rotate, shrinkFont)", c.Table.Overflow)
+	}
+	switch c.Page.LineNumbers.Restart {
+	case "", LineNumbersContinuous, LineNumbersNewPage, LineNumbersNewSection:
+	default:
+		return fmt.Errorf("无效的 page.lineNumbers.restart: %q (可选: continuous, newPage, newSection)", c.Page.LineNumbers.Restart)
+	}
+	for _, pos := range []struct{ name, value string }{
+		{"figurePosition", c.Captions.FigurePosition},
+		{"tablePosition", c.Captions.TablePosition},
+	} {
+		switch pos.value {
+		case "", CaptionAbove, CaptionBelow:
+		default:
+			return fmt.Errorf("无效的 captions.%s: %q (可选: above, below)", pos.name, pos.value)
+		}
+	}
+	switch c.Mermaid.Format {
+	case "", MermaidFormatPNG, MermaidFormatSVG:
+	default:
+		return fmt.Errorf("无效的 mermaid.format: %q (可选: png, svg)", c.Mermaid.Format)
+	}
+	switch c.Images.SVG {
+	case "", ImageSVGEmbed, ImageSVGRasterize, ImageSVGReject:
+	default:
+		return fmt.Errorf("无效的 images.svg: %q (可选: embed, rasterize, reject)", c.Images.SVG)
+	}
+	if c.Styles.DropCap.Enabled && (c.Styles.DropCap.Lines < 1 || c.Styles.DropCap.Lines > 10) {
+		return fmt.Errorf("无效的 styles.dropCap.lines: %d (应在 1~10 之间)", c.Styles.DropCap.Lines)
+	}
+	switch c.Images.Align {
+	case "", ImageAlignInline, ImageAlignCenter, ImageAlignLeft, ImageAlignRight:
+	default:
+		return fmt.Errorf("无效的 images.align: %q (可选: inline, center, left, right)", c.Images.Align)
+	}
+	switch c.Styles.TaskList.Layout {
+	case "", TaskListInline, TaskListTable:
+	default:
+		return fmt.Errorf("无效的 styles.taskList.layout: %q (可选: inline, table)", c.Styles.TaskList.Layout)
+	}
+	for _, format := range c.Styles.List.OrderedFormats {
+		switch format {
+		case ListFormatDecimal, ListFormatLowerAlpha, ListFormatUpperAlpha, ListFormatLowerRoman, ListFormatUpperRoman:
+		default:
+			return fmt.Errorf("无效的 styles.list.orderedFormats 取值: %q (可选: decimal, lower-alpha, upper-alpha, lower-roman, upper-roman)", format)
+		}
+	}
+	if c.Page.Margins.Gutter < 0 {
+		return fmt.Errorf("无效的 page.margins.gutter: %d (不能为负数)", c.Page.Margins.Gutter)
+	}
+	for i, r := range c.TOC.AnchorPrefix {
+		if r != '_' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return fmt.Errorf("无效的 toc.anchorPrefix: %q (须以字母或下划线开头，只能包含字母、数字与下划线)", c.TOC.AnchorPrefix)
+		}
+	}
+	return nil
+}
+
+// TOCMaxLevel 返回进入大纲（导航窗格/目录）的最深标题级别，未配置或越界时为 9
+func (c *Config) TOCMaxLevel() int {
+	if c.TOC.MaxLevel <= 0 || c.TOC.MaxLevel > 9 {
+		return 9
+	}
+	return c.TOC.MaxLevel
+}
+
 // GetHeadingStyle 获取标题样式
 func (c *Config) GetHeadingStyle(level int) StyleConfig {
+	if style := c.headingStyle(level); style != nil {
+		return *style
+	}
+	return c.Styles.Body
+}
+
+// headingStyle 返回指定级别标题样式的指针，级别超出 1~9 时返回 nil
+func (c *Config) headingStyle(level int) *StyleConfig {
 	switch level {
 	case 1:
-		return c.Styles.Heading1
+		return &c.Styles.Heading1
 	case 2:
-		return c.Styles.Heading2
+		return &c.Styles.Heading2
 	case 3:
-		return c.Styles.Heading3
+		return &c.Styles.Heading3
 	case 4:
-		return c.Styles.Heading4
+		return &c.Styles.Heading4
 	case 5:
-		return c.Styles.Heading5
+		return &c.Styles.Heading5
 	case 6:
-		return c.Styles.Heading6
+		return &c.Styles.Heading6
 	case 7:
-		return c.Styles.Heading7
+		return &c.Styles.Heading7
 	case 8:
-		return c.Styles.Heading8
+		return &c.Styles.Heading8
 	case 9:
-		return c.Styles.Heading9
-	default:
-		return c.Styles.Body
+		return &c.Styles.Heading9
 	}
+	return nil
 }
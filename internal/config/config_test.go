@@ -0,0 +1,135 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestNegativeGutter(t *testing.T) {
+	if _, err := LoadConfig(writeConfig(t, []byte("page:\n  margins:\n    gutter: -1\n"))); err == nil {
+		t.Fatal("expected error for negative gutter")
+	}
+}
+
+func TestInvalidLineNumbersRestart(t *testing.T) {
+	if _, err := LoadConfig(writeConfig(t, []byte("page:\n  lineNumbers:\n    restart: never\n"))); err == nil {
+		t.Fatal("expected error for invalid restart")
+	}
+}
+
+func TestLengthUnits(t *testing.T) {
+	data := []byte(`styles:
+  body:
+    size: 14px
+    spaceBefore: 6pt
+    spaceAfter: 120
+    firstLineIndent: 2.54cm
+page:
+  margins:
+    gutter: 0.5in
+math:
+  inlineBaselineShift: "-40twip"
+settings:
+  defaultTabStop: 12.7mm
+`)
+	cfg, err := LoadConfig(writeConfig(t, data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := cfg.Styles.Body
+	if body.Size != 10.5 || body.SpaceBefore != 120 || body.SpaceAfter != 120 || body.FirstLineIndent != 1440 {
+		t.Errorf("body = size %v, before %d, after %d, indent %d", body.Size, body.SpaceBefore, body.SpaceAfter, body.FirstLineIndent)
+	}
+	if cfg.Page.Margins.Gutter != 720 {
+		t.Errorf("gutter = %d, want 720", cfg.Page.Margins.Gutter)
+	}
+	if cfg.Math.InlineBaselineShift != -2 {
+		t.Errorf("inlineBaselineShift = %v, want -2", cfg.Math.InlineBaselineShift)
+	}
+	if cfg.Settings.DefaultTabStop != 720 {
+		t.Errorf("defaultTabStop = %d, want 720", cfg.Settings.DefaultTabStop)
+	}
+}
+
+func TestInvalidLengthUnit(t *testing.T) {
+	if _, err := LoadConfig(writeConfig(t, []byte("styles:\n  body:\n    size: 12em\n"))); err == nil {
+		t.Fatal("expected error for unknown unit")
+	}
+}
+
+func TestTableWidth(t *testing.T) {
+	tests := []struct {
+		value string
+		want  TableWidth
+	}{
+		{`""`, TableWidth{}},
+		{`"100%"`, TableWidth{Percent: 100}},
+		{`"12.5 %"`, TableWidth{Percent: 12.5}},
+		{`"1in"`, TableWidth{Twips: 1440}},
+		{`8000`, TableWidth{Twips: 8000}},
+	}
+	for _, tt := range tests {
+		cfg, err := LoadConfig(writeConfig(t, []byte("table:\n  width: "+tt.value+"\n")))
+		if err != nil {
+			t.Errorf("width %s: %v", tt.value, err)
+			continue
+		}
+		if cfg.Table.Width != tt.want {
+			t.Errorf("width %s = %+v, want %+v", tt.value, cfg.Table.Width, tt.want)
+		}
+	}
+	for _, bad := range []string{`"0%"`, `"150%"`, `"wide"`} {
+		if _, err := LoadConfig(writeConfig(t, []byte("table:\n  width: "+bad+"\n"))); err == nil {
+			t.Errorf("width %s: expected error", bad)
+		}
+	}
+}
+
+func TestNegativeFontScale(t *testing.T) {
+	if _, err := LoadConfig(writeConfig(t, []byte("fontScale: -1\n"))); err == nil {
+		t.Fatal("expected error for negative fontScale")
+	}
+}
+
+func TestInvalidMaxStyledLevel(t *testing.T) {
+	for _, level := range []string{"-1", "10"} {
+		if _, err := LoadConfig(writeConfig(t, []byte("headings:\n  maxStyledLevel: "+level+"\n"))); err == nil {
+			t.Errorf("maxStyledLevel %s: expected error", level)
+		}
+	}
+}
+
+func TestWithFrontMatter(t *testing.T) {
+	base, err := LoadConfig(writeConfig(t, []byte("styles:\n  body:\n    font: \"宋体\"\n    size: 11\n  heading1:\n    font: \"黑体\"\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := base.WithFrontMatter([]byte("title: 报告\nstyle:\n  body: { font: \"Georgia\" }\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Styles.Body.Font != "Georgia" {
+		t.Errorf("body font = %q, want front matter value", cfg.Styles.Body.Font)
+	}
+	if cfg.Styles.Body.Size != 11 || cfg.Styles.Heading1.Font != "黑体" {
+		t.Error("settings not in front matter should come from the config file")
+	}
+	if base.Styles.Body.Font != "宋体" {
+		t.Error("front matter modified the base config")
+	}
+
+	if _, err := base.WithFrontMatter([]byte("style:\n  taskList: { layout: grid }\n")); err == nil {
+		t.Error("expected error for invalid style override")
+	}
+}
+
+func TestDefaultConfigHasNoUnknownKeys(t *testing.T) {
+	dec := yaml.NewDecoder(bytes.NewReader(defaultConfigData))
+	dec.KnownFields(true)
+	var cfg Config
+	if err := dec.Decode(&cfg); err != nil {
+		t.Errorf("default.yaml: %v", err)
+	}
+}
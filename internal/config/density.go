@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+	"math"
+)
+
+// DensityPreset 排版密度预设：一组协调的行高与间距，
+// 在用户配置之前应用到内置默认值上，用户显式填写的间距仍然优先。
+type DensityPreset struct {
+	BodyLineHeight  Twips   // 正文行高
+	BodySpaceAfter  Twips   // 正文段后间距
+	HeadingSpacing  float64 // 标题段前/段后间距相对内置默认值的比例
+	ListSpacing     Twips   // 列表与前后正文之间的间距
+	CodeLineHeight  Twips   // 代码块行高
+	CodeLineSpacing Twips   // 代码块行间额外间距
+}
+
+// densityPresets 内置密度预设；normal 与内置默认配置一致
+var densityPresets = map[string]DensityPreset{
+	"compact": {
+		BodyLineHeight: 276, // 1.15 倍
+		HeadingSpacing: 0.5,
+		CodeLineHeight: 240,
+	},
+	"normal": {
+		BodyLineHeight: 360,
+		HeadingSpacing: 1,
+		CodeLineHeight: 240,
+	},
+	"relaxed": {
+		BodyLineHeight:  420, // 1.75 倍
+		BodySpaceAfter:  120,
+		HeadingSpacing:  1.5,
+		ListSpacing:     120,
+		CodeLineHeight:  276,
+		CodeLineSpacing: 40,
+	},
+}
+
+// ApplyDensity 把密度预设写入配置的正文、标题、列表与代码块间距；空名称表示 normal。
+// 预设会覆盖这些项的当前值，因此应在叠加用户配置之前调用
+func (c *Config) ApplyDensity(name string) error {
+	if name == "" {
+		name = "normal"
+	}
+	preset, ok := densityPresets[name]
+	if !ok {
+		return fmt.Errorf("未知排版密度: %s (可选: compact, normal, relaxed)", name)
+	}
+
+	c.Density = name
+	c.Styles.Body.LineHeight = preset.BodyLineHeight
+	c.Styles.Body.SpaceAfter = preset.BodySpaceAfter
+	for level := 1; level <= 9; level++ {
+		h := c.headingStyle(level)
+		h.SpaceBefore = Twips(math.Round(float64(h.SpaceBefore) * preset.HeadingSpacing))
+		h.SpaceAfter = Twips(math.Round(float64(h.SpaceAfter) * preset.HeadingSpacing))
+	}
+	c.Styles.List.SpaceBefore = preset.ListSpacing
+	c.Styles.List.SpaceAfter = preset.ListSpacing
+	c.Styles.CodeBlock.LineHeight = preset.CodeLineHeight
+	c.Styles.CodeBlock.LineSpacing = preset.CodeLineSpacing
+	return nil
+}
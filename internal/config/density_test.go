@@ -0,0 +1,85 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"md2word/internal/config"
+	"md2word/internal/docx"
+)
+
+// loadDensityConfig 写出配置文件并加载
+func loadDensityConfig(t *testing.T, data string) *config.Config {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+// styleXML 返回 styles.xml 中 styleID 样式的定义
+func styleXML(t *testing.T, cfg *config.Config, styleID string) string {
+	t.Helper()
+	styles := docx.GenerateStyles(cfg)
+	i := strings.Index(styles, `w:styleId="`+styleID+`"`)
+	if i < 0 {
+		t.Fatalf("style %s not generated", styleID)
+	}
+	return styles[i : i+strings.Index(styles[i:], "</w:style>")]
+}
+
+func TestNormalDensityMatchesDefaults(t *testing.T) {
+	base := config.DefaultConfig()
+	cfg := config.DefaultConfig()
+	if err := cfg.ApplyDensity("normal"); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cfg.Styles, base.Styles) {
+		t.Error("normal density changes the built-in defaults")
+	}
+}
+
+func TestDensityPreset(t *testing.T) {
+	cfg := loadDensityConfig(t, `
+density: relaxed
+styles:
+  body:
+    lineHeight: 300
+`)
+	body := styleXML(t, cfg, docx.BodyTextStyleID)
+	if !strings.Contains(body, `<w:spacing w:before="0" w:after="120" w:line="300" w:lineRule="auto"/>`) {
+		t.Errorf("relaxed body text should keep the user line height and take the preset spacing:\n%s", body)
+	}
+	if cfg.Styles.List.SpaceBefore != 120 {
+		t.Errorf("list.spaceBefore = %d, want preset 120", cfg.Styles.List.SpaceBefore)
+	}
+	if h1 := styleXML(t, cfg, "Heading1"); !strings.Contains(h1, `<w:spacing w:before="360" w:after="180"/>`) {
+		t.Errorf("relaxed Heading1 spacing not scaled:\n%s", h1)
+	}
+
+	cfg = loadDensityConfig(t, "density: compact\n")
+	if body := styleXML(t, cfg, docx.BodyTextStyleID); !strings.Contains(body, `w:line="276"`) {
+		t.Errorf("compact body line height not applied:\n%s", body)
+	}
+	if h2 := styleXML(t, cfg, "Heading2"); !strings.Contains(h2, `<w:spacing w:before="100" w:after="50"/>`) {
+		t.Errorf("compact Heading2 spacing not scaled:\n%s", h2)
+	}
+}
+
+func TestUnknownDensity(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("density: airy\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := config.LoadConfig(path); err == nil {
+		t.Fatal("expected error for unknown density")
+	}
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ThemePreset 主题预设：一组协调的颜色/样式默认值，
+// 只填充用户配置与内置默认配置中留空的对应项。
+type ThemePreset struct {
+	HighlightStyle  string // 代码块 Chroma 样式
+	CodeBackground  string // 行内代码底色
+	CodeColor       string // 行内代码文字颜色
+	BlockBackground string // 代码块底色
+	LinkColor       string // 超链接颜色
+	BodyColor       string // 正文文字颜色
+	PageBackground  string // 页面背景色
+	MermaidTheme    string // Mermaid 主题
+}
+
+// themePresets 内置主题预设
+var themePresets = map[string]ThemePreset{
+	"light": {
+		HighlightStyle:  "github",
+		CodeBackground:  "#E8E8E8",
+		BlockBackground: "#F6F8FA",
+		LinkColor:       "#0563C1",
+		MermaidTheme:    "default",
+	},
+	"dark": {
+		HighlightStyle:  "github-dark",
+		CodeBackground:  "#343942",
+		CodeColor:       "#E6EDF3",
+		BlockBackground: "#161B22",
+		LinkColor:       "#58A6FF",
+		BodyColor:       "#E6EDF3",
+		PageBackground:  "#0D1117",
+		MermaidTheme:    "dark",
+	},
+}
+
+// ApplyTheme 将指定主题预设应用到配置中尚未设置（为空）的项；空名称表示 light
+func (c *Config) ApplyTheme(name string) error {
+	if name == "" {
+		name = "light"
+	}
+	preset, ok := themePresets[name]
+	if !ok {
+		return fmt.Errorf("未知主题: %s (可选: light, dark)", name)
+	}
+
+	c.Theme = name
+	setDefault(&c.Code.HighlightStyle, preset.HighlightStyle)
+	setDefault(&c.Styles.Code.Background, preset.CodeBackground)
+	setDefault(&c.Styles.Code.Color, preset.CodeColor)
+	setDefault(&c.Styles.CodeBlock.Background, preset.BlockBackground)
+	setDefault(&c.Styles.Link.Color, preset.LinkColor)
+	setDefault(&c.Styles.Body.Color, preset.BodyColor)
+	setDefault(&c.Page.Background, preset.PageBackground)
+	setDefault(&c.Mermaid.Theme, preset.MermaidTheme)
+	c.applyPalette()
+	return nil
+}
+
+// headingTintStep 每深一级标题向白色混合的比例，headingTintMax 为混合比例上限
+const (
+	headingTintStep = 0.1
+	headingTintMax  = 0.5
+)
+
+// applyPalette 用 palette.accent 填充未设置颜色的标题：一级标题使用强调色，
+// 之后每级逐渐变浅
+func (c *Config) applyPalette() {
+	accent, ok := parseHexColor(c.Palette.Accent)
+	if !ok {
+		return
+	}
+	for level := 1; level <= 9; level++ {
+		tint := min(float64(level-1)*headingTintStep, headingTintMax)
+		setDefault(&c.headingStyle(level).Color, tintColor(accent, tint))
+	}
+}
+
+// parseHexColor 解析 "#RRGGBB" 或 "RRGGBB"
+func parseHexColor(s string) ([3]uint8, bool) {
+	var rgb [3]uint8
+	s = strings.TrimPrefix(s, "#")
+	if len(s) != 6 {
+		return rgb, false
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return rgb, false
+	}
+	return [3]uint8{uint8(v >> 16), uint8(v >> 8), uint8(v)}, true
+}
+
+// tintColor 把颜色按比例 t (0~1) 向白色混合，返回 "#RRGGBB"
+func tintColor(rgb [3]uint8, t float64) string {
+	for i, v := range rgb {
+		rgb[i] = uint8(math.Round(float64(v) + (255-float64(v))*t))
+	}
+	return fmt.Sprintf("#%02X%02X%02X", rgb[0], rgb[1], rgb[2])
+}
+
+// setDefault 仅在 *field 为空时写入 value
+func setDefault(field *string, value string) {
+	if *field == "" {
+		*field = value
+	}
+}
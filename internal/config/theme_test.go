@@ -0,0 +1,122 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeConfig 把配置内容写入临时文件并返回路径
+func writeConfig(t *testing.T, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadShippedDefaultWithDarkTheme(t *testing.T) {
+	data := bytes.Replace(defaultConfigData, []byte(`theme: "light"`), []byte(`theme: "dark"`), 1)
+	if bytes.Equal(data, defaultConfigData) {
+		t.Fatal(`default.yaml 中未找到 theme: "light"`)
+	}
+
+	cfg, err := LoadConfig(writeConfig(t, data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dark := themePresets["dark"]
+	checks := []struct {
+		name, got, want string
+	}{
+		{"styles.body.color", cfg.Styles.Body.Color, dark.BodyColor},
+		{"page.background", cfg.Page.Background, dark.PageBackground},
+		{"styles.link.color", cfg.Styles.Link.Color, dark.LinkColor},
+		{"styles.code.background", cfg.Styles.Code.Background, dark.CodeBackground},
+		{"styles.code.color", cfg.Styles.Code.Color, dark.CodeColor},
+		{"styles.codeBlock.background", cfg.Styles.CodeBlock.Background, dark.BlockBackground},
+		{"code.highlightStyle", cfg.Code.HighlightStyle, dark.HighlightStyle},
+		{"mermaid.theme", cfg.Mermaid.Theme, dark.MermaidTheme},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s = %q, want %q", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestUserValueOverridesTheme(t *testing.T) {
+	cfg, err := LoadConfig(writeConfig(t, []byte(`
+theme: dark
+styles:
+  link:
+    color: "#FF0000"
+  code:
+    background: ""
+`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Styles.Link.Color != "#FF0000" {
+		t.Errorf("styles.link.color = %q, want user value #FF0000", cfg.Styles.Link.Color)
+	}
+	if want := themePresets["dark"].CodeBackground; cfg.Styles.Code.Background != want {
+		t.Errorf("empty styles.code.background = %q, want preset %q", cfg.Styles.Code.Background, want)
+	}
+}
+
+func TestDefaultConfigUsesLightTheme(t *testing.T) {
+	cfg := DefaultConfig()
+	light := themePresets["light"]
+	if cfg.Styles.Link.Color != light.LinkColor {
+		t.Errorf("styles.link.color = %q, want %q", cfg.Styles.Link.Color, light.LinkColor)
+	}
+	if cfg.Page.Background != "" {
+		t.Errorf("page.background = %q, want empty", cfg.Page.Background)
+	}
+}
+
+func TestUnknownTheme(t *testing.T) {
+	if _, err := LoadConfig(writeConfig(t, []byte("theme: sepia\n"))); err == nil {
+		t.Fatal("expected error for unknown theme")
+	}
+}
+
+func TestPaletteAccentColorsHeadings(t *testing.T) {
+	cfg, err := LoadConfig(writeConfig(t, []byte(`
+palette:
+  accent: "#1F4E79"
+styles:
+  heading3:
+    color: "#FF0000"
+`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checks := []struct {
+		level int
+		want  string
+	}{
+		{1, "#1F4E79"},
+		{2, "#356086"},
+		{3, "#FF0000"},
+		{9, "#8FA7BC"},
+	}
+	for _, c := range checks {
+		if got := cfg.GetHeadingStyle(c.level).Color; got != c.want {
+			t.Errorf("heading%d color = %q, want %q", c.level, got, c.want)
+		}
+	}
+	if cfg.Styles.Body.Color != "" {
+		t.Errorf("body color = %q, want empty", cfg.Styles.Body.Color)
+	}
+}
+
+func TestDefaultConfigHeadingsUncolored(t *testing.T) {
+	if got := DefaultConfig().Styles.Heading1.Color; got != "" {
+		t.Errorf("heading1 color = %q, want empty", got)
+	}
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// lengthUnits 长度单位换算为磅的系数
+var lengthUnits = map[string]float64{
+	"pt":    1,
+	"twip":  1.0 / 20,
+	"twips": 1.0 / 20,
+	"mm":    72 / 25.4,
+	"cm":    72 / 2.54,
+	"in":    72,
+	"px":    0.75, // 按 96 DPI
+}
+
+// ParseLength 解析带单位的长度（如 "10pt"、"2.5cm"、"1in"、"360twip"）并换算为磅。
+// 不带单位时 hasUnit 为 false，value 为数值本身，由调用方按字段的默认单位解释
+func ParseLength(s string) (value float64, hasUnit bool, err error) {
+	s = strings.TrimSpace(s)
+	num := strings.TrimRightFunc(s, func(r rune) bool { return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' })
+	unit := strings.ToLower(strings.TrimSpace(s[len(num):]))
+	value, err = strconv.ParseFloat(strings.TrimSpace(num), 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("无效的长度: %q", s)
+	}
+	if unit == "" {
+		return value, false, nil
+	}
+	factor, ok := lengthUnits[unit]
+	if !ok {
+		return 0, false, fmt.Errorf("无效的长度单位: %q (可选: pt, twip, mm, cm, in, px)", s)
+	}
+	return value * factor, true, nil
+}
+
+// decodeLength 解析 YAML 中的长度并换算为磅，裸数字按 bareUnit（每单位的磅数）解释
+func decodeLength(node *yaml.Node, bareUnit float64) (float64, error) {
+	if node.Kind != yaml.ScalarNode {
+		return 0, fmt.Errorf("第 %d 行: 长度应为数字或带单位的字符串", node.Line)
+	}
+	value, hasUnit, err := ParseLength(node.Value)
+	if err != nil {
+		return 0, fmt.Errorf("第 %d 行: %w", node.Line, err)
+	}
+	if !hasUnit {
+		value *= bareUnit
+	}
+	return value, nil
+}
+
+// Twips 以 twips (1/20 磅) 计的长度；配置中可写裸数字 (twips) 或带单位的字符串
+type Twips int
+
+// UnmarshalYAML 实现 yaml.Unmarshaler
+func (t *Twips) UnmarshalYAML(node *yaml.Node) error {
+	pt, err := decodeLength(node, lengthUnits["twip"])
+	if err != nil {
+		return err
+	}
+	*t = Twips(math.Round(pt * 20))
+	return nil
+}
+
+// Points 以磅计的长度或字号；配置中可写裸数字 (磅) 或带单位的字符串
+type Points float64
+
+// UnmarshalYAML 实现 yaml.Unmarshaler
+func (p *Points) UnmarshalYAML(node *yaml.Node) error {
+	pt, err := decodeLength(node, 1)
+	if err != nil {
+		return err
+	}
+	*p = Points(pt)
+	return nil
+}
+
+// TableWidth 表格宽度：页面内容宽度的百分比，或以 twips 计的长度；零值表示按内容自动
+type TableWidth struct {
+	Percent float64 // 百分比，如 100 表示占满页面内容宽度
+	Twips   Twips
+}
+
+// UnmarshalYAML 实现 yaml.Unmarshaler，接受 "100%"、"12cm" 或裸数字 (twips)，空字符串表示自动
+func (w *TableWidth) UnmarshalYAML(node *yaml.Node) error {
+	*w = TableWidth{}
+	s := strings.TrimSpace(node.Value)
+	if node.Kind == yaml.ScalarNode && s == "" {
+		return nil
+	}
+	if pct, ok := strings.CutSuffix(s, "%"); ok && node.Kind == yaml.ScalarNode {
+		value, err := strconv.ParseFloat(strings.TrimSpace(pct), 64)
+		if err != nil || value <= 0 || value > 100 {
+			return fmt.Errorf("第 %d 行: 无效的表格宽度百分比: %q (应在 0%%~100%% 之间)", node.Line, s)
+		}
+		w.Percent = value
+		return nil
+	}
+	return w.Twips.UnmarshalYAML(node)
+}
@@ -0,0 +1,113 @@
+package converter
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// abbrMatch 文本中一处待展开的缩写
+type abbrMatch struct {
+	term       string
+	start, end int
+}
+
+// expandAbbreviations 把文本中尚未展开过的缩写在首次出现处展开为 "缩写 (全称)"；
+// 较长的缩写优先，与其重叠的较短缩写不在此处展开
+func (c *Converter) expandAbbreviations(text string) string {
+	abbrs := c.config.Abbreviations
+	if len(abbrs) == 0 || c.inHeading {
+		return text
+	}
+
+	terms := make([]string, 0, len(abbrs))
+	for term := range abbrs {
+		if term != "" && !c.expandedAbbrs[term] {
+			terms = append(terms, term)
+		}
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if len(terms[i]) != len(terms[j]) {
+			return len(terms[i]) > len(terms[j])
+		}
+		return terms[i] < terms[j]
+	})
+
+	var matches []abbrMatch
+	for _, term := range terms {
+		start := findAbbreviation(text, term)
+		if start < 0 {
+			continue
+		}
+		m := abbrMatch{term: term, start: start, end: start + len(term)}
+		overlap := false
+		for _, prev := range matches {
+			if m.start < prev.end && prev.start < m.end {
+				overlap = true
+				break
+			}
+		}
+		if !overlap {
+			matches = append(matches, m)
+		}
+	}
+	if len(matches) == 0 {
+		return text
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].start < matches[j].start })
+	if c.expandedAbbrs == nil {
+		c.expandedAbbrs = make(map[string]bool)
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		b.WriteString(text[last:m.end])
+		b.WriteString(" (" + abbrs[m.term] + ")")
+		last = m.end
+		c.expandedAbbrs[m.term] = true
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// findAbbreviation 返回 term 在 text 中第一次作为独立词出现的位置：
+// 首尾为字母或数字时，相邻字符不能也是字母或数字（中日韩字符不受此限制）
+func findAbbreviation(text, term string) int {
+	for offset := 0; offset < len(text); {
+		i := strings.Index(text[offset:], term)
+		if i < 0 {
+			return -1
+		}
+		start := offset + i
+		end := start + len(term)
+		if abbrBoundary(text[:start], term, true) && abbrBoundary(text[end:], term, false) {
+			return start
+		}
+		_, size := utf8.DecodeRuneInString(text[start:])
+		offset = start + size
+	}
+	return -1
+}
+
+// abbrBoundary 判断缩写与相邻文字之间是否为词边界
+func abbrBoundary(adjacent, term string, before bool) bool {
+	var edge, next rune
+	if before {
+		edge, _ = utf8.DecodeRuneInString(term)
+		next, _ = utf8.DecodeLastRuneInString(adjacent)
+	} else {
+		edge, _ = utf8.DecodeLastRuneInString(term)
+		next, _ = utf8.DecodeRuneInString(adjacent)
+	}
+	if adjacent == "" || isWideRune(edge) {
+		return true
+	}
+	return !isWordRune(edge) || !isWordRune(next) || isWideRune(next)
+}
+
+// isWordRune 判断是否为构成单词的字母或数字
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
@@ -0,0 +1,26 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+
+	"md2word/internal/config"
+)
+
+func TestAbbreviations(t *testing.T) {
+	md := "# API 简介\n\n调用API接口前先读 REST API 文档。RAPID 不是缩写，API 也不再展开。\n\n`API` 与 API。\n"
+	doc := convertMarkdown(t, md, func(cfg *config.Config) {
+		cfg.Abbreviations = map[string]string{
+			"API":      "Application Programming Interface",
+			"REST API": "表述性状态传递接口",
+		}
+	})
+
+	texts := strings.Join(doc.texts(t), "")
+	want := "API 简介" +
+		"调用API (Application Programming Interface)接口前先读 REST API (表述性状态传递接口) 文档。RAPID 不是缩写，API 也不再展开。" +
+		"API 与 API。"
+	if texts != want {
+		t.Errorf("texts = %q\nwant    %q", texts, want)
+	}
+}
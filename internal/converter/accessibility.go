@@ -0,0 +1,67 @@
+package converter
+
+import (
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+
+	"md2word/internal/i18n"
+)
+
+// checkAccessibility 按 accessibility 配置检查 Markdown 中的无障碍问题：
+// 图片缺少替代文字、标题层级跳跃（如 H1 → H3）、表格表头行为空。
+// 每个问题输出一条警告；strict 时存在问题则返回错误
+func (c *Converter) checkAccessibility(root ast.Node) error {
+	cfg := c.config.Accessibility
+	if !cfg.Check && !cfg.Strict {
+		return nil
+	}
+	issues := 0
+	report := func(key string, args ...any) {
+		issues++
+		c.warn(key, args...)
+	}
+	lastLevel, tables := 0, 0
+	ast.Walk(root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.Heading:
+			// 第一个标题不限级别，之后每次最多深入一级
+			if lastLevel > 0 && node.Level > lastLevel+1 {
+				report(i18n.A11yHeadingSkip, lastLevel, node.Level, c.extractParagraphText(node))
+			}
+			lastLevel = node.Level
+		case *ast.Image:
+			if c.imageAltText(node) == "" {
+				report(i18n.A11yImageAlt, node.Destination)
+			}
+			return ast.WalkSkipChildren, nil
+		case *east.Table:
+			tables++
+		case *east.TableHeader:
+			if strings.TrimSpace(c.extractParagraphText(node)) == "" {
+				report(i18n.A11yTableHeader, tables)
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	if cfg.Strict && issues > 0 {
+		return c.errorf(i18n.A11yFailed, issues)
+	}
+	return nil
+}
+
+// imageAltText 返回图片的替代文字，不含 float-left: 等浮动前缀
+func (c *Converter) imageAltText(node *ast.Image) string {
+	alt := strings.TrimSpace(c.extractParagraphText(node))
+	for prefix := range imageFloatPrefixes {
+		if len(alt) >= len(prefix) && strings.EqualFold(alt[:len(prefix)], prefix) {
+			return strings.TrimSpace(alt[len(prefix):])
+		}
+	}
+	return alt
+}
@@ -0,0 +1,48 @@
+package converter
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"md2word/internal/config"
+)
+
+func TestAccessibilityCheck(t *testing.T) {
+	md := "# 标题\n\n### 跳级\n\n![](a.png)\n\n![float-left:](b.png)\n\n![示意图](c.png)\n\n|  |  |\n|--|--|\n| 1 | 2 |\n\n| 名称 | 值 |\n|--|--|\n| a | 1 |\n"
+	newConverter := func(check, strict bool) *Converter {
+		cfg := config.DefaultConfig()
+		cfg.Mermaid.Enabled = false
+		cfg.Images.Cache = false
+		cfg.Accessibility.Check = check
+		cfg.Accessibility.Strict = strict
+		return NewConverter(cfg)
+	}
+
+	c := newConverter(true, false)
+	if _, err := c.Build(context.Background(), []byte(md), t.TempDir()); err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	var issues []string
+	for _, w := range c.warnings {
+		if strings.HasPrefix(w, "无障碍") {
+			issues = append(issues, w)
+		}
+	}
+	want := []string{"H1 → H3", "a.png", "b.png", "第 1 个表格"}
+	if len(issues) != len(want) {
+		t.Fatalf("issues = %q, want %d", issues, len(want))
+	}
+	for i, w := range want {
+		if !strings.Contains(issues[i], w) {
+			t.Errorf("issue %d = %q, want it to mention %q", i, issues[i], w)
+		}
+	}
+
+	if _, err := newConverter(false, true).Build(context.Background(), []byte(md), t.TempDir()); err == nil || !strings.Contains(err.Error(), "4 个问题") {
+		t.Errorf("strict: err = %v, want failure with 4 issues", err)
+	}
+	if _, err := newConverter(false, true).Build(context.Background(), []byte("# 标题\n\n## 小节\n"), t.TempDir()); err != nil {
+		t.Errorf("strict on clean document: %v", err)
+	}
+}
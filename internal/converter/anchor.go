@@ -0,0 +1,77 @@
+package converter
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// maxBookmarkName Word 书签名的最大长度（字符数）
+const maxBookmarkName = 40
+
+// anchorNames 为标题生成书签名：以 goldmark 生成的标题 ID 为基础，
+// 加上 toc.anchorPrefix 前缀，去掉书签名中不允许的字符，
+// 重名时依次追加 _1、_2……，保证同一文档内唯一且不超过 Word 的长度限制
+type anchorNames struct {
+	prefix string
+	used   map[string]bool
+}
+
+func newAnchorNames(prefix string) *anchorNames {
+	return &anchorNames{prefix: prefix, used: make(map[string]bool)}
+}
+
+// heading 返回标题的书签名
+func (a *anchorNames) heading(h *ast.Heading) string {
+	var id string
+	if v, ok := h.AttributeString("id"); ok {
+		if b, ok := v.([]byte); ok {
+			id = strings.Trim(string(b), "-")
+		}
+	}
+	return a.unique(sanitizeBookmarkName(a.prefix + id))
+}
+
+// unique 在 base 已被占用时追加序号
+func (a *anchorNames) unique(base string) string {
+	name := truncateRunes(base, maxBookmarkName)
+	for i := 1; a.used[name]; i++ {
+		suffix := "_" + strconv.Itoa(i)
+		name = truncateRunes(base, maxBookmarkName-len(suffix)) + suffix
+	}
+	a.used[name] = true
+	return name
+}
+
+// sanitizeBookmarkName 只保留字母、数字与下划线（其余分隔字符换成下划线），
+// 不以字母或下划线开头时加前缀 h；结果为空时使用 heading
+func sanitizeBookmarkName(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			b.WriteRune(r)
+		case r == '-' || r == '.' || unicode.IsSpace(r):
+			b.WriteByte('_')
+		}
+	}
+	name := b.String()
+	if name == "" {
+		return "heading"
+	}
+	if first := []rune(name)[0]; first != '_' && !unicode.IsLetter(first) {
+		name = "h" + name
+	}
+	return name
+}
+
+// truncateRunes 把 s 截断到最多 n 个字符
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
@@ -0,0 +1,122 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"md2word/internal/i18n"
+)
+
+// FileResult 批量转换中单个文件的结果
+type FileResult struct {
+	Source string // 源文件路径
+	Output string // 输出文件路径
+	Err    error  // 转换失败的原因，成功时为 nil
+}
+
+// DirReport 批量转换的结果，按源文件路径顺序排列
+type DirReport struct {
+	Results []FileResult
+}
+
+// Failed 返回转换失败的文件
+func (r *DirReport) Failed() []FileResult {
+	var failed []FileResult
+	for _, res := range r.Results {
+		if res.Err != nil {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
+
+// Err 汇总全部失败文件的错误，全部成功时为 nil
+func (r *DirReport) Err() error {
+	var errs []error
+	for _, res := range r.Failed() {
+		errs = append(errs, fmt.Errorf("%s: %w", res.Source, res.Err))
+	}
+	return errors.Join(errs...)
+}
+
+// isMarkdownFile 判断是否为 Markdown 文件
+func isMarkdownFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".md", ".markdown":
+		return true
+	}
+	return false
+}
+
+// ConvertDir 把 srcDir 下（含子目录）的每个 Markdown 文件转换为 dstDir 下相同相对路径的 .docx：
+// 相对路径的图片相对于各自源文件所在目录解析，全部文件共用一个浏览器实例；
+// 单个文件失败不影响其余文件，结果记录在返回的 DirReport 中。
+// 只有遍历目录失败或 ctx 被取消时返回 error
+func (c *Converter) ConvertDir(ctx context.Context, srcDir, dstDir string) (*DirReport, error) {
+	absDst, err := filepath.Abs(dstDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var sources []string
+	err = filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			// 输出目录位于源目录中时不再扫描其中的文件
+			if abs, err := filepath.Abs(path); err == nil && abs == absDst && path != srcDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if isMarkdownFile(path) {
+			sources = append(sources, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.keepBrowser = true
+	defer func() {
+		c.keepBrowser = false
+		c.Close()
+	}()
+
+	report := &DirReport{}
+	for _, src := range sources {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		rel, err := filepath.Rel(srcDir, src)
+		if err != nil {
+			return report, err
+		}
+		out := filepath.Join(dstDir, strings.TrimSuffix(rel, filepath.Ext(rel))+".docx")
+		res := FileResult{Source: src, Output: out, Err: c.convertFile(ctx, src, out)}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return report, ctxErr
+		}
+		report.Results = append(report.Results, res)
+	}
+	return report, nil
+}
+
+// convertFile 读取并转换单个文件，图片相对于源文件所在目录解析
+func (c *Converter) convertFile(ctx context.Context, src, out string) error {
+	content, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(out), 0755); err != nil {
+		return c.errorf(i18n.CreateDirFailed, err)
+	}
+	return c.convert(ctx, content, filepath.Dir(src), out)
+}
@@ -0,0 +1,65 @@
+package converter
+
+import (
+	"context"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"md2word/internal/config"
+)
+
+func TestConvertDir(t *testing.T) {
+	src := t.TempDir()
+	files := map[string]string{
+		"a.md":           "# A\n",
+		"sub/b.markdown": "![图](pic.png)\n",
+		"sub/bad.md":     "{{undefined}}\n",
+		"sub/notes.txt":  "不是 Markdown",
+		"out/skipped.md": "# 输出目录中的文件不转换\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(src, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	png := makePNG(t, 20, 10, func(x, y int) color.NRGBA { return color.NRGBA{0, 200, 0, 255} })
+	if err := os.WriteFile(filepath.Join(src, "sub", "pic.png"), png, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Mermaid.Enabled = false
+	cfg.Variables.Strict = true
+	dst := filepath.Join(src, "out")
+	report, err := NewConverter(cfg).ConvertDir(context.Background(), src, dst)
+	if err != nil {
+		t.Fatalf("ConvertDir: %v", err)
+	}
+
+	if len(report.Results) != 3 {
+		t.Fatalf("results = %+v, want 3 files", report.Results)
+	}
+	failed := report.Failed()
+	if len(failed) != 1 || !strings.HasSuffix(failed[0].Source, "bad.md") {
+		t.Errorf("failed = %+v, want only bad.md", failed)
+	}
+	if report.Err() == nil {
+		t.Error("Err() = nil, want aggregated error")
+	}
+	for _, name := range []string{"a.docx", "sub/b.docx"} {
+		if _, err := os.Stat(filepath.Join(dst, name)); err != nil {
+			t.Errorf("missing output %s: %v", name, err)
+		}
+	}
+	doc := readDocx(t, filepath.Join(dst, "sub", "b.docx"))
+	if !strings.Contains(doc.document, "<w:drawing>") {
+		t.Error("relative image not resolved against the source directory")
+	}
+}
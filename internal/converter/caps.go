@@ -0,0 +1,78 @@
+package converter
+
+import (
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"md2word/internal/docx"
+)
+
+// kindCaps capsNode 的 NodeKind
+var kindCaps = ast.NewNodeKind("Caps")
+
+// capsNode HTML 中以小型大写或全部大写显示的文字，子节点为其中的内联内容
+type capsNode struct {
+	ast.BaseInline
+	small bool // true 为小型大写字母，false 为全部大写
+}
+
+// Kind 实现 ast.Node
+func (n *capsNode) Kind() ast.NodeKind {
+	return kindCaps
+}
+
+// Dump 实现 ast.Node
+func (n *capsNode) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+// htmlCaps 按 HTML 元素返回对应的大写节点：<small> 与 font-variant: small-caps 为小型大写，
+// text-transform: uppercase 为全部大写；其余返回 nil
+func htmlCaps(n *html.Node) *capsNode {
+	if n.DataAtom == atom.Small {
+		return &capsNode{small: true}
+	}
+	for _, decl := range strings.Split(htmlAttr(n, "style"), ";") {
+		prop, value, _ := strings.Cut(decl, ":")
+		prop = strings.ToLower(strings.TrimSpace(prop))
+		value = strings.ToLower(strings.TrimSpace(value))
+		switch {
+		case prop == "font-variant" && value == "small-caps":
+			return &capsNode{small: true}
+		case prop == "text-transform" && value == "uppercase":
+			return &capsNode{}
+		}
+	}
+	return nil
+}
+
+// processCaps 处理大写节点：照常处理子节点，再给新增的 Runs 加上大写格式
+func (c *Converter) processCaps(node *capsNode, p docx.RunContainer, bold, italic, code, strike bool) {
+	before := len(containerRuns(p))
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		c.processInlineNode(child, p, bold, italic, code, strike)
+	}
+	for _, run := range containerRuns(p)[before:] {
+		if node.small {
+			run.SmallCaps = true
+		} else {
+			run.AllCaps = true
+		}
+	}
+}
+
+// containerRuns 返回 p 中已有的文本运行
+func containerRuns(p docx.RunContainer) []*docx.Run {
+	switch c := p.(type) {
+	case *docx.Paragraph:
+		return c.Runs()
+	case *docx.Hyperlink:
+		return c.Runs
+	case *docx.Revision:
+		return c.Runs
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package converter
+
+import (
+	"slices"
+
+	"md2word/internal/config"
+	"md2word/internal/docx"
+	"md2word/internal/i18n"
+)
+
+// 题注的内容类型，对应 captions.labels 的键
+const (
+	captionFigure   = "figure"
+	captionTable    = "table"
+	captionEquation = "equation"
+)
+
+// captionMessages captions.labels 未配置时使用的标签消息
+var captionMessages = map[string]string{
+	captionFigure:   i18n.CaptionFigure,
+	captionTable:    i18n.CaptionTable,
+	captionEquation: i18n.CaptionEquation,
+}
+
+// captionLabel 返回 kind 类题注编号前的标签：captions.labels 优先，其次为 lang 对应的内置文字。
+// 所有带编号的题注都应从这里取标签
+func (c *Converter) captionLabel(kind string) string {
+	if label := c.config.Captions.Labels[kind]; label != "" {
+		return label
+	}
+	return c.msg(captionMessages[kind])
+}
+
+// captionAbove 判断 kind 类题注是否位于内容上方：按 captions.figurePosition / tablePosition，
+// 未配置时表格题注在上、图片题注在下
+func (c *Converter) captionAbove(kind string) bool {
+	switch kind {
+	case captionTable:
+		return c.config.Captions.TablePosition != config.CaptionBelow
+	case captionFigure:
+		return c.config.Captions.FigurePosition == config.CaptionAbove
+	}
+	return false
+}
+
+// addCaptioned 把内容段落与题注段落按题注位置依次加入文档。
+// 前面的段落都设为与下段同页、题注段内不分页，避免题注与内容被分页拆开
+func (c *Converter) addCaptioned(kind string, content, captions []*docx.Paragraph) {
+	paras := slices.Concat(content, captions)
+	if c.captionAbove(kind) {
+		paras = slices.Concat(captions, content)
+	}
+	for _, p := range captions {
+		p.KeepLines = true
+	}
+	for i, p := range paras {
+		p.KeepNext = i < len(paras)-1
+		c.doc.AddParagraph(p)
+	}
+}
@@ -0,0 +1,63 @@
+package converter
+
+import (
+	"encoding/base64"
+	"image/color"
+	"strings"
+	"testing"
+
+	"md2word/internal/config"
+)
+
+func TestCaptionLabel(t *testing.T) {
+	tests := []struct {
+		lang   string
+		labels map[string]string
+		want   [3]string
+	}{
+		{"", nil, [3]string{"图", "表", "公式"}},
+		{"en", nil, [3]string{"Figure", "Table", "Equation"}},
+		{"en", map[string]string{"table": "Tableau", "equation": "Équation"}, [3]string{"Figure", "Tableau", "Équation"}},
+	}
+	for _, tt := range tests {
+		cfg := config.DefaultConfig()
+		cfg.Lang = tt.lang
+		cfg.Captions.Labels = tt.labels
+		c := NewConverter(cfg)
+		for i, kind := range []string{captionFigure, captionTable, captionEquation} {
+			if got := c.captionLabel(kind); got != tt.want[i] {
+				t.Errorf("lang %q labels %v: captionLabel(%s) = %q, want %q", tt.lang, tt.labels, kind, got, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestFigureCaptionPosition(t *testing.T) {
+	png := makePNG(t, 20, 10, func(x, y int) color.NRGBA { return color.NRGBA{0, 0, 200, 255} })
+	md := "<figure>\n<img src=\"data:image/png;base64," + base64.StdEncoding.EncodeToString(png) + "\">\n" +
+		"<figcaption>示意图</figcaption>\n</figure>\n"
+	for _, pos := range []string{config.CaptionBelow, config.CaptionAbove} {
+		t.Run(pos, func(t *testing.T) {
+			doc := convertMarkdown(t, md, func(cfg *config.Config) { cfg.Captions.FigurePosition = pos })
+			above := strings.Index(doc.document, "Caption") < strings.Index(doc.document, "<w:drawing>")
+			if above != (pos == config.CaptionAbove) {
+				t.Errorf("caption above image = %v", above)
+			}
+			// 只有前一段与下段同页，题注段内不分页
+			if n := strings.Count(doc.document, "<w:keepNext/>"); n != 1 {
+				t.Errorf("keepNext count = %d, want 1", n)
+			}
+			if n := strings.Count(doc.document, "<w:keepLines/>"); n != 1 {
+				t.Errorf("keepLines count = %d, want 1", n)
+			}
+		})
+	}
+}
+
+func TestInvalidCaptionPosition(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Captions.TablePosition = "left"
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error for invalid captions.tablePosition")
+	}
+}
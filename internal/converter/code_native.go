@@ -10,8 +10,9 @@ import (
 	"github.com/alecthomas/chroma/v2/styles"
 )
 
-// HighlightCodeNative 使用Chroma将代码转换为具有高亮效果的DOCX段落并添加到单元格中
-func HighlightCodeNative(cell *docx.TableCell, code, language, fontName string, fontSize float64, lineSpacing, lineHeight int) error {
+// HighlightCodeNative 使用Chroma将代码转换为具有高亮效果的DOCX段落并添加到单元格中。
+// 每行一个段落，引用 paraStyle（字体、字号、行距由样式提供），run 只带高亮的颜色与粗斜体
+func HighlightCodeNative(cell *docx.TableCell, code, language, styleName, paraStyle string) error {
 	// 获取lexer
 	lexer := lexers.Get(language)
 	if lexer == nil {
@@ -20,7 +21,10 @@ func HighlightCodeNative(cell *docx.TableCell, code, language, fontName string,
 	lexer = chroma.Coalesce(lexer)
 
 	// 获取样式
-	style := styles.Get("github")
+	if styleName == "" {
+		styleName = "github"
+	}
+	style := styles.Get(styleName)
 	if style == nil {
 		style = styles.Fallback
 	}
@@ -32,10 +36,7 @@ func HighlightCodeNative(cell *docx.TableCell, code, language, fontName string,
 	}
 
 	// 创建初始段落
-	p := docx.NewParagraph("")
-	p.SpacingA = lineSpacing / 2
-	p.SpacingB = lineSpacing / 2
-	p.LineHeight = lineHeight
+	p := docx.NewParagraph(paraStyle)
 	cell.AddParagraph(p)
 
 	for _, token := range iterator.Tokens() {
@@ -46,17 +47,12 @@ func HighlightCodeNative(cell *docx.TableCell, code, language, fontName string,
 		for i, lineText := range lines {
 			if i > 0 {
 				// 换行，创建新段落
-				p = docx.NewParagraph("")
-				p.SpacingA = lineSpacing / 2
-				p.SpacingB = lineSpacing / 2
-				p.LineHeight = lineHeight
+				p = docx.NewParagraph(paraStyle)
 				cell.AddParagraph(p)
 			}
 
 			if lineText != "" {
 				run := p.AddRun(lineText)
-				run.FontName = fontName
-				run.FontSize = fontSize
 
 				// 映射Chroma颜色到RGB
 				if entry.Colour.IsSet() {
@@ -4,31 +4,38 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"image"
 	_ "image/gif"
 	_ "image/jpeg"
 	_ "image/png"
-	"io"
+	"math"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/chromedp/chromedp"
 	"github.com/yuin/goldmark/ast"
 	east "github.com/yuin/goldmark/extension/ast"
 	goldmarkText "github.com/yuin/goldmark/text"
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/tiff"
 
 	"md2word/internal/config"
 	"md2word/internal/docx"
+	"md2word/internal/i18n"
 	"md2word/internal/parser"
 )
 
 // Converter Markdown到DOCX转换器
 type Converter struct {
 	config    *config.Config
+	ctx       context.Context // 本次转换的上下文，由 Convert 设置
 	doc       *docx.Document
 	parser    *parser.MarkdownParser
 	source    []byte
@@ -39,45 +46,208 @@ type Converter struct {
 	// Chromedp 资源
 	chromeCtx    context.Context
 	chromeCancel context.CancelFunc
+	keepBrowser  bool // 转换结束后保留浏览器供下一个文件使用
+
+	// 本次转换读取的本地图片文件，监视模式据此决定监视哪些文件
+	localFiles []string
 
 	// 编号状态跟踪
 	numberingState *docx.NumberingState
+
+	// 缩进代码块重新解析的当前嵌套深度
+	reparseDepth int
+
+	// 预渲染的公式与流程图图片，键为 renderJob.key()
+	renders map[string]*renderResult
+
+	// comment 指令登记的批注ID，挂到下一个段落或标题上
+	pendingComments []int
+
+	// color 指令登记的颜色，应用到下一个正文段落
+	pendingColor *paragraphColor
+
+	// border 指令登记的边框，应用到下一个正文段落
+	pendingBorder *docx.ParagraphBorder
+
+	// 本次转换的修订时间 (ISO 8601)，CriticMarkup 修订共用
+	revisionDate string
+
+	// WikiLink 目标标题：标题节点 → 书签名，以及链接目标 → 书签名
+	headingBookmarks map[*ast.Heading]string
+	wikiAnchors      map[string]string
+
+	// {{name}} 占位符的替换值，以及 variables.strict 下遇到的第一个未定义变量
+	variables   map[string]string
+	variableErr error
+
+	// 已展开过的缩写；处理标题时不展开
+	expandedAbbrs map[string]bool
+	inHeading     bool
+
+	// 本次转换的结果信息，见 ConvertWithResult
+	title     string
+	warnings  []string
+	durations map[string]time.Duration
+
+	// styles.dropCap 下，一级标题之后尚未遇到正文段落
+	dropCapPending bool
+
+	// 上一个顶层有序列表的最后序号，styles.list.continueNumbering 下用于接续编号；遇到标题时清零
+	lastOrdered int
+
+	// 网络图片磁盘缓存，首次下载时创建
+	imageCache     *imageCache
+	imageCacheOnce sync.Once
 }
 
+// maxReparseDepth 缩进代码块重新解析为 Markdown 的最大嵌套深度，超出后按代码块渲染
+const maxReparseDepth = 8
+
 // Element 文档元素接口
 type Element interface {
 	ToXML() string
 }
 
+// newMarkdownParser 按配置创建 Markdown 解析器
+func newMarkdownParser(cfg *config.Config) *parser.MarkdownParser {
+	var opts []parser.Option
+	if cfg.CriticMarkup.Enabled {
+		opts = append(opts, parser.WithCriticMarkup())
+	}
+	if cfg.WikiLinks.Enabled {
+		opts = append(opts, parser.WithWikiLinks())
+	}
+	if cfg.Index.Enabled {
+		opts = append(opts, parser.WithIndex())
+	}
+	return parser.NewMarkdownParser(opts...)
+}
+
 // NewConverter 创建新的转换器
 func NewConverter(cfg *config.Config) *Converter {
 	return &Converter{
 		config:         cfg,
-		parser:         parser.NewMarkdownParser(),
+		parser:         newMarkdownParser(cfg),
 		elements:       make([]Element, 0),
 		numberingState: docx.NewNumberingState(),
 	}
 }
 
 // Convert 转换Markdown到DOCX
-func (c *Converter) Convert(content []byte, outputPath string) error {
-	c.source = content
-	c.basePath = filepath.Dir(outputPath)
+// ctx 取消时中止渲染与图片下载，删除不完整的输出并返回 ctx.Err()
+func (c *Converter) Convert(ctx context.Context, content []byte, outputPath string) error {
+	return c.convert(ctx, content, filepath.Dir(outputPath), outputPath)
+}
+
+// convert 转换并保存，basePath 为解析相对路径图片的目录
+func (c *Converter) convert(ctx context.Context, content []byte, basePath, outputPath string) error {
+	if err := c.config.Validate(); err != nil {
+		return err
+	}
+	markdown, restore, err := c.applyFrontMatter(content)
+	if err != nil {
+		return err
+	}
+	defer restore()
+	c.doc = docx.NewDocument(c.config)
+	if c.config.EmbedSource {
+		c.doc.EmbedSource(content)
+	}
+	if c.config.Streaming {
+		if err := c.doc.StartStreaming(outputPath); err != nil {
+			return err
+		}
+	}
+	if err := c.build(ctx, markdown, basePath); err != nil {
+		return err
+	}
+
+	// 保存文档
+	c.timed("save", func() { err = c.doc.Save(outputPath) })
+	return err
+}
+
+// Build 转换Markdown但不保存，返回生成的文档元素供检查或序列化（见 docx.MarshalElements）
+// basePath 为解析相对路径图片的目录；streaming 配置被忽略
+func (c *Converter) Build(ctx context.Context, content []byte, basePath string) ([]docx.Element, error) {
+	if err := c.config.Validate(); err != nil {
+		return nil, err
+	}
+	content, restore, err := c.applyFrontMatter(content)
+	if err != nil {
+		return nil, err
+	}
+	defer restore()
 	c.doc = docx.NewDocument(c.config)
+	if err := c.build(ctx, content, basePath); err != nil {
+		return nil, err
+	}
+	return c.doc.Elements(), nil
+}
 
-	// 在转换结束时关闭浏览器
-	defer c.Close()
+// Elements 返回最近一次转换生成的文档元素；流式模式下为 nil
+func (c *Converter) Elements() []docx.Element {
+	if c.doc == nil {
+		return nil
+	}
+	return c.doc.Elements()
+}
+
+// build 解析 content 并把元素写入 c.doc；失败时放弃流式输出
+func (c *Converter) build(ctx context.Context, content []byte, basePath string) error {
+	c.ctx = ctx
+	c.source = content
+	c.basePath = basePath
+	c.revisionDate = time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	c.expandedAbbrs = nil
+	c.variableErr = nil
+	c.localFiles = nil
+	c.lastOrdered = 0
+	c.dropCapPending = false
+	c.pendingColor = nil
+	c.pendingBorder = nil
+	c.warnings = nil
+	c.durations = make(map[string]time.Duration)
+
+	// 在转换结束时关闭浏览器；批量转换时由 ConvertDir 在全部文件完成后关闭
+	if !c.keepBrowser {
+		defer c.Close()
+	}
 
 	// 解析Markdown
-	root := c.parser.Parse(content)
+	var root ast.Node
+	c.timed("parse", func() { root = c.parser.Parse(content) })
+	c.title = c.documentTitle(root)
+	if err := c.checkAccessibility(root); err != nil {
+		c.doc.Abort()
+		return err
+	}
 
-	// 遍历AST
-	if err := c.walkNode(root); err != nil {
-		return fmt.Errorf("转换失败: %w", err)
+	c.collectWikiAnchors(root)
+
+	// 并发渲染公式与流程图
+	c.timed("render", func() { c.prerender(ctx, root) })
+	if err := ctx.Err(); err != nil {
+		c.doc.Abort()
+		return err
 	}
 
-	// 保存文档
-	return c.doc.Save(outputPath)
+	// 遍历AST
+	var err error
+	c.timed("build", func() { err = c.walkNode(root) })
+	if err != nil {
+		c.doc.Abort()
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return c.errorf(i18n.ConvertFailed, err)
+	}
+	c.flushPendingComments()
+	if c.variableErr != nil {
+		c.doc.Abort()
+		return c.variableErr
+	}
+	return nil
 }
 
 // Close 关闭转换器并释放资源
@@ -106,9 +276,10 @@ func (c *Converter) ensureChrome() (context.Context, error) {
 		chromedp.NoDefaultBrowserCheck,
 		chromedp.Headless,
 		chromedp.DisableGPU,
-		chromedp.Flag("no-sandbox", true),
-		chromedp.Flag("disable-web-security", true),
 	)
+	for name, value := range chromeFlags(c.config.Chrome) {
+		opts = append(opts, chromedp.Flag(name, value))
+	}
 
 	allocCtx, cancel := chromedp.NewExecAllocator(context.Background(), opts...)
 
@@ -125,9 +296,37 @@ func (c *Converter) ensureChrome() (context.Context, error) {
 	return c.chromeCtx, nil
 }
 
+// chromeFlags 按 chrome 配置生成额外的启动参数：chrome.noSandbox 未设置时仅在以 root 运行时关闭沙箱
+// （Chrome 拒绝以 root 身份在沙箱中启动）；chrome.flags 中的 "--name=value" 或 "--name" 逐项加入
+func chromeFlags(cfg config.ChromeConfig) map[string]any {
+	flags := make(map[string]any)
+	noSandbox := os.Geteuid() == 0
+	if cfg.NoSandbox != nil {
+		noSandbox = *cfg.NoSandbox
+	}
+	if noSandbox {
+		flags["no-sandbox"] = true
+	}
+	for _, flag := range cfg.Flags {
+		name, value, ok := strings.Cut(strings.TrimLeft(flag, "-"), "=")
+		if name == "" {
+			continue
+		}
+		if ok {
+			flags[name] = value
+		} else {
+			flags[name] = true
+		}
+	}
+	return flags
+}
+
 // walkNode 遍历AST节点
 func (c *Converter) walkNode(n ast.Node) error {
 	for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+		if err := c.ctx.Err(); err != nil {
+			return err
+		}
 		if err := c.processNode(child); err != nil {
 			return err
 		}
@@ -149,13 +348,19 @@ func (c *Converter) processNode(n ast.Node) error {
 	case *ast.CodeBlock:
 		return c.processCodeBlock(node)
 	case *ast.List:
-		return c.processList(node, 0)
+		if c.config.Styles.TaskList.Layout == config.TaskListTable && isTaskList(node) {
+			c.processTaskListTable(node)
+			return nil
+		}
+		return c.processList(node, 0, true)
 	case *ast.Blockquote:
 		return c.processBlockquote(node)
 	case *ast.ThematicBreak:
 		return c.processThematicBreak()
 	case *east.Table:
 		return c.processTable(node)
+	case *ast.HTMLBlock:
+		return c.processHTMLBlock(node)
 	default:
 		// 处理其他节点类型
 		return c.walkNode(n)
@@ -169,9 +374,18 @@ func (c *Converter) processHeading(node *ast.Heading) error {
 		level = 9
 	}
 
+	c.inHeading = true
+	defer func() { c.inHeading = false }()
+	c.lastOrdered = 0
+	c.dropCapPending = level == 1 && c.config.Styles.DropCap.Enabled
+	if limit := c.config.Headings.MaxStyledLevel; limit > 0 && level > limit {
+		c.processDemotedHeading(node)
+		return nil
+	}
+
 	styleID := fmt.Sprintf("Heading%d", level)
 	p := docx.NewParagraph(styleID)
-	p.LineHeight = c.config.Styles.Body.LineHeight
+	p.LineHeight = int(c.config.Styles.Body.LineHeight)
 
 	// 提取标题文本 - 修复文本提取逻辑
 	var headingText strings.Builder
@@ -204,6 +418,7 @@ func (c *Converter) processHeading(node *ast.Heading) error {
 		// 使用移除编号后的标题文本
 		cleanRun := p.AddRun(parsedNum.Text)
 		cleanRun.Bold = c.config.GetHeadingStyle(level).Bold
+		cleanRun.Italic = c.config.GetHeadingStyle(level).Italic
 
 		// 更新编号状态
 		numState.UpdateNumberingState(parsedNum)
@@ -212,16 +427,40 @@ func (c *Converter) processHeading(node *ast.Heading) error {
 		c.processInlineNodes(node, p)
 	}
 
+	if name, ok := c.headingBookmarks[node]; ok {
+		p.AddBookmark(c.doc.NextAnnotationID(), name)
+	}
+	c.attachPendingComments(p)
 	c.doc.AddParagraph(p)
 	return nil
 }
 
+// processDemotedHeading 把超过 headings.maxStyledLevel 的标题输出为加粗的正文段落：
+// 不引用标题样式、没有大纲级别，因而不出现在导航窗格和目录中
+func (c *Converter) processDemotedHeading(node *ast.Heading) {
+	p := docx.NewParagraph(c.bodyStyleID())
+	p.KeepNext = c.config.Headings.KeepWithNext
+	c.processInlineNodes(node, p)
+	for _, run := range p.Runs() {
+		run.Bold = true
+	}
+	if name, ok := c.headingBookmarks[node]; ok {
+		p.AddBookmark(c.doc.NextAnnotationID(), name)
+	}
+	c.attachPendingComments(p)
+	c.doc.AddParagraph(p)
+}
+
 // extractTextFromNode 递归提取节点中的所有文本
 func (c *Converter) extractTextFromNode(node ast.Node, builder *strings.Builder) {
 	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
 		switch n := child.(type) {
 		case *ast.Text:
-			builder.WriteString(string(n.Segment.Value(c.source)))
+			builder.WriteString(decodeEntities(string(n.Segment.Value(c.source))))
+		case *ast.String:
+			builder.WriteString(decodeEntities(string(n.Value)))
+		case *parser.Variable:
+			builder.WriteString(c.variableText(n))
 		case *ast.Emphasis:
 			// 处理加粗/斜体标记，继续提取内部文本
 			c.extractTextFromNode(n, builder)
@@ -232,32 +471,46 @@ func (c *Converter) extractTextFromNode(node ast.Node, builder *strings.Builder)
 	}
 }
 
+// bodyStyleID 返回正文段落引用的样式 ID
+func (c *Converter) bodyStyleID() string {
+	if id := c.config.Paragraphs.Style; id != "" {
+		return id
+	}
+	return docx.BodyTextStyleID
+}
+
 // processParagraph 处理段落
 func (c *Converter) processParagraph(node *ast.Paragraph) error {
-	p := docx.NewParagraph("")
+	// 间距与缩进由正文样式提供，不写直接格式，便于在 Word 中统一修改
+	p := docx.NewParagraph(c.bodyStyleID())
+	c.processInlineContent(node, p)
+
+	// 段落有内容（子元素）时添加到文档；paragraphs.keepEmpty 时空段落也保留，用于留白
+	if len(p.Children) > 0 || c.config.Paragraphs.KeepEmpty {
+		if c.dropCapPending && len(p.Children) > 0 {
+			c.dropCapPending = false
+			if dropCap := c.splitDropCap(p); dropCap != nil {
+				c.doc.AddParagraph(dropCap)
+			}
+		}
+		c.applyPendingColor(p)
+		c.applyPendingBorder(p)
+		c.attachPendingComments(p)
+		c.doc.AddParagraph(p)
+	}
 
-	// 应用正文配置
-	p.SpacingA = c.config.Styles.Body.SpaceBefore
-	p.SpacingB = c.config.Styles.Body.SpaceAfter
-	p.LineHeight = c.config.Styles.Body.LineHeight
-	p.FirstLineIndent = c.config.Styles.Body.FirstLineIndent
+	return nil
+}
 
-	// 首先检查段落是否包含数学公式
-	paragraphText := c.extractParagraphText(node)
-	if strings.Contains(paragraphText, "$") {
+// processInlineContent 处理段落、列表项或表格单元格的内联内容，含 $...$ 时把行内公式渲染为图片
+func (c *Converter) processInlineContent(node ast.Node, p *docx.Paragraph) {
+	if strings.Contains(c.extractParagraphText(node), "$") {
 		// 包含公式，使用特殊处理
 		c.processParagraphWithFormulas(node, p)
 	} else {
 		// 不包含公式，正常处理内联节点
 		c.processInlineNodes(node, p)
 	}
-
-	// 如果段落有内容（子元素），则添加到文档
-	if len(p.Children) > 0 {
-		c.doc.AddParagraph(p)
-	}
-
-	return nil
 }
 
 // extractParagraphText 提取段落的完整文本内容
@@ -293,7 +546,7 @@ func (c *Converter) processParagraphWithFormulas(node ast.Node, p *docx.Paragrap
 		}
 		
 		// 处理公式
-		imgData, err := RenderMathJax(formula.Formula, false)
+		imgData, err := c.renderedImage(renderJob{kind: renderMath, source: formula.Formula})
 		if err == nil && len(imgData) > 0 {
 			width, height := c.getImageDimensions(imgData)
 			if width > 0 && height > 0 {
@@ -301,14 +554,18 @@ func (c *Converter) processParagraphWithFormulas(node ast.Node, p *docx.Paragrap
 				displayW, displayH := c.calculateFormulaSize(width, height, true) // true表示行内公式
 				
 				rID := c.doc.AddImage(imgData, "image/png", width, height)
-				p.AddImageRun(rID, int64(displayW)*9525, int64(displayH)*9525)
+				run := p.AddImageRun(rID, int64(displayW)*9525, int64(displayH)*9525)
+				// math.inlineBaselineShift 以磅为单位，w:position 以半磅为单位
+				run.Position = int(math.Round(float64(c.config.Math.InlineBaselineShift) * 2))
 			} else {
 				// 尺寸异常，作为文本处理
-				p.AddRun("$" + formula.Formula + "$")
+				c.warn(i18n.MathSizeInvalid, formula.Formula)
+				c.addPlaceholderRun(p, placeholderMath, "$"+formula.Formula+"$")
 			}
 		} else {
 			// 渲染失败，作为文本处理
-			p.AddRun("$" + formula.Formula + "$")
+			c.warn(i18n.MathFailed, formula.Formula, err)
+			c.addPlaceholderRun(p, placeholderMath, "$"+formula.Formula+"$")
 		}
 		
 		lastEnd = formula.End
@@ -382,6 +639,7 @@ func (c *Converter) parseInlineFormulas(text string) []FormulaInfo {
 func (c *Converter) processTextBlock(node *ast.TextBlock) error {
 	p := docx.NewParagraph("")
 	c.processInlineNodes(node, p)
+	c.attachPendingComments(p)
 	c.doc.AddParagraph(p)
 	return nil
 }
@@ -398,27 +656,16 @@ func (c *Converter) processInlineNode(n ast.Node, p docx.RunContainer, bold, ita
 	switch node := n.(type) {
 	case *ast.Text:
 		text := string(node.Segment.Value(c.source))
-		if code {
-			run := p.AddRun(text)
-			run.IsCode = true
-			run.FontName = c.config.Styles.Code.Font
-			if run.FontName == "" {
-				run.FontName = "Consolas"
-			}
-			run.FontSize = c.config.Styles.Code.Size
-			if run.FontSize == 0 {
-				run.FontSize = 10.5
-			}
-			if c.config.Styles.Code.Color != "" {
-				run.Color = strings.TrimPrefix(c.config.Styles.Code.Color, "#")
-			}
-		} else {
-			// 对于普通文本，直接添加（公式已在段落级别处理）
-			run := p.AddRun(text)
-			run.Bold = bold
-			run.Italic = italic
-			run.Strike = strike
+		if !code {
+			text = unescapePunctuation(text)
+		}
+		if node.SoftLineBreak() {
+			text += c.softLineBreak(node, text)
 		}
+		c.addTextRun(p, text, bold, italic, code, strike)
+	case *ast.String:
+		// HTML 块转换出的文本不在源文件中
+		c.addTextRun(p, string(node.Value), bold, italic, code, strike)
 	case *ast.Emphasis:
 		level := node.Level
 		for child := node.FirstChild(); child != nil; child = child.NextSibling() {
@@ -437,11 +684,9 @@ func (c *Converter) processInlineNode(n ast.Node, p docx.RunContainer, bold, ita
 				c.processInlineNode(child, link, bold, italic, code, strike)
 			}
 			// 处理完所有子节点后，统一给 link 的 Runs 加上超链接样式
-			for _, run := range link.Runs {
-				if run.Color == "" {
-					run.Color = "0563C1" // Word 默认链接蓝
-				}
-				run.Underline = true
+			c.styleLinkRuns(link)
+			if !strings.HasPrefix(url, "#") {
+				c.addExternalIndicator(para)
 			}
 		} else {
 			// 嵌套链接（不被支持），作为普通文本处理
@@ -449,8 +694,38 @@ func (c *Converter) processInlineNode(n ast.Node, p docx.RunContainer, bold, ita
 				c.processInlineNode(child, p, bold, italic, code, strike)
 			}
 		}
+	case *ast.AutoLink:
+		// GFM 自动链接: <https://...>、www.example.com、裸邮箱地址
+		label := string(node.Label(c.source))
+		if para, ok := p.(*docx.Paragraph); ok {
+			rID := c.doc.AddHyperlink(autoLinkTarget(node, c.source))
+			link := para.AddHyperlink(rID)
+			run := link.AddRun(label)
+			run.Bold = bold
+			run.Italic = italic
+			run.Strike = strike
+			c.styleLinkRuns(link)
+			c.addExternalIndicator(para)
+		} else {
+			run := p.AddRun(label)
+			run.Bold = bold
+			run.Italic = italic
+			run.Strike = strike
+		}
 	case *ast.Image:
 		c.processImage(node, p)
+	case *ast.RawHTML:
+		c.inlineComment(node, p)
+	case *parser.CriticMarkup:
+		c.processCriticMarkup(node, p, bold, italic, code, strike)
+	case *capsNode:
+		c.processCaps(node, p, bold, italic, code, strike)
+	case *parser.WikiLink:
+		c.processWikiLink(node, p, bold, italic, code, strike)
+	case *parser.IndexEntry:
+		c.processIndexEntry(node, p)
+	case *parser.Variable:
+		c.addTextRun(p, c.variableText(node), bold, italic, code, strike)
 	case *east.Strikethrough:
 		for child := node.FirstChild(); child != nil; child = child.NextSibling() {
 			c.processInlineNode(child, p, bold, italic, code, true)
@@ -458,11 +733,161 @@ func (c *Converter) processInlineNode(n ast.Node, p docx.RunContainer, bold, ita
 	}
 }
 
+// softLineBreak 返回段内软换行替换成的文本：保留换行时为 \n（输出为 <w:br/>），
+// 两侧均为中日韩字符时为空，其余为空格
+func (c *Converter) softLineBreak(node *ast.Text, text string) string {
+	if c.config.Paragraphs.PreserveLineBreaks {
+		return "\n"
+	}
+	if c.config.Paragraphs.CJKLineBreak {
+		before, _ := utf8.DecodeLastRuneInString(text)
+		if isWideRune(before) && isWideRune(firstRuneAfter(node, c.source)) {
+			return ""
+		}
+	}
+	return " "
+}
+
+// firstRuneAfter 返回节点之后第一个文本字符，没有时为 0
+func firstRuneAfter(n ast.Node, source []byte) rune {
+	for next := n.NextSibling(); next != nil; next = next.NextSibling() {
+		for leaf := next; leaf != nil; leaf = leaf.FirstChild() {
+			var value []byte
+			switch t := leaf.(type) {
+			case *ast.Text:
+				value = t.Segment.Value(source)
+			case *ast.String:
+				value = t.Value
+			default:
+				continue
+			}
+			if len(value) > 0 {
+				r, _ := utf8.DecodeRune(value)
+				return r
+			}
+			break
+		}
+	}
+	return 0
+}
 
+// addTextRun 以给定格式添加一段文本
+func (c *Converter) addTextRun(p docx.RunContainer, text string, bold, italic, code, strike bool) {
+	if code {
+		// 字体、字号、颜色与底色由 CodeChar 字符样式提供
+		p.AddRun(text).IsCode = true
+		return
+	}
+	// 对于普通文本，直接添加（公式已在段落级别处理）
+	run := p.AddRun(c.expandAbbreviations(decodeEntities(text)))
+	run.Bold = bold
+	run.Italic = italic
+	run.Strike = strike
+}
+
+// linkColor 返回超链接文字颜色
+func (c *Converter) linkColor() string {
+	if c.config.Styles.Link.Color != "" {
+		return strings.TrimPrefix(c.config.Styles.Link.Color, "#")
+	}
+	return "0563C1" // Word 默认链接蓝
+}
+
+// styleLinkRuns 按 styles.link 给超链接的 Runs 加上颜色与下划线，已有颜色（如代码高亮）的保留
+func (c *Converter) styleLinkRuns(link *docx.Hyperlink) {
+	for _, run := range link.Runs {
+		if run.Color == "" {
+			run.Color = c.linkColor()
+		}
+		run.Underline = c.config.Styles.Link.Underline
+	}
+}
+
+// addExternalIndicator 在外部链接后追加 styles.link.externalIndicator 标记，
+// 标记在链接之外，使用链接颜色但不加下划线
+func (c *Converter) addExternalIndicator(p *docx.Paragraph) {
+	if c.config.Styles.Link.ExternalIndicator == "" {
+		return
+	}
+	run := p.AddRun(c.config.Styles.Link.ExternalIndicator)
+	run.Color = c.linkColor()
+}
+
+// autoLinkTarget 计算自动链接的目标地址
+// 邮箱补全 mailto: 前缀；无协议的 URL（如 www.example.com）由 AddHyperlink 补全 http://
+func autoLinkTarget(node *ast.AutoLink, source []byte) string {
+	url := string(node.URL(source))
+	if node.AutoLinkType == ast.AutoLinkEmail && !strings.HasPrefix(strings.ToLower(url), "mailto:") {
+		return "mailto:" + url
+	}
+	return url
+}
 
 // processImage 处理图片
 func (c *Converter) processImage(node *ast.Image, p docx.RunContainer) {
-	src := string(node.Destination)
+	run := c.addImage(string(node.Destination), p)
+	if float := c.imageFloat(node); float != "" {
+		// 浮动图片由文字环绕，不再设置段落对齐
+		if run != nil {
+			run.Float = float
+		}
+		return
+	}
+	if para, ok := p.(*docx.Paragraph); ok && isSoleImage(node) {
+		if align := c.imageAlign(node); align != config.ImageAlignInline {
+			para.Align = align
+		}
+	}
+}
+
+// isSoleImage 判断图片是否独占一个正文段落（不在列表项、表格中）
+func isSoleImage(node *ast.Image) bool {
+	para, ok := node.Parent().(*ast.Paragraph)
+	if !ok || node.PreviousSibling() != nil || node.NextSibling() != nil {
+		return false
+	}
+	_, inList := para.Parent().(*ast.ListItem)
+	return !inList
+}
+
+// imageFloatPrefixes 替代文字中表示浮动图片的前缀，如 ![float-right: 示意图](a.png)
+var imageFloatPrefixes = map[string]string{
+	"float-left:":  "left",
+	"float-right:": "right",
+}
+
+// imageFloat 返回图片的浮动方向（left/right），不浮动时为空：
+// 替代文字以 float-left: / float-right: 开头，或 HTML 图片的 align 为 left/right（与浏览器一致）
+func (c *Converter) imageFloat(node *ast.Image) string {
+	alt := strings.ToLower(strings.TrimSpace(c.extractParagraphText(node)))
+	for prefix, float := range imageFloatPrefixes {
+		if strings.HasPrefix(alt, prefix) {
+			return float
+		}
+	}
+	if v, ok := node.AttributeString("align"); ok {
+		switch float := strings.ToLower(fmt.Sprint(v)); float {
+		case "left", "right":
+			return float
+		}
+	}
+	return ""
+}
+
+// imageAlign 返回独占一段的图片的对齐方式：HTML 图片的 align="center" 优先，其次为 images.align
+func (c *Converter) imageAlign(node *ast.Image) string {
+	if v, ok := node.AttributeString("align"); ok && strings.EqualFold(fmt.Sprint(v), config.ImageAlignCenter) {
+		return config.ImageAlignCenter
+	}
+	if c.config.Images.Align == "" {
+		return config.ImageAlignInline
+	}
+	return c.config.Images.Align
+}
+
+// addImage 加载 src 指向的图片（网络、data URI 或本地路径）并作为图片 run 加入 p，
+// 返回图片 run；加载失败以占位文字代替时返回 nil
+func (c *Converter) addImage(src string, p docx.RunContainer) *docx.Run {
 	var data []byte
 	var contentType string
 	var err error
@@ -476,33 +901,47 @@ func (c *Converter) processImage(node *ast.Image, p docx.RunContainer) {
 	}
 
 	if err != nil {
-		fmt.Printf("图片加载失败: %s, %v\n", src, err)
-		return
+		c.warn(i18n.ImageLoadFailed, src, err)
+		c.addPlaceholderRun(p, placeholderImage, src)
+		return nil
 	}
 
 	// 如果没有检测到 contentType，尝试从数据中检测
 	if contentType == "" || contentType == "application/octet-stream" {
 		contentType = http.DetectContentType(data)
 	}
-
+	if isSVG(data, contentType) {
+		return c.addSVGImage(src, data, p)
+	}
+	data, contentType = transcodeImage(data, contentType)
+	// 显示尺寸按原图计算，之后的缩小只减少嵌入的数据量
 	width, height := c.getImageDimensions(data)
-
-	// 使用智能尺寸计算
 	displayW, displayH := c.calculateOptimalImageSize(width, height)
 
+	if limited, limitedType, ok := c.limitImage(data, contentType); ok {
+		data, contentType = limited, limitedType
+		width, height = c.getImageDimensions(data)
+	}
+	data, contentType = c.optimizeImage(data, contentType)
+
 	rID := c.doc.AddImage(data, contentType, width, height)
 	// Word使用EMU单位: 1 pixel 约等于 9525 EMUs
-	p.AddImageRun(rID, int64(displayW)*9525, int64(displayH)*9525)
+	return p.AddImageRun(rID, int64(displayW)*9525, int64(displayH)*9525)
+}
+
+// contentWidthTwips 返回纵向页面内容区宽度，扣除配置的装订线
+func (c *Converter) contentWidthTwips() int {
+	return docx.ContentWidthTwips() - int(c.config.Page.Margins.Gutter)
 }
 
 // calculateOptimalImageSize 计算图片的最佳显示尺寸
-// 目标：适配 Word 页面可用宽度（页面宽度 - 左右边距，由 docx.ContentWidthPx 提供），保持高清。
+// 目标：适配 Word 页面可用宽度（页面宽度 - 左右边距 - 装订线，见 contentWidthTwips），保持高清。
 func (c *Converter) calculateOptimalImageSize(originalWidth, originalHeight int) (displayWidth, displayHeight int) {
 	const (
 		minWidth  = 100
 		maxHeight = 800
 	)
-	maxPageWidth := docx.ContentWidthPx()
+	maxPageWidth := c.contentWidthTwips() * 96 / 1440
 
 	displayWidth = originalWidth
 	displayHeight = originalHeight
@@ -634,19 +1073,6 @@ func (c *Converter) calculateFormulaSize(originalWidth, originalHeight int, isIn
 	return displayWidth, displayHeight
 }
 
-func (c *Converter) downloadImage(url string) ([]byte, string, error) {
-	client := &http.Client{
-		Timeout: time.Duration(c.config.Images.DownloadTimeout) * time.Second,
-	}
-	resp, err := client.Get(url)
-	if err != nil {
-		return nil, "", err
-	}
-	defer resp.Body.Close()
-	data, err := io.ReadAll(resp.Body)
-	return data, resp.Header.Get("Content-Type"), err
-}
-
 func (c *Converter) parseBase64Image(src string) ([]byte, string, error) {
 	parts := strings.Split(src, ",")
 	if len(parts) != 2 {
@@ -667,6 +1093,7 @@ func (c *Converter) loadLocalImage(path string) ([]byte, string, error) {
 	if !filepath.IsAbs(path) {
 		path = filepath.Join(c.basePath, path)
 	}
+	c.localFiles = append(c.localFiles, path)
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, "", err
@@ -698,62 +1125,67 @@ func (c *Converter) processFencedCodeBlock(node *ast.FencedCodeBlock) error {
 		return c.processMathBlock(node)
 	}
 
-	table := docx.NewTable()
-	table.HasBorders = true
-	row := table.AddRow(false)
-	cell := row.AddCell()
-	cell.Shading = "F6F8FA"
-
 	var code strings.Builder
 	for i := 0; i < node.Lines().Len(); i++ {
 		line := node.Lines().At(i)
 		code.WriteString(string(line.Value(c.source)))
 	}
 
-	// 执行高亮渲染到单元格中
-	fontName := c.config.Styles.CodeBlock.Font
-	fontSize := c.config.Styles.CodeBlock.Size
-	lineSpacing := c.config.Styles.CodeBlock.LineSpacing
-	lineHeight := c.config.Styles.CodeBlock.LineHeight
-	if fontName == "" {
-		fontName = "Consolas"
+	if strings.ToLower(lang) == "ooxml" && c.config.AllowRawOOXML {
+		raw, err := docx.NewRawXML(code.String())
+		if err != nil {
+			return fmt.Errorf("ooxml 代码块: %w", err)
+		}
+		c.doc.AddParagraph(raw)
+		return nil
 	}
-	if fontSize == 0 {
-		fontSize = 9.5
+
+	c.addCodeBlock(code.String(), lang)
+	return nil
+}
+
+// addCodeBlock 将代码渲染为单格表格（浅灰底色 + 语法高亮）并添加到文档
+func (c *Converter) addCodeBlock(code, lang string) {
+	table := docx.NewTable()
+	table.HasBorders = true
+	row := table.AddRow(false)
+	cell := row.AddCell()
+	cell.Shading = "F6F8FA"
+	if c.config.Styles.CodeBlock.Background != "" {
+		cell.Shading = strings.TrimPrefix(c.config.Styles.CodeBlock.Background, "#")
 	}
 
-	if err := HighlightCodeNative(cell, code.String(), lang, fontName, fontSize, lineSpacing, lineHeight); err != nil {
+	// 执行高亮渲染到单元格中，字体、字号与行距由代码块样式提供
+	if err := HighlightCodeNative(cell, code, lang, c.config.Code.HighlightStyle, docx.CodeBlockStyleID); err != nil {
 		// 回退处理
-		p := docx.NewParagraph("")
-		p.SpacingA = lineSpacing / 2
-		p.SpacingB = lineSpacing / 2
-		p.LineHeight = lineHeight
-		run := p.AddRun(code.String())
-		run.FontName = fontName
-		run.FontSize = fontSize
+		p := docx.NewParagraph(docx.CodeBlockStyleID)
+		p.AddRun(code)
 		cell.AddParagraph(p)
 	}
 	c.doc.AddParagraph(docx.NewTableElement(table))
 	c.doc.AddParagraph(docx.NewParagraph(""))
-	return nil
 }
 
-// processCodeBlock 处理缩进代码块 - 重新解析为Markdown
+// processCodeBlock 处理缩进代码块
+// 默认 (code.indentedAsCode=true) 与 CommonMark 语义一致：按原样渲染为代码块（无语言，不做高亮）。
+// 设为 false 时沿用旧行为，把内容当作 Markdown 重新解析；重新解析可能再次产生缩进代码块，
+// 因此用 maxReparseDepth 限制嵌套深度，超出后回退为代码块渲染。
 func (c *Converter) processCodeBlock(node *ast.CodeBlock) error {
-	var lines []string
+	var code strings.Builder
 	for i := 0; i < node.Lines().Len(); i++ {
 		line := node.Lines().At(i)
-		lineStr := string(line.Value(c.source))
-		// 去除4空格缩进（如果存在）
-		if len(lineStr) >= 4 && lineStr[:4] == "    " {
-			lineStr = lineStr[4:]
-		}
-		lines = append(lines, lineStr)
+		code.WriteString(string(line.Value(c.source)))
 	}
 
-	// 合并为新的Markdown内容，重新解析
-	content := strings.Join(lines, "")
+	if c.config.Code.IndentedAsCode || c.reparseDepth >= maxReparseDepth {
+		c.addCodeBlock(code.String(), "")
+		return nil
+	}
+	return c.reparseCodeBlock(code.String())
+}
 
+// reparseCodeBlock 将缩进代码块的内容重新解析为Markdown并处理
+func (c *Converter) reparseCodeBlock(content string) error {
 	// 使用Goldmark重新解析这段内容
 	mdParser := c.parser.GetParser()
 	reader := goldmarkText.NewReader([]byte(content))
@@ -762,74 +1194,73 @@ func (c *Converter) processCodeBlock(node *ast.CodeBlock) error {
 	// 保存原始source，临时替换为新内容
 	originalSource := c.source
 	c.source = []byte(content)
+	c.reparseDepth++
+	defer func() {
+		// 恢复原始source
+		c.source = originalSource
+		c.reparseDepth--
+	}()
 
 	// 递归处理子AST
 	for child := subDoc.FirstChild(); child != nil; child = child.NextSibling() {
 		if err := c.processNode(child); err != nil {
-			c.source = originalSource
 			return err
 		}
 	}
-
-	// 恢复原始source
-	c.source = originalSource
 	return nil
 }
 
 // processMermaid 处理Mermaid流程图
 func (c *Converter) processMermaid(node *ast.FencedCodeBlock) error {
-	fmt.Println("正在处理 Mermaid 流程图...")
-	var lines []string
-	for i := 0; i < node.Lines().Len(); i++ {
-		line := node.Lines().At(i)
-		lines = append(lines, string(line.Value(c.source)))
-	}
-	mermaidCode := strings.Join(lines, "")
+	fmt.Println(c.msg(i18n.ProcessingMermaid))
+	mermaidCode := c.blockText(node)
 
-	ctx, err := c.ensureChrome()
-	if err != nil {
-		return fmt.Errorf("启动浏览器失败: %w", err)
+	job := c.mermaidJob(node)
+	res := c.rendered(job)
+	imgData, err := res.data, res.err
+	if errors.Is(err, errBrowserStart) {
+		return err
 	}
-
-	imgData, err := RenderMermaidWithContext(ctx, mermaidCode, c.config.Mermaid.Theme, c.config.Mermaid.Width, c.config.Mermaid.Height, c.config.Mermaid.Scale)
 	if err != nil {
-		fmt.Printf("Mermaid 渲染错误: %v\n", err)
-		p := docx.NewParagraph("")
-		p.Shading = "FFF3CD"
-		p.Border = true
-		p.AddRun("[流程图渲染失败]\n").Bold = true
-		p.AddRun("原始代码:\n" + mermaidCode).FontName = "Consolas"
-		c.doc.AddParagraph(p)
+		c.warn(i18n.MermaidFailed, err)
+		c.addPlaceholderBlock(placeholderMermaid, mermaidCode)
 		return nil
 	}
 
 	width, height := c.getImageDimensions(imgData)
 
-	// 使用智能尺寸计算
+	// 使用智能尺寸计算；指定了固定宽度时按该宽度显示（不超过页面可用宽度）
 	displayW, displayH := c.calculateOptimalImageSize(width, height)
+	if job.width > 0 && width > 0 {
+		displayW = min(job.width, c.contentWidthTwips()*96/1440)
+		displayH = height * displayW / width
+	}
 
 	rID := c.doc.AddImage(imgData, "image/png", width, height)
 	p := docx.NewParagraph("")
 	p.Align = "center"
-	p.AddImageRun(rID, int64(displayW)*9525, int64(displayH)*9525)
+	run := p.AddImageRun(rID, int64(displayW)*9525, int64(displayH)*9525)
+	if len(res.svg) > 0 {
+		// 矢量图供支持 SVG 的 Word 使用，PNG 作为旧版本的后备
+		run.SVGRelID = c.doc.AddImage(res.svg, "image/svg+xml", width, height)
+	}
 	c.doc.AddParagraph(p)
 	return nil
 }
 
 func (c *Converter) processMathBlock(node *ast.FencedCodeBlock) error {
-	var lines []string
-	for i := 0; i < node.Lines().Len(); i++ {
-		line := node.Lines().At(i)
-		lines = append(lines, string(line.Value(c.source)))
-	}
-	latex := strings.Join(lines, "")
-	return c.renderMathAsImage(latex, true)
+	return c.renderMathAsImage(c.blockText(node), true)
 }
 
 func (c *Converter) renderMathAsImage(latex string, display bool) error {
-	imgData, err := RenderMathJax(latex, display)
+	imgData, err := c.renderedImage(renderJob{kind: renderMath, source: latex, display: display})
 	if err != nil {
-		return err
+		if ctxErr := c.ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		c.warn(i18n.MathFailed, latex, err)
+		c.addPlaceholderBlock(placeholderMath, latex)
+		return nil
 	}
 	width, height := c.getImageDimensions(imgData)
 	
@@ -846,34 +1277,43 @@ func (c *Converter) renderMathAsImage(latex string, display bool) error {
 	return nil
 }
 
-// processList 处理列表
-func (c *Converter) processList(node *ast.List, level int) error {
+// processList 处理列表，有序列表从 Markdown 中的起始序号开始编号；
+// 开启 styles.list.continueNumbering 时，从 1 开始的顶层有序列表接续上一个有序列表的序号。
+// last 表示该列表的最后一段也是整个顶层列表的最后一段，用于加上 styles.list.spaceAfter
+func (c *Converter) processList(node *ast.List, level int, last bool) error {
 	i := 1
+	if node.IsOrdered() {
+		i = node.Start
+		if level == 0 && i == 1 && c.config.Styles.List.ContinueNumbering {
+			i = c.lastOrdered + 1
+		}
+	}
+	// 每个列表一个 Word 编号实例，序号由 Word 生成
+	numID := c.doc.AddListNumbering(c.listLevels(node.IsOrdered()), level, i)
 	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
 		if item, ok := child.(*ast.ListItem); ok {
-			c.processListItem(item, node.IsOrdered(), i, level)
+			c.processListItem(item, numID, level, level == 0 && child == node.FirstChild(), last && child.NextSibling() == nil)
 			i++
 		}
 	}
+	if node.IsOrdered() && level == 0 {
+		c.lastOrdered = i - 1
+	}
 	return nil
 }
 
-func (c *Converter) processListItem(node *ast.ListItem, isOrdered bool, index int, level int) {
+// processListItem 处理列表项：段落引用列表的编号实例 numID，级别 level 决定序号格式与缩进；
+// first 表示顶层列表的第一项，last 表示该项所在的各级列表都已到最后一项
+func (c *Converter) processListItem(node *ast.ListItem, numID int, level int, first, last bool) {
 	p := docx.NewParagraph("")
-
-	// 使用首行缩进(不是悬挂缩进):
-	// - 内容顶格(左缩进=0)
-	// - 序号向右移动360 twips
-	// 结果:序号行在360位置(有缩进),内容换行后在0位置(顶格)
-	p.Indent = 0
-	p.FirstLineIndent = 360 // 序号缩进
-
-	p.LineHeight = c.config.Styles.Body.LineHeight
-	if isOrdered {
-		p.AddRun(fmt.Sprintf("%d. ", index)).Bold = true
+	box := taskCheckBox(node)
+	if box != nil {
+		// 任务项以复选框代替项目符号
+		c.addTaskCheckBox(p, box, level)
 	} else {
-		p.AddRun("• ").Bold = true
+		p.NumberingXML = docx.GetNumberingXMLForParagraph(min(level, 8), numID)
 	}
+	p.LineHeight = int(c.config.Styles.Body.LineHeight)
 
 	// 收集嵌套列表,稍后处理
 	var nestedLists []*ast.List
@@ -885,26 +1325,36 @@ func (c *Converter) processListItem(node *ast.ListItem, isOrdered bool, index in
 			nestedLists = append(nestedLists, list)
 			continue
 		}
-		c.processInlineNodes(child, p)
+		c.processInlineContent(child, p)
+	}
+	if box != nil && box.IsChecked && c.config.Styles.TaskList.StrikeCompleted {
+		strikeTaskText(p.Runs()[1:])
+	}
+
+	// 列表与前后正文的间距；有嵌套列表时最后一段在嵌套列表中
+	if first {
+		p.SpacingA = int(c.config.Styles.List.SpaceBefore)
+	}
+	if last && len(nestedLists) == 0 {
+		p.SpacingB = int(c.config.Styles.List.SpaceAfter)
 	}
 
 	// 先添加当前段落
+	c.attachPendingComments(p)
 	c.doc.AddParagraph(p)
 
 	// 再处理嵌套列表(在当前段落之后)
-	for _, list := range nestedLists {
-		c.processList(list, level+1)
+	for i, list := range nestedLists {
+		c.processList(list, level+1, last && i == len(nestedLists)-1)
 	}
 }
 
 func (c *Converter) processBlockquote(node *ast.Blockquote) error {
 	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
-		tempP := docx.NewParagraph("")
+		// 边框、底纹与缩进由引用样式提供
+		tempP := docx.NewParagraph(docx.QuoteStyleID)
 		c.processInlineNodes(child, tempP)
-		tempP.Shading = "F0F0F0"
-		tempP.Border = true
-		tempP.Indent = 360
-		tempP.LineHeight = c.config.Styles.Body.LineHeight
+		c.attachPendingComments(tempP)
 		c.doc.AddParagraph(tempP)
 	}
 	return nil
@@ -919,19 +1369,46 @@ func (c *Converter) processThematicBreak() error {
 	return nil
 }
 
+// columnAlign 返回 Markdown 表格第 col 列声明的对齐方式，未声明时左对齐
+func columnAlign(table *east.Table, col int) string {
+	if col < len(table.Alignments) {
+		switch table.Alignments[col] {
+		case east.AlignCenter:
+			return "center"
+		case east.AlignRight:
+			return "right"
+		}
+	}
+	return "left"
+}
+
 func (c *Converter) processTable(node *east.Table) error {
 	// 简单的表格占位符，可以稍后细化
 	table := docx.NewTable()
 	table.HasBorders = true
 	for row := node.FirstChild(); row != nil; row = row.NextSibling() {
-		r := table.AddRow(false) // 简化处理
+		// 表头行跨页时重复，并按 table.headerBold / table.headerBackground 加粗、加底纹
+		_, header := row.(*east.TableHeader)
+		r := table.AddRow(header)
+		col := 0
 		for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
 			c_cell := r.AddCell()
+			c_cell.Align = columnAlign(node, col)
+			col++
 			p := docx.NewParagraph("")
-			c.processInlineNodes(cell, p)
+			c.processInlineContent(cell, p)
+			if header {
+				c_cell.Shading = strings.TrimPrefix(c.config.Table.HeaderBackground, "#")
+				if c.config.Table.HeaderBold {
+					for _, run := range p.Runs() {
+						run.Bold = true
+					}
+				}
+			}
 			c_cell.AddParagraph(p)
 		}
 	}
-	c.doc.AddParagraph(docx.NewTableElement(table))
+	c.setTableWidth(table)
+	c.addTable(table)
 	return nil
 }
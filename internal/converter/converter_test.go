@@ -0,0 +1,396 @@
+package converter
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/xml"
+	"image/color"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"md2word/internal/config"
+	"md2word/internal/docx"
+)
+
+// convertedDoc 转换结果中与断言相关的部件
+type convertedDoc struct {
+	document string            // word/document.xml
+	rels     map[string]string // document.xml.rels: ID -> Target
+	parts    map[string]string // 全部部件: 名称 -> 内容
+}
+
+// convertMarkdown 用默认配置（可由 setup 调整）转换 md，并读出生成的 docx 部件
+func convertMarkdown(t *testing.T, md string, setup func(cfg *config.Config)) *convertedDoc {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.Mermaid.Enabled = false
+	cfg.Images.Cache = false
+	if setup != nil {
+		setup(cfg)
+	}
+
+	out := filepath.Join(t.TempDir(), "out.docx")
+	if err := NewConverter(cfg).Convert(context.Background(), []byte(md), out); err != nil {
+		t.Fatalf("Convert: %v", err)
+	}
+	for _, err := range docx.Validate(out) {
+		t.Errorf("Validate: %v", err)
+	}
+	return readDocx(t, out)
+}
+
+// readDocx 读出 docx 文件的部件
+func readDocx(t *testing.T, path string) *convertedDoc {
+	t.Helper()
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+
+	parts := make(map[string]string)
+	for _, f := range zr.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts[f.Name] = string(data)
+	}
+
+	var rels struct {
+		Rels []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := xml.Unmarshal([]byte(parts["word/_rels/document.xml.rels"]), &rels); err != nil {
+		t.Fatal(err)
+	}
+	doc := &convertedDoc{document: parts["word/document.xml"], rels: make(map[string]string), parts: parts}
+	for _, rel := range rels.Rels {
+		doc.rels[rel.ID] = rel.Target
+	}
+	return doc
+}
+
+// docHyperlink 文档中的一个超链接
+type docHyperlink struct {
+	Target string
+	Text   string
+}
+
+// hyperlinks 按出现顺序返回所有超链接的关系目标与文字
+func (d *convertedDoc) hyperlinks(t *testing.T) []docHyperlink {
+	t.Helper()
+	var links []docHyperlink
+	dec := xml.NewDecoder(strings.NewReader(d.document))
+	var cur *docHyperlink
+	inText := false
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch tk := tok.(type) {
+		case xml.StartElement:
+			switch tk.Name.Local {
+			case "hyperlink":
+				cur = &docHyperlink{}
+				for _, attr := range tk.Attr {
+					if attr.Name.Local == "id" {
+						cur.Target = d.rels[attr.Value]
+					}
+				}
+			case "t":
+				inText = true
+			}
+		case xml.EndElement:
+			switch tk.Name.Local {
+			case "hyperlink":
+				links = append(links, *cur)
+				cur = nil
+			case "t":
+				inText = false
+			}
+		case xml.CharData:
+			if inText && cur != nil {
+				cur.Text += string(tk)
+			}
+		}
+	}
+	return links
+}
+
+// texts 返回文档中所有 <w:t> 的内容
+func (d *convertedDoc) texts(t *testing.T) []string {
+	t.Helper()
+	var texts []string
+	dec := xml.NewDecoder(strings.NewReader(d.document))
+	inText := false
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch tk := tok.(type) {
+		case xml.StartElement:
+			if tk.Name.Local == "t" {
+				inText = true
+				texts = append(texts, "")
+			}
+		case xml.EndElement:
+			if tk.Name.Local == "t" {
+				inText = false
+			}
+		case xml.CharData:
+			if inText {
+				texts[len(texts)-1] += string(tk)
+			}
+		}
+	}
+	return texts
+}
+
+func TestAutoLinks(t *testing.T) {
+	tests := []struct {
+		name       string
+		md         string
+		wantTarget string
+		wantText   string
+	}{
+		{"www", "see www.example.com today", "http://www.example.com", "www.example.com"},
+		{"www path", "www.example.com/docs?page=1", "http://www.example.com/docs?page=1", "www.example.com/docs?page=1"},
+		{"http bare domain", "visit http://example.com now", "http://example.com", "http://example.com"},
+		{"https", "visit https://example.com/a", "https://example.com/a", "https://example.com/a"},
+		{"angle brackets", "<https://example.com>", "https://example.com", "https://example.com"},
+		{"email", "mail foo@example.com now", "mailto:foo@example.com", "foo@example.com"},
+		{"angle email", "<foo@example.com>", "mailto:foo@example.com", "foo@example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			links := convertMarkdown(t, tt.md, nil).hyperlinks(t)
+			if len(links) != 1 {
+				t.Fatalf("got %d hyperlinks, want 1: %+v", len(links), links)
+			}
+			if links[0].Target != tt.wantTarget {
+				t.Errorf("target = %q, want %q", links[0].Target, tt.wantTarget)
+			}
+			if links[0].Text != tt.wantText {
+				t.Errorf("text = %q, want %q", links[0].Text, tt.wantText)
+			}
+		})
+	}
+}
+
+// 不带 www. 的裸域名不属于 GFM 自动链接，保持为普通文本
+func TestBareDomainIsNotLinked(t *testing.T) {
+	doc := convertMarkdown(t, "see example.com today", nil)
+	if links := doc.hyperlinks(t); len(links) != 0 {
+		t.Fatalf("got hyperlinks %+v, want none", links)
+	}
+	if got := strings.Join(doc.texts(t), ""); got != "see example.com today" {
+		t.Errorf("text = %q", got)
+	}
+}
+
+func TestLinkStyle(t *testing.T) {
+	const md = "[文档](https://example.com) 与 <https://example.org>\n"
+	doc := convertMarkdown(t, md, nil)
+	if n := strings.Count(doc.document, `<w:u w:val="single"/>`); n != 2 {
+		t.Errorf("default: underlined runs = %d, want 2", n)
+	}
+	if n := strings.Count(doc.document, `<w:color w:val="0563C1"/>`); n != 2 {
+		t.Errorf("default: colored runs = %d, want 2", n)
+	}
+
+	doc = convertMarkdown(t, md, func(cfg *config.Config) {
+		cfg.Styles.Link.Underline = false
+		cfg.Styles.Link.Color = "#C00000"
+	})
+	if strings.Contains(doc.document, "<w:u ") {
+		t.Error("underline present with styles.link.underline: false")
+	}
+	if n := strings.Count(doc.document, `<w:color w:val="C00000"/>`); n != 2 {
+		t.Errorf("colored runs = %d, want 2", n)
+	}
+}
+
+func TestExternalLinkIndicator(t *testing.T) {
+	const md = "# 概述\n\n[文档](https://example.com)、<https://example.org> 与 [概述](#概述)\n"
+	if got := strings.Join(convertMarkdown(t, md, nil).texts(t), ""); strings.Contains(got, "↗") {
+		t.Errorf("indicator present by default: %q", got)
+	}
+	doc := convertMarkdown(t, md, func(cfg *config.Config) { cfg.Styles.Link.ExternalIndicator = "↗" })
+	if got, want := strings.Join(doc.texts(t), ""), "概述文档↗、https://example.org↗ 与 概述"; got != want {
+		t.Errorf("text = %q, want %q", got, want)
+	}
+}
+
+func TestBuildReturnsElements(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Mermaid.Enabled = false
+	elems, err := NewConverter(cfg).Build(context.Background(), []byte("# 标题\n\n正文\n\n| a | b |\n|---|---|\n| 1 | 2 |\n"), t.TempDir())
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	var kinds []string
+	for _, elem := range elems {
+		switch e := elem.(type) {
+		case *docx.Paragraph:
+			kinds = append(kinds, "p:"+e.StyleID)
+		case *docx.TableElement:
+			kinds = append(kinds, "tbl")
+		}
+	}
+	want := []string{"p:Heading1", "p:BodyText", "tbl"}
+	if strings.Join(kinds, ",") != strings.Join(want, ",") {
+		t.Errorf("elements = %v, want %v", kinds, want)
+	}
+	if _, err := docx.MarshalElements(elems); err != nil {
+		t.Errorf("MarshalElements: %v", err)
+	}
+}
+
+func TestPreserveLineBreaks(t *testing.T) {
+	const md = "北京市海淀区\n中关村大街1号\n"
+
+	doc := convertMarkdown(t, md, nil)
+	if strings.Contains(doc.document, "<w:br/>") {
+		t.Error("soft line break rendered as <w:br/> by default")
+	}
+
+	doc = convertMarkdown(t, md, func(cfg *config.Config) { cfg.Paragraphs.PreserveLineBreaks = true })
+	if n := strings.Count(doc.document, "<w:br/>"); n != 1 {
+		t.Errorf("<w:br/> count = %d, want 1", n)
+	}
+}
+
+func TestSoftLineBreakJoining(t *testing.T) {
+	doc := convertMarkdown(t, "第一行中文\n第二行 end\nof line\n", nil)
+	if got := strings.Join(doc.texts(t), ""); got != "第一行中文第二行 end of line" {
+		t.Errorf("texts = %q", got)
+	}
+
+	doc = convertMarkdown(t, "中文\n中文\n", func(cfg *config.Config) { cfg.Paragraphs.CJKLineBreak = false })
+	if got := strings.Join(doc.texts(t), ""); got != "中文 中文" {
+		t.Errorf("texts = %q, want space with cjkLineBreak disabled", got)
+	}
+}
+
+func TestBodyParagraphStyle(t *testing.T) {
+	doc := convertMarkdown(t, "正文\n", nil)
+	if !strings.Contains(doc.document, `<w:pStyle w:val="BodyText"/>`) {
+		t.Error("body paragraph does not reference BodyText")
+	}
+	if strings.Contains(doc.document, "w:firstLine=") {
+		t.Error("body paragraph has direct indentation")
+	}
+
+	doc = convertMarkdown(t, "正文\n", func(cfg *config.Config) { cfg.Paragraphs.Style = "Normal" })
+	if !strings.Contains(doc.document, `<w:pStyle w:val="Normal"/>`) {
+		t.Error("paragraphs.style not applied")
+	}
+}
+
+func TestMaxStyledHeadingLevel(t *testing.T) {
+	md := "# 一级\n\n### 三级\n\n#### 细目 *说明*\n\n正文\n"
+	doc := convertMarkdown(t, md, func(cfg *config.Config) { cfg.Headings.MaxStyledLevel = 3 })
+	if !strings.Contains(doc.document, `<w:pStyle w:val="Heading3"/>`) {
+		t.Error("level 3 heading should keep its style")
+	}
+	if strings.Contains(doc.document, `<w:pStyle w:val="Heading4"/>`) {
+		t.Error("level 4 heading should be demoted")
+	}
+	i := strings.Index(doc.document, "细目")
+	p := doc.document[strings.LastIndex(doc.document[:i], "<w:p>"):]
+	p = p[:strings.Index(p, "</w:p>")]
+	if !strings.Contains(p, `<w:pStyle w:val="BodyText"/>`) || strings.Count(p, "<w:b/>") != 2 {
+		t.Errorf("demoted heading should be a bold body paragraph:\n%s", p)
+	}
+}
+
+func TestBlockquoteUsesQuoteStyle(t *testing.T) {
+	doc := convertMarkdown(t, "> 第一段\n>\n> 第二段\n", nil)
+	if n := strings.Count(doc.document, `<w:pStyle w:val="Quote"/>`); n != 2 {
+		t.Errorf("Quote paragraphs = %d, want 2", n)
+	}
+	if strings.Contains(doc.document, "<w:pBdr>") || strings.Contains(doc.document, "<w:shd ") {
+		t.Error("blockquote has direct border or shading")
+	}
+}
+
+func TestInlineCodeUsesCodeCharStyle(t *testing.T) {
+	doc := convertMarkdown(t, "调用 `fmt.Println` 与 `os.Exit`\n", func(cfg *config.Config) {
+		cfg.Styles.Code.Font = "Menlo"
+		cfg.Styles.Code.Color = "#C7254E"
+	})
+	if n := strings.Count(doc.document, `<w:rStyle w:val="CodeChar"/>`); n != 2 {
+		t.Errorf("CodeChar runs = %d, want 2", n)
+	}
+	if strings.Contains(doc.document, "<w:rFonts") || strings.Contains(doc.document, "<w:shd ") {
+		t.Error("inline code runs carry direct fonts or shading")
+	}
+	styles := doc.parts["word/styles.xml"]
+	start := strings.Index(styles, `w:styleId="CodeChar"`)
+	if start < 0 {
+		t.Fatal("CodeChar style missing")
+	}
+	style := styles[start : start+strings.Index(styles[start:], "</w:style>")]
+	for _, want := range []string{`w:ascii="Menlo"`, `<w:color w:val="C7254E"/>`, `w:fill="E8E8E8"`} {
+		if !strings.Contains(style, want) {
+			t.Errorf("CodeChar style missing %s", want)
+		}
+	}
+}
+
+func TestCodeBlockUsesCodeBlockStyle(t *testing.T) {
+	doc := convertMarkdown(t, "```go\nfunc main() {\n}\n```\n", nil)
+	if !strings.Contains(doc.document, `<w:pStyle w:val="CodeBlock"/>`) {
+		t.Error("code lines do not reference CodeBlock")
+	}
+	if strings.Contains(doc.document, `w:ascii="Consolas"`) {
+		t.Error("code runs carry direct fonts")
+	}
+	if !strings.Contains(doc.parts["word/styles.xml"], `w:styleId="CodeBlock"`) {
+		t.Error("CodeBlock style missing")
+	}
+}
+
+func TestKeepEmptyParagraphs(t *testing.T) {
+	const md = "甲\n\n<span></span>\n\n乙\n"
+	for keep, want := range map[bool]int{false: 2, true: 3} {
+		doc := convertMarkdown(t, md, func(cfg *config.Config) { cfg.Paragraphs.KeepEmpty = keep })
+		if n := strings.Count(doc.document, `<w:pStyle w:val="BodyText"/>`); n != want {
+			t.Errorf("keepEmpty=%v: paragraphs = %d, want %d", keep, n, want)
+		}
+	}
+}
+
+func TestImageAndMathOnlyParagraphsKept(t *testing.T) {
+	fakeMathTools(t)
+	img := filepath.Join(t.TempDir(), "dot.png")
+	if err := os.WriteFile(img, makePNG(t, 4, 4, func(x, y int) color.NRGBA { return color.NRGBA{0, 0, 0, 255} }), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	doc := convertMarkdown(t, "![点]("+img+")\n\n$x^2$\n", nil)
+	if n := strings.Count(doc.document, `<w:pStyle w:val="BodyText"/>`); n != 2 {
+		t.Errorf("paragraphs = %d, want 2", n)
+	}
+	if n := strings.Count(doc.document, "<w:drawing>"); n != 2 {
+		t.Errorf("drawings = %d, want 2", n)
+	}
+}
@@ -0,0 +1,94 @@
+package converter
+
+import (
+	"strings"
+
+	"md2word/internal/docx"
+	"md2word/internal/parser"
+)
+
+// processCriticMarkup 渲染 CriticMarkup：
+//   - 新增/删除渲染为 Word 修订 (w:ins / w:del)，作者取 review.author，时间为本次转换时间；替换拆为一处删除加一处新增
+//   - 高亮渲染为黄色突出显示，紧跟的 {>>批注<<} 成为覆盖该高亮文字的 Word 批注
+//   - 单独的批注插入在所在位置
+//
+// 位于超链接等非段落容器内时无法插入修订与批注，修订退化为普通文字（删除的文字加删除线），批注丢弃
+func (c *Converter) processCriticMarkup(node *parser.CriticMarkup, p docx.RunContainer, bold, italic, code, strike bool) {
+	switch node.Critic {
+	case parser.CriticSubstitution:
+		for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+			c.processInlineNode(child, p, bold, italic, code, strike)
+		}
+		return
+	case parser.CriticHighlight:
+		c.processCriticHighlight(node, p, bold, italic, code, strike)
+		return
+	case parser.CriticComment:
+		// 紧跟高亮的批注已由 processCriticHighlight 处理
+		if prev, ok := node.PreviousSibling().(*parser.CriticMarkup); ok && prev.Critic == parser.CriticHighlight {
+			return
+		}
+		if para, ok := p.(*docx.Paragraph); ok {
+			id := c.addCriticComment(node)
+			para.Children = append(para.Children, &docx.CommentRangeStart{ID: id}, &docx.CommentRangeEnd{ID: id})
+		}
+		return
+	}
+
+	kind := docx.RevisionInsert
+	if node.Critic == parser.CriticDeletion {
+		kind = docx.RevisionDelete
+	}
+	var target docx.RunContainer = p
+	if para, ok := p.(*docx.Paragraph); ok {
+		target = para.AddRevision(kind, c.doc.NextAnnotationID(), c.config.Review.Author, c.revisionDate)
+	} else if kind == docx.RevisionDelete {
+		strike = true
+	}
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		c.processInlineNode(child, target, bold, italic, code, strike)
+	}
+}
+
+// highlightRuns 给新增的文本运行加上突出显示
+type highlightRuns struct {
+	docx.RunContainer
+}
+
+func (h highlightRuns) AddRun(text string) *docx.Run {
+	run := h.RunContainer.AddRun(text)
+	run.Highlight = "yellow"
+	return run
+}
+
+func (h highlightRuns) AddFormattedRun(text string, bold, italic, code bool) *docx.Run {
+	run := h.RunContainer.AddFormattedRun(text, bold, italic, code)
+	run.Highlight = "yellow"
+	return run
+}
+
+// processCriticHighlight 渲染高亮文字，后面紧跟批注时以批注范围包住高亮文字
+func (c *Converter) processCriticHighlight(node *parser.CriticMarkup, p docx.RunContainer, bold, italic, code, strike bool) {
+	para, isPara := p.(*docx.Paragraph)
+	comment, hasComment := node.NextSibling().(*parser.CriticMarkup)
+	hasComment = hasComment && isPara && comment.Critic == parser.CriticComment
+
+	var id int
+	if hasComment {
+		id = c.addCriticComment(comment)
+		para.Children = append(para.Children, &docx.CommentRangeStart{ID: id})
+	}
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		c.processInlineNode(child, highlightRuns{p}, bold, italic, code, strike)
+	}
+	if hasComment {
+		para.Children = append(para.Children, &docx.CommentRangeEnd{ID: id})
+	}
+}
+
+// addCriticComment 登记 {>>批注<<} 的文字为批注并返回其ID
+func (c *Converter) addCriticComment(node *parser.CriticMarkup) int {
+	var text strings.Builder
+	c.extractTextFromNode(node, &text)
+	return c.doc.AddComment(c.config.Review.Author, strings.TrimSpace(text.String()))
+}
@@ -0,0 +1,58 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+
+	"md2word/internal/config"
+)
+
+func TestCriticMarkupRevisions(t *testing.T) {
+	doc := convertMarkdown(t, "保留{++新增++}文字{--删除--}，{~~旧词~>新词~~}；{++未闭合\n", nil)
+
+	ins := strings.Count(doc.document, `<w:ins w:id="`)
+	del := strings.Count(doc.document, `<w:del w:id="`)
+	if ins != 2 || del != 2 {
+		t.Errorf("ins = %d, del = %d, want 2 and 2", ins, del)
+	}
+	if !strings.Contains(doc.document, `<w:delText>删除</w:delText>`) || !strings.Contains(doc.document, `<w:delText>旧词</w:delText>`) {
+		t.Error("deleted text not written as w:delText")
+	}
+	if !strings.Contains(doc.document, `w:author="md2word"`) {
+		t.Error("revision author missing")
+	}
+	texts := strings.Join(doc.texts(t), "")
+	if !strings.Contains(texts, "{++未闭合") {
+		t.Errorf("unclosed markup should stay literal, got %q", texts)
+	}
+}
+
+func TestCriticMarkupHighlightAndComment(t *testing.T) {
+	doc := convertMarkdown(t, "这是{==重点==}{>>需要核实<<}内容，另有{>>独立批注<<}。\n", nil)
+
+	if !strings.Contains(doc.document, `<w:highlight w:val="yellow"/>`) {
+		t.Error("highlight missing")
+	}
+	comments := doc.parts["word/comments.xml"]
+	if !strings.Contains(comments, "需要核实") || !strings.Contains(comments, "独立批注") {
+		t.Errorf("comments.xml = %s", comments)
+	}
+	start := strings.Index(doc.document, "<w:commentRangeStart")
+	end := strings.Index(doc.document, "<w:commentRangeEnd")
+	if start < 0 || end < start || !strings.Contains(doc.document[start:end], "重点") {
+		t.Error("comment range does not cover highlighted text")
+	}
+	if texts := strings.Join(doc.texts(t), ""); strings.Contains(texts, "需要核实") {
+		t.Errorf("comment text rendered inline: %q", texts)
+	}
+}
+
+func TestCriticMarkupDisabled(t *testing.T) {
+	doc := convertMarkdown(t, "保留{++新增++}文字\n", func(cfg *config.Config) { cfg.CriticMarkup.Enabled = false })
+	if strings.Contains(doc.document, "<w:ins ") {
+		t.Error("revision rendered while criticmarkup is disabled")
+	}
+	if texts := strings.Join(doc.texts(t), ""); !strings.Contains(texts, "{++新增++}") {
+		t.Errorf("texts = %q, want literal markup", texts)
+	}
+}
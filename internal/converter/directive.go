@@ -0,0 +1,245 @@
+package converter
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+
+	"md2word/internal/config"
+	"md2word/internal/docx"
+	"md2word/internal/i18n"
+)
+
+// directivePattern 匹配独占一个 HTML 块的注释指令: <!-- name: args -->
+var directivePattern = regexp.MustCompile(`^<!--\s*([a-zA-Z][\w-]*)\s*(?::\s*(.*?))?\s*-->$`)
+
+// processHTMLBlock 处理 HTML 块
+// 注释形式的转换指令（section、comment、color、border）优先，其余按 HTML 元素转换（见 processHTMLElements）
+func (c *Converter) processHTMLBlock(node *ast.HTMLBlock) error {
+	var raw strings.Builder
+	for i := 0; i < node.Lines().Len(); i++ {
+		line := node.Lines().At(i)
+		raw.Write(line.Value(c.source))
+	}
+	if node.HasClosure() {
+		raw.Write(node.ClosureLine.Value(c.source))
+	}
+
+	m := directivePattern.FindStringSubmatch(strings.TrimSpace(raw.String()))
+	if m == nil {
+		return c.processHTMLElements(raw.String())
+	}
+	name, args := strings.ToLower(m[1]), parseDirectiveArgs(m[2])
+
+	switch name {
+	case "section":
+		return c.processSectionDirective(args)
+	case "comment":
+		// 批注内容原样保留，不按 key=value 解析
+		if text := strings.TrimSpace(m[2]); text != "" {
+			c.pendingComments = append(c.pendingComments, c.doc.AddComment(c.config.Review.Author, text))
+		}
+	case "color":
+		return c.processColorDirective(args)
+	case "border":
+		return c.processBorderDirective(args)
+	}
+	return nil
+}
+
+// paragraphColor color 指令给下一段落设置的文字颜色与底色 (RRGGBB)
+type paragraphColor struct {
+	text, background string
+}
+
+// processColorDirective 处理颜色指令
+// <!-- color: #C00000 background=#FFF0F0 --> 把下一个正文段落的文字设为该颜色，可选整段底色
+func (c *Converter) processColorDirective(args map[string]string) error {
+	var color paragraphColor
+	for key, value := range args {
+		field, v := &color.text, key
+		if value != "" {
+			if key != "background" {
+				continue
+			}
+			field, v = &color.background, value
+		}
+		hex, ok := normalizeHexColor(v)
+		if !ok {
+			return c.errorf(i18n.ColorInvalid, v)
+		}
+		*field = hex
+	}
+	c.pendingColor = &color
+	return nil
+}
+
+// applyPendingColor 把 color 指令的颜色应用到段落 p 的全部文字（及底色）
+func (c *Converter) applyPendingColor(p *docx.Paragraph) {
+	if c.pendingColor == nil {
+		return
+	}
+	if text := c.pendingColor.text; text != "" {
+		for _, run := range p.Runs() {
+			run.Color = text
+		}
+	}
+	if bg := c.pendingColor.background; bg != "" {
+		p.Shading = bg
+	}
+	c.pendingColor = nil
+}
+
+// borderStyles border 指令支持的线型
+var borderStyles = map[string]bool{
+	docx.BorderSingle: true,
+	docx.BorderDouble: true,
+	docx.BorderDashed: true,
+}
+
+// processBorderDirective 处理边框指令
+// <!-- border: left bottom color=#C00000 width=1.5 style=double --> 给下一个正文段落加边框；
+// 不列出边时四边都有，width 为线宽（不带单位时按磅，0.25~12 磅）
+func (c *Converter) processBorderDirective(args map[string]string) error {
+	border := &docx.ParagraphBorder{}
+	sides := map[string]*bool{"top": &border.Top, "left": &border.Left, "bottom": &border.Bottom, "right": &border.Right}
+	anySide := false
+	for key, value := range args {
+		invalid := func() error { return c.errorf(i18n.BorderInvalid, key+"="+value) }
+		switch key {
+		case "color":
+			hex, ok := normalizeHexColor(value)
+			if !ok {
+				return invalid()
+			}
+			border.Color = hex
+		case "width":
+			pt, _, err := config.ParseLength(value)
+			if err != nil || pt < 0.25 || pt > 12 {
+				return invalid()
+			}
+			border.Width = int(math.Round(pt * 8))
+		case "style":
+			if !borderStyles[value] {
+				return invalid()
+			}
+			border.Style = value
+		default:
+			side, ok := sides[key]
+			if !ok || value != "" {
+				return c.errorf(i18n.BorderInvalid, key)
+			}
+			*side, anySide = true, true
+		}
+	}
+	if !anySide {
+		border.Top, border.Left, border.Bottom, border.Right = true, true, true, true
+	}
+	c.pendingBorder = border
+	return nil
+}
+
+// applyPendingBorder 把 border 指令的边框应用到段落 p
+func (c *Converter) applyPendingBorder(p *docx.Paragraph) {
+	if c.pendingBorder != nil {
+		p.BorderStyle = c.pendingBorder
+		c.pendingBorder = nil
+	}
+}
+
+// normalizeHexColor 把 "#RGB"、"#RRGGBB"（# 可省略）规范为大写的 RRGGBB
+func normalizeHexColor(s string) (string, bool) {
+	s = strings.TrimPrefix(s, "#")
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+	if len(s) != 6 {
+		return "", false
+	}
+	if _, err := strconv.ParseUint(s, 16, 32); err != nil {
+		return "", false
+	}
+	return strings.ToUpper(s), true
+}
+
+// attachPendingComments 把之前的 comment 指令挂到段落 p 上（批注范围为整段）
+func (c *Converter) attachPendingComments(p *docx.Paragraph) {
+	for _, id := range c.pendingComments {
+		p.AddComment(id)
+	}
+	c.pendingComments = nil
+}
+
+// flushPendingComments 文末仍未挂上的批注放入一个空段落
+func (c *Converter) flushPendingComments() {
+	if len(c.pendingComments) == 0 {
+		return
+	}
+	p := docx.NewParagraph("")
+	c.attachPendingComments(p)
+	c.doc.AddParagraph(p)
+}
+
+// inlineComment 识别段落内的 <!-- comment: 内容 --> 并在该位置插入批注
+func (c *Converter) inlineComment(node *ast.RawHTML, p docx.RunContainer) {
+	para, ok := p.(*docx.Paragraph)
+	if !ok {
+		return
+	}
+	var raw strings.Builder
+	for i := 0; i < node.Segments.Len(); i++ {
+		seg := node.Segments.At(i)
+		raw.Write(seg.Value(c.source))
+	}
+	m := directivePattern.FindStringSubmatch(strings.TrimSpace(raw.String()))
+	if m == nil || strings.ToLower(m[1]) != "comment" {
+		return
+	}
+	if text := strings.TrimSpace(m[2]); text != "" {
+		id := c.doc.AddComment(c.config.Review.Author, text)
+		para.Children = append(para.Children, &docx.CommentRangeStart{ID: id}, &docx.CommentRangeEnd{ID: id})
+	}
+}
+
+// parseDirectiveArgs 解析 "key=value key2=value2" 形式的指令参数（也接受逗号分隔）
+func parseDirectiveArgs(s string) map[string]string {
+	args := make(map[string]string)
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ',' || r == '\t' }) {
+		key, value, _ := strings.Cut(field, "=")
+		args[strings.ToLower(key)] = strings.Trim(value, `"'`)
+	}
+	return args
+}
+
+// pageNumberFormats 支持的页码格式
+var pageNumberFormats = map[string]bool{
+	"decimal":     true,
+	"upperRoman":  true,
+	"lowerRoman":  true,
+	"upperLetter": true,
+	"lowerLetter": true,
+}
+
+// processSectionDirective 处理分节指令
+// <!-- section: format=decimal start=1 --> 结束当前节，新节使用指定页码格式并从 start 重新编号
+func (c *Converter) processSectionDirective(args map[string]string) error {
+	sec := &docx.Section{}
+	if format, ok := args["format"]; ok {
+		if !pageNumberFormats[format] {
+			return c.errorf(i18n.SectionFormat, format)
+		}
+		sec.PageNumberFormat = format
+	}
+	if start, ok := args["start"]; ok {
+		n, err := strconv.Atoi(start)
+		if err != nil || n < 1 {
+			return c.errorf(i18n.SectionStart, start)
+		}
+		sec.PageNumberStart = n
+	}
+	c.doc.AddSectionBreak(sec)
+	return nil
+}
@@ -0,0 +1,84 @@
+package converter
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"md2word/internal/config"
+)
+
+func TestCommentDirective(t *testing.T) {
+	md := "<!-- comment: 请核对数据来源 -->\n\n第一段。\n\n第二段 <!-- comment: 这里需要引用 --> 结尾。\n\n<!-- comment: 文末批注 -->\n"
+	doc := convertMarkdown(t, md, func(cfg *config.Config) { cfg.Review.Author = "审阅人" })
+
+	comments := doc.parts["word/comments.xml"]
+	for _, want := range []string{"请核对数据来源", "这里需要引用", "文末批注", `w:author="审阅人"`} {
+		if !strings.Contains(comments, want) {
+			t.Errorf("comments.xml missing %q", want)
+		}
+	}
+	if n := strings.Count(doc.document, "<w:commentReference "); n != 3 {
+		t.Errorf("%d comment references, want 3", n)
+	}
+	first := doc.document[strings.Index(doc.document, `<w:commentRangeStart w:id="0"/>`):]
+	if end := strings.Index(first, `<w:commentRangeEnd w:id="0"/>`); end < 0 || !strings.Contains(first[:end], "第一段。") {
+		t.Error("first comment does not cover the following paragraph")
+	}
+	if !strings.Contains(doc.parts["[Content_Types].xml"], "/word/comments.xml") {
+		t.Error("comments part has no content type")
+	}
+}
+
+func TestNoCommentsPart(t *testing.T) {
+	doc := convertMarkdown(t, "正文\n", nil)
+	if _, ok := doc.parts["word/comments.xml"]; ok {
+		t.Error("comments.xml written without comments")
+	}
+}
+
+func TestColorDirective(t *testing.T) {
+	md := "<!-- color: #c00 background=#FFF0F0 -->\n\n醒目 **提示** [链接](https://example.com)\n\n普通段落\n"
+	doc := convertMarkdown(t, md, nil)
+	if n := strings.Count(doc.document, `<w:color w:val="CC0000"/>`); n != 4 {
+		t.Errorf("colored runs = %d, want 4", n)
+	}
+	if n := strings.Count(doc.document, `w:fill="FFF0F0"`); n != 1 {
+		t.Errorf("shaded paragraphs = %d, want 1", n)
+	}
+}
+
+func TestInvalidColorDirective(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Mermaid.Enabled = false
+	c := NewConverter(cfg)
+	if err := c.Convert(context.Background(), []byte("<!-- color: red -->\n\n段落\n"), filepath.Join(t.TempDir(), "out.docx")); err == nil {
+		t.Fatal("expected error for invalid color")
+	}
+}
+
+func TestBorderDirective(t *testing.T) {
+	md := "<!-- border: left bottom color=#c00 width=1.5 style=double -->\n\n提示\n\n<!-- border -->\n\n方框\n\n普通段落\n"
+	doc := convertMarkdown(t, md, nil)
+	if !strings.Contains(doc.document, `<w:left w:val="double" w:sz="12" w:space="4" w:color="CC0000"/>`) {
+		t.Error("missing custom left border")
+	}
+	if n := strings.Count(doc.document, "<w:pBdr>"); n != 2 {
+		t.Errorf("bordered paragraphs = %d, want 2", n)
+	}
+	if n := strings.Count(doc.document, "<w:top "); n != 1 {
+		t.Errorf("top borders = %d, want 1 (default box only)", n)
+	}
+}
+
+func TestInvalidBorderDirective(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Mermaid.Enabled = false
+	for _, args := range []string{"middle", "style=wavy", "width=20", "color=red"} {
+		c := NewConverter(cfg)
+		if err := c.Convert(context.Background(), []byte("<!-- border: "+args+" -->\n\n段落\n"), filepath.Join(t.TempDir(), "out.docx")); err == nil {
+			t.Errorf("%s: expected error", args)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package converter
+
+import (
+	"math"
+	"unicode/utf8"
+
+	"md2word/internal/docx"
+)
+
+// splitDropCap 把段落开头的第一个字符移到单独的首字下沉段落并返回该段落；
+// 段落不以普通文字开头（如图片、行内代码、链接）时不处理，返回 nil
+func (c *Converter) splitDropCap(p *docx.Paragraph) *docx.Paragraph {
+	run, ok := p.Children[0].(*docx.Run)
+	if !ok || run.IsImage || run.IsCode || run.Text == "" {
+		return nil
+	}
+	r, size := utf8.DecodeRuneInString(run.Text)
+	if r == '\n' || r == '\t' || r == ' ' {
+		return nil
+	}
+
+	lines := c.config.Styles.DropCap.Lines
+	dropCap := docx.NewParagraph("")
+	dropCap.DropCap = lines
+	first := dropCap.AddRun(run.Text[:size])
+	first.Bold = run.Bold
+	first.Italic = run.Italic
+	first.Color = run.Color
+	first.FontSize = c.dropCapFontSize(lines)
+	run.Text = run.Text[size:]
+	if run.Text == "" {
+		p.Children = p.Children[1:]
+	}
+	return dropCap
+}
+
+// dropCapFontSize 估算下沉 lines 行的首字字号（磅）：字符的大写高度约为字号的 0.7，
+// 应覆盖 lines-1 个行距再加上末行的大写高度；按半磅取整
+func (c *Converter) dropCapFontSize(lines int) float64 {
+	body := float64(c.config.Styles.Body.Size)
+	if body <= 0 {
+		body = 10.5
+	}
+	body = c.config.ScaleFont(body)
+	lineHeight := float64(c.config.Styles.Body.LineHeight)
+	if lineHeight <= 0 {
+		lineHeight = 240
+	}
+	// 单倍行距约为字号的 1.2 倍，lineHeight 以 240 为单倍
+	linePt := body * 1.2 * lineHeight / 240
+	size := (float64(lines-1)*linePt + 0.7*body) / 0.7
+	return math.Round(size*2) / 2
+}
@@ -0,0 +1,34 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+
+	"md2word/internal/config"
+)
+
+func TestDropCap(t *testing.T) {
+	const md = "# 第一章\n\n春天来了。\n\n第二段不下沉。\n\n## 小节\n\n小节正文不下沉。\n\n# 第二章\n\n`code` 开头不下沉。\n"
+	doc := convertMarkdown(t, md, func(cfg *config.Config) {
+		cfg.Styles.DropCap.Enabled = true
+		cfg.Styles.DropCap.Lines = 3
+	})
+	if n := strings.Count(doc.document, `<w:framePr w:dropCap="drop" w:lines="3"`); n != 1 {
+		t.Errorf("drop cap frames = %d, want 1", n)
+	}
+	if texts := doc.texts(t); !strings.Contains(strings.Join(texts, "|"), "春|天来了。") {
+		t.Errorf("texts = %q, want first character split from the paragraph", texts)
+	}
+	if doc := convertMarkdown(t, md, nil); strings.Contains(doc.document, "w:framePr") {
+		t.Error("drop cap rendered while disabled")
+	}
+}
+
+func TestInvalidDropCapLines(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Styles.DropCap.Enabled = true
+	cfg.Styles.DropCap.Lines = 0
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error for styles.dropCap.lines: 0")
+	}
+}
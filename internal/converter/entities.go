@@ -0,0 +1,26 @@
+package converter
+
+import (
+	"strings"
+
+	"github.com/yuin/goldmark/util"
+)
+
+// decodeEntities 解码文本中的 HTML 实体，包括命名实体（&copy;）与数字实体（&#169;、&#x1F600;）。
+// goldmark 在 AST 中保留实体原文（由 HTML 渲染器负责解码），因此需要在生成 Run 前自行处理；
+// 未知的实体名保持原样
+func decodeEntities(s string) string {
+	if !strings.Contains(s, "&") {
+		return s
+	}
+	return string(util.ResolveEntityNames(util.ResolveNumericReferences([]byte(s))))
+}
+
+// unescapePunctuation 去掉反斜杠转义（\*、表格中的 \| 等）的反斜杠。
+// 与实体一样，goldmark 在文本节点中保留转义原文；代码中的反斜杠是字面字符，不应调用
+func unescapePunctuation(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	return string(util.UnescapePunctuations([]byte(s)))
+}
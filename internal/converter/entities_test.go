@@ -0,0 +1,65 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNonBreakingSpaceEntities(t *testing.T) {
+	entities := []string{"&nbsp;", "&#160;", "&#xA0;"}
+	contexts := []struct {
+		name string
+		md   string // %s 处替换为实体
+	}{
+		{"text", "foo%sbar"},
+		{"emphasis", "*foo%sbar*"},
+		{"strong", "**foo%sbar**"},
+		{"table cell", "| h |\n|---|\n| foo%sbar |"},
+	}
+	for _, entity := range entities {
+		for _, ctx := range contexts {
+			t.Run(ctx.name+"/"+entity, func(t *testing.T) {
+				md := strings.Replace(ctx.md, "%s", entity, 1)
+				texts := convertMarkdown(t, md, nil).texts(t)
+				got := strings.Join(texts, "")
+				if !strings.Contains(got, "foo\u00a0bar") {
+					t.Errorf("text = %q, want U+00A0 between foo and bar", got)
+				}
+				if strings.Contains(got, entity) {
+					t.Errorf("text = %q still contains %s", got, entity)
+				}
+			})
+		}
+	}
+}
+
+func TestDecodeEntities(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"a&nbsp;b", "a\u00a0b"},
+		{"a&#160;b", "a\u00a0b"},
+		{"a&#xA0;b", "a\u00a0b"},
+		{"a&#xa0;b", "a\u00a0b"},
+		{"a&ensp;b&emsp;c&thinsp;d", "a\u2002b\u2003c\u2009d"},
+		{"a &amp; b", "a & b"},
+		{"&copy; &#169; &#8482; &#x2605; &#x1F600;", "© © ™ ★ 😀"},
+		{"&unknown; &copy", "&unknown; &copy"},
+		{"plain", "plain"},
+	}
+	for _, tt := range tests {
+		if got := decodeEntities(tt.in); got != tt.want {
+			t.Errorf("decodeEntities(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEntitiesInAllContexts(t *testing.T) {
+	const md = "# 标题 &copy;\n\n正文 &#169; **粗体 &#x1F600;**\n\n- 列表 &#8482;\n\n| 表头 &#x2605; |\n|---|\n| 单元格 &amp; |\n\n`代码 &copy;`\n"
+	got := strings.Join(convertMarkdown(t, md, nil).texts(t), "")
+	for _, want := range []string{"标题 ©", "正文 ©", "粗体 😀", "列表 ™", "表头 ★", "单元格 &", "代码 &copy;"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("text %q missing %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package converter
+
+import "bytes"
+
+// splitFrontMatter 拆出文档开头以 --- 行开始、以 --- 或 ... 行结束的 YAML front matter，
+// 没有 front matter 时 meta 为 nil、body 为 content
+func splitFrontMatter(content []byte) (meta, body []byte) {
+	first, rest, ok := bytes.Cut(content, []byte("\n"))
+	if !ok || string(bytes.TrimRight(first, " \t\r")) != "---" {
+		return nil, content
+	}
+	for offset := 0; offset < len(rest); {
+		line, next := rest[offset:], len(rest)
+		if end := bytes.IndexByte(line, '\n'); end >= 0 {
+			line, next = line[:end], offset+end+1
+		}
+		switch string(bytes.TrimRight(line, " \t\r")) {
+		case "---", "...":
+			return rest[:offset], rest[next:]
+		}
+		offset = next
+	}
+	return nil, content
+}
+
+// applyFrontMatter 剥离 content 开头的 front matter，把其中的 style 样式覆盖叠加到本次转换使用的配置上
+// （front matter 优先于配置文件，配置文件优先于内置默认）；返回剩余的 Markdown 与恢复原配置的函数
+func (c *Converter) applyFrontMatter(content []byte) ([]byte, func(), error) {
+	meta, body := splitFrontMatter(content)
+	if meta == nil {
+		return content, func() {}, nil
+	}
+	cfg, err := c.config.WithFrontMatter(meta)
+	if err != nil {
+		return nil, nil, err
+	}
+	base := c.config
+	c.config = cfg
+	return body, func() { c.config = base }, nil
+}
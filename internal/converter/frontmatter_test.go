@@ -0,0 +1,46 @@
+package converter
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"md2word/internal/config"
+)
+
+func TestSplitFrontMatter(t *testing.T) {
+	meta, body := splitFrontMatter([]byte("---\r\nstyle: {}\r\n...\r\n# 标题\n"))
+	if string(meta) != "style: {}\r\n" || string(body) != "# 标题\n" {
+		t.Errorf("meta %q, body %q", meta, body)
+	}
+	for _, md := range []string{"# 标题\n---\n", "---\n未闭合\n"} {
+		if meta, body := splitFrontMatter([]byte(md)); meta != nil || string(body) != md {
+			t.Errorf("%q: unexpected front matter %q", md, meta)
+		}
+	}
+}
+
+func TestFrontMatterStyle(t *testing.T) {
+	md := "---\nstyle:\n  body: { font: \"Georgia\", size: 12 }\n---\n正文\n"
+	cfg := config.DefaultConfig()
+	cfg.Mermaid.Enabled = false
+	c := NewConverter(cfg)
+	out := filepath.Join(t.TempDir(), "out.docx")
+	if err := c.Convert(context.Background(), []byte(md), out); err != nil {
+		t.Fatalf("Convert: %v", err)
+	}
+	doc := readDocx(t, out)
+	styles := doc.parts["word/styles.xml"]
+	i := strings.Index(styles, `w:styleId="Normal"`)
+	body := styles[i : i+strings.Index(styles[i:], "</w:style>")]
+	if !strings.Contains(body, `w:ascii="Georgia"`) || !strings.Contains(body, `<w:sz w:val="24"/>`) {
+		t.Errorf("front matter style not applied:\n%s", body)
+	}
+	if strings.Contains(doc.document, "style:") || strings.Contains(doc.document, "<w:pBdr>") {
+		t.Error("front matter rendered as content")
+	}
+	if c.config != cfg {
+		t.Error("front matter config leaked past the conversion")
+	}
+}
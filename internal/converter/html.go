@@ -0,0 +1,432 @@
+package converter
+
+import (
+	"cmp"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"md2word/internal/docx"
+)
+
+// parseHTMLFragment 按 <body> 上下文解析 HTML 块，返回顶层节点
+func parseHTMLFragment(raw string) ([]*html.Node, error) {
+	return html.ParseFragment(strings.NewReader(raw), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+}
+
+// htmlAttr 返回元素属性值，不存在时为空
+func htmlAttr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// htmlText 返回节点下的全部文本，空白折叠为单个空格
+func htmlText(n *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// findHTMLElements 按文档顺序返回 n 之下（不含 n）所有指定标签的元素
+func findHTMLElements(n *html.Node, tag atom.Atom) []*html.Node {
+	var found []*html.Node
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && child.DataAtom == tag {
+			found = append(found, child)
+		}
+		found = append(found, findHTMLElements(child, tag)...)
+	}
+	return found
+}
+
+// processFigure 处理 <figure>：每张 <img> 居中成段，<figcaption> 文字以题注样式
+// 按 captions.figurePosition 放在图片上方或下方
+func (c *Converter) processFigure(n *html.Node) {
+	var images, captions []*docx.Paragraph
+	for _, img := range findHTMLElements(n, atom.Img) {
+		src := htmlAttr(img, "src")
+		if src == "" {
+			continue
+		}
+		p := docx.NewParagraph("")
+		p.Align = "center"
+		c.addImage(src, p)
+		images = append(images, p)
+	}
+	for _, caption := range findHTMLElements(n, atom.Figcaption) {
+		if text := htmlText(caption); text != "" {
+			p := docx.NewParagraph("Caption")
+			p.AddRun(text)
+			captions = append(captions, p)
+		}
+	}
+	c.addCaptioned(captionFigure, images, captions)
+}
+
+// htmlHeadings 标题标签对应的级别
+var htmlHeadings = map[atom.Atom]int{
+	atom.H1: 1, atom.H2: 2, atom.H3: 3, atom.H4: 4, atom.H5: 5, atom.H6: 6,
+}
+
+// htmlBlockTags 作为块级处理的容器标签：其中的内联内容各自成段
+var htmlBlockTags = map[atom.Atom]bool{
+	atom.Div: true, atom.Section: true, atom.Article: true, atom.Aside: true,
+	atom.Header: true, atom.Footer: true, atom.Main: true, atom.Nav: true,
+	atom.Address: true, atom.Details: true, atom.Summary: true,
+	atom.P: true, atom.Blockquote: true, atom.Hr: true, atom.Figure: true, atom.Table: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+}
+
+// processHTMLElements 把 HTML 块转换为等价的 Markdown 节点后交给对应的处理方法：
+// p、h1~h6、blockquote、hr、figure、table 以及 strong/em/code/del/a/img/br 等内联标签；
+// 不支持的标签退化为其文本内容，script/style 等不可见内容丢弃
+func (c *Converter) processHTMLElements(raw string) error {
+	nodes, err := parseHTMLFragment(raw)
+	if err != nil {
+		return nil
+	}
+	return c.processHTMLNodes(nodes)
+}
+
+// processHTMLNodes 依次处理同级 HTML 节点，相邻的内联节点合为一段
+func (c *Converter) processHTMLNodes(nodes []*html.Node) error {
+	var inline []*html.Node
+	flush := func() error {
+		if p := htmlParagraph(inline); p != nil {
+			inline = nil
+			return c.processNode(p)
+		}
+		inline = nil
+		return nil
+	}
+	for _, n := range nodes {
+		if n.Type == html.ElementNode && htmlBlockTags[n.DataAtom] {
+			if err := flush(); err != nil {
+				return err
+			}
+			if err := c.processHTMLBlockElement(n); err != nil {
+				return err
+			}
+			continue
+		}
+		inline = append(inline, n)
+	}
+	return flush()
+}
+
+// processHTMLBlockElement 处理单个块级 HTML 元素
+func (c *Converter) processHTMLBlockElement(n *html.Node) error {
+	switch {
+	case n.DataAtom == atom.Figure:
+		c.processFigure(n)
+		return nil
+	case n.DataAtom == atom.Hr:
+		return c.processThematicBreak()
+	case n.DataAtom == atom.Table:
+		table := c.htmlTable(n, 0)
+		c.setTableWidth(table)
+		c.addTable(table)
+		return nil
+	case n.DataAtom == atom.P:
+		if p := htmlParagraph(htmlChildren(n)); p != nil {
+			return c.processNode(p)
+		}
+		return nil
+	case n.DataAtom == atom.Blockquote:
+		return c.processBlockquote(htmlBlockquote(n))
+	case htmlHeadings[n.DataAtom] > 0:
+		h := ast.NewHeading(htmlHeadings[n.DataAtom])
+		appendHTMLInlines(h, htmlChildren(n))
+		trimHTMLWhitespace(h)
+		if !h.HasChildren() {
+			return nil
+		}
+		return c.processHeading(h)
+	}
+	// 其他容器：按子节点逐个处理
+	return c.processHTMLNodes(htmlChildren(n))
+}
+
+// maxHTMLTableDepth 嵌套表格的最大层数：更深的 <table> 退化为单元格中的文本
+const maxHTMLTableDepth = 1
+
+// htmlTable 把 <table> 转换为表格：<thead> 中的行作为重复的表头行，<th> 的文字加粗，
+// 单元格按 align/valign（或 text-align/vertical-align 样式）对齐，未设置时沿用所在的 <tr>；
+// depth 为嵌套层数，单元格中的 <table> 在 maxHTMLTableDepth 以内作为嵌套表格
+func (c *Converter) htmlTable(n *html.Node, depth int) *docx.Table {
+	table := docx.NewTable()
+	var addRows func(parent *html.Node, header bool)
+	addRows = func(parent *html.Node, header bool) {
+		for child := parent.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+			switch child.DataAtom {
+			case atom.Thead:
+				addRows(child, true)
+			case atom.Tbody, atom.Tfoot:
+				addRows(child, false)
+			case atom.Tr:
+				row := table.AddRow(header)
+				rowAlign := htmlAlign(child, "align", "text-align", htmlTextAligns)
+				rowVAlign := htmlAlign(child, "valign", "vertical-align", htmlVerticalAligns)
+				for cell := child.FirstChild; cell != nil; cell = cell.NextSibling {
+					if cell.Type == html.ElementNode && (cell.DataAtom == atom.Td || cell.DataAtom == atom.Th) {
+						tc := row.AddCell()
+						tc.Align = cmp.Or(htmlAlign(cell, "align", "text-align", htmlTextAligns), rowAlign)
+						tc.VAlign = cmp.Or(htmlAlign(cell, "valign", "vertical-align", htmlVerticalAligns), rowVAlign)
+						c.fillHTMLCell(tc, cell, depth)
+					}
+				}
+			}
+		}
+	}
+	addRows(n, false)
+	return table
+}
+
+// htmlTextAligns align 属性与 text-align 的取值对应的段落对齐
+var htmlTextAligns = map[string]string{
+	"left": "left", "start": "left", "center": "center", "right": "right", "end": "right", "justify": "justify",
+}
+
+// htmlVerticalAligns valign 属性与 vertical-align 的取值对应的单元格垂直对齐
+var htmlVerticalAligns = map[string]string{
+	"top": "top", "middle": "center", "bottom": "bottom",
+}
+
+// htmlAlign 返回元素的对齐方式：style 中的 prop 优先于 attr 属性（与浏览器一致），
+// 按 values 换算，未设置或取值不支持时为空
+func htmlAlign(n *html.Node, attr, prop string, values map[string]string) string {
+	for _, decl := range strings.Split(htmlAttr(n, "style"), ";") {
+		name, value, _ := strings.Cut(decl, ":")
+		if strings.EqualFold(strings.TrimSpace(name), prop) {
+			if v, ok := values[strings.ToLower(strings.TrimSpace(value))]; ok {
+				return v
+			}
+		}
+	}
+	return values[strings.ToLower(strings.TrimSpace(htmlAttr(n, attr)))]
+}
+
+// fillHTMLCell 把 <td>/<th> 的内容写入单元格：内联内容与块级标签各自成段，
+// 嵌套的 <table> 作为嵌套表格
+func (c *Converter) fillHTMLCell(cell *docx.TableCell, n *html.Node, depth int) {
+	var inline []*html.Node
+	flush := func() {
+		if para := htmlParagraph(inline); para != nil {
+			p := docx.NewParagraph("")
+			c.processInlineNodes(para, p)
+			if n.DataAtom == atom.Th {
+				for _, run := range p.Runs() {
+					run.Bold = true
+				}
+			}
+			cell.AddParagraph(p)
+		}
+		inline = nil
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		switch {
+		case child.Type == html.ElementNode && child.DataAtom == atom.Table && depth < maxHTMLTableDepth:
+			flush()
+			cell.AddTable(c.htmlTable(child, depth+1))
+		case child.Type == html.ElementNode && htmlBlockTags[child.DataAtom]:
+			flush()
+			inline = htmlChildren(child)
+			flush()
+		default:
+			inline = append(inline, child)
+		}
+	}
+	flush()
+}
+
+// htmlBlockquote 把 <blockquote> 转换为引用节点：内联内容各自成段，
+// cite 属性作为末尾的出处段落
+func htmlBlockquote(n *html.Node) *ast.Blockquote {
+	quote := ast.NewBlockquote()
+	var inline []*html.Node
+	flush := func() {
+		if p := htmlParagraph(inline); p != nil {
+			quote.AppendChild(quote, p)
+		}
+		inline = nil
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && htmlBlockTags[child.DataAtom] {
+			flush()
+			switch {
+			case child.DataAtom == atom.Blockquote:
+				// 嵌套引用展平为同一引用中的段落
+				for p := htmlBlockquote(child).FirstChild(); p != nil; {
+					next := p.NextSibling()
+					quote.AppendChild(quote, p)
+					p = next
+				}
+			default:
+				inline = htmlChildren(child)
+				flush()
+			}
+			continue
+		}
+		inline = append(inline, child)
+	}
+	flush()
+	if cite := htmlAttr(n, "cite"); cite != "" {
+		p := ast.NewParagraph()
+		p.AppendChild(p, ast.NewString([]byte("—— "+cite)))
+		quote.AppendChild(quote, p)
+	}
+	return quote
+}
+
+// htmlChildren 返回 n 的直接子节点
+func htmlChildren(n *html.Node) []*html.Node {
+	var children []*html.Node
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		children = append(children, child)
+	}
+	return children
+}
+
+// htmlParagraph 把一组内联节点转换为段落，内容为空时返回 nil
+func htmlParagraph(nodes []*html.Node) *ast.Paragraph {
+	p := ast.NewParagraph()
+	appendHTMLInlines(p, nodes)
+	trimHTMLWhitespace(p)
+	if !p.HasChildren() {
+		return nil
+	}
+	return p
+}
+
+// appendHTMLInlines 把 HTML 内联节点转换为 Markdown 内联节点追加到 parent
+func appendHTMLInlines(parent ast.Node, nodes []*html.Node) {
+	for _, n := range nodes {
+		switch n.Type {
+		case html.TextNode:
+			if text := collapseHTMLSpace(n.Data); text != "" {
+				parent.AppendChild(parent, ast.NewString([]byte(text)))
+			}
+			continue
+		case html.ElementNode:
+		default:
+			continue
+		}
+
+		var child ast.Node
+		switch n.DataAtom {
+		case atom.Strong, atom.B:
+			child = ast.NewEmphasis(2)
+		case atom.Em, atom.I, atom.Cite, atom.Dfn:
+			child = ast.NewEmphasis(1)
+		case atom.Code, atom.Kbd, atom.Samp, atom.Tt:
+			child = ast.NewCodeSpan()
+		case atom.Del, atom.S, atom.Strike:
+			child = east.NewStrikethrough()
+		case atom.A:
+			if href := htmlAttr(n, "href"); href != "" {
+				link := ast.NewLink()
+				link.Destination = []byte(href)
+				child = link
+			}
+		case atom.Small, atom.Span:
+			if caps := htmlCaps(n); caps != nil {
+				child = caps
+			}
+		case atom.Img:
+			if src := htmlAttr(n, "src"); src != "" {
+				link := ast.NewLink()
+				link.Destination = []byte(src)
+				img := ast.NewImage(link)
+				if align := htmlAttr(n, "align"); align != "" {
+					img.SetAttributeString("align", align)
+				}
+				parent.AppendChild(parent, img)
+			}
+			continue
+		case atom.Br:
+			parent.AppendChild(parent, ast.NewString([]byte("\n")))
+			continue
+		case atom.Script, atom.Style, atom.Template, atom.Noscript:
+			continue
+		}
+		if child == nil {
+			// 不支持的标签：保留其内容
+			appendHTMLInlines(parent, htmlChildren(n))
+			continue
+		}
+		appendHTMLInlines(child, htmlChildren(n))
+		if child.HasChildren() {
+			parent.AppendChild(parent, child)
+		}
+	}
+}
+
+// collapseHTMLSpace 按 HTML 规则把连续空白折叠为一个空格
+func collapseHTMLSpace(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range s {
+		if r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f' {
+			space = true
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	if space {
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
+
+// trimHTMLWhitespace 去掉块首尾的空白文本
+func trimHTMLWhitespace(block ast.Node) {
+	for {
+		s, ok := block.FirstChild().(*ast.String)
+		if !ok {
+			break
+		}
+		s.Value = []byte(strings.TrimLeft(string(s.Value), " "))
+		if len(s.Value) > 0 {
+			break
+		}
+		block.RemoveChild(block, s)
+	}
+	for {
+		s, ok := block.LastChild().(*ast.String)
+		if !ok {
+			break
+		}
+		s.Value = []byte(strings.TrimRight(string(s.Value), " "))
+		if len(s.Value) > 0 {
+			break
+		}
+		block.RemoveChild(block, s)
+	}
+}
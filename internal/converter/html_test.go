@@ -0,0 +1,194 @@
+package converter
+
+import (
+	"encoding/base64"
+	"image/color"
+	"slices"
+	"strings"
+	"testing"
+
+	"md2word/internal/config"
+)
+
+func TestFigureBlock(t *testing.T) {
+	png := makePNG(t, 20, 10, func(x, y int) color.NRGBA { return color.NRGBA{200, 0, 0, 255} })
+	md := "<figure>\n<img src=\"data:image/png;base64," + base64.StdEncoding.EncodeToString(png) + "\" alt=\"红色\">\n" +
+		"<figcaption>示意图 <em>说明</em></figcaption>\n</figure>\n"
+
+	doc := convertMarkdown(t, md, nil)
+	if !strings.Contains(doc.document, "<w:drawing>") {
+		t.Error("figure image missing")
+	}
+	if !strings.Contains(doc.document, `<w:pStyle w:val="Caption"/>`) {
+		t.Error("caption paragraph missing")
+	}
+	if got := doc.texts(t); !slices.Contains(got, "示意图 说明") {
+		t.Errorf("texts = %q, want caption text", got)
+	}
+	if strings.Index(doc.document, "<w:drawing>") > strings.Index(doc.document, "Caption") {
+		t.Error("caption precedes image")
+	}
+}
+
+func TestSemanticHTMLBlocks(t *testing.T) {
+	md := "<h2>HTML 标题</h2>\n\n" +
+		"<p>普通 <strong>加粗</strong> 与 <em>斜体</em> <a href=\"https://example.com\">链接</a></p>\n\n" +
+		"<blockquote cite=\"https://example.com/src\">\n<p>引用内容</p>\n</blockquote>\n\n" +
+		"<hr>\n\n" +
+		"<div><span>未知标签</span><script>alert(1)</script></div>\n"
+
+	doc := convertMarkdown(t, md, nil)
+	if !strings.Contains(doc.document, `<w:pStyle w:val="Heading2"/>`) {
+		t.Error("h2 not rendered as Heading2")
+	}
+	if !strings.Contains(doc.document, "<w:b/>") || !strings.Contains(doc.document, "<w:i/>") {
+		t.Error("strong/em formatting missing")
+	}
+	if got := doc.hyperlinks(t); len(got) != 1 || got[0].Target != "https://example.com" || got[0].Text != "链接" {
+		t.Errorf("hyperlinks = %+v", got)
+	}
+	texts := strings.Join(doc.texts(t), "|")
+	for _, want := range []string{"HTML 标题", "普通 ", "加粗", "引用内容", "—— https://example.com/src", "未知标签"} {
+		if !strings.Contains(texts, want) {
+			t.Errorf("texts %q missing %q", texts, want)
+		}
+	}
+	if strings.Contains(texts, "alert") {
+		t.Error("script content rendered")
+	}
+	if !strings.Contains(doc.document, "<w:pBdr>") {
+		t.Error("hr / blockquote border missing")
+	}
+}
+
+func TestImageAlign(t *testing.T) {
+	png := makePNG(t, 20, 10, func(x, y int) color.NRGBA { return color.NRGBA{0, 120, 0, 255} })
+	src := "data:image/png;base64," + base64.StdEncoding.EncodeToString(png)
+	tests := []struct {
+		name, md, align, want string
+	}{
+		{"default center", "![图](" + src + ")\n", "", `<w:jc w:val="center"/>`},
+		{"configured right", "![图](" + src + ")\n", "right", `<w:jc w:val="end"/>`},
+		{"inline", "![图](" + src + ")\n", "inline", ""},
+		{"with text", "文字 ![图](" + src + ")\n", "center", ""},
+		{"html override", "<img src=\"" + src + "\" align=\"center\">\n", "right", `<w:jc w:val="center"/>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := convertMarkdown(t, tt.md, func(cfg *config.Config) {
+				if tt.align != "" {
+					cfg.Images.Align = tt.align
+				}
+			})
+			if !strings.Contains(doc.document, "<w:drawing>") {
+				t.Fatal("image missing")
+			}
+			jc := strings.Contains(doc.document, "<w:jc ")
+			if tt.want == "" && jc {
+				t.Error("unexpected paragraph alignment")
+			}
+			if tt.want != "" && !strings.Contains(doc.document, tt.want) {
+				t.Errorf("document missing %s", tt.want)
+			}
+		})
+	}
+}
+
+func TestFloatingImages(t *testing.T) {
+	png := makePNG(t, 20, 10, func(x, y int) color.NRGBA { return color.NRGBA{0, 0, 120, 255} })
+	src := "data:image/png;base64," + base64.StdEncoding.EncodeToString(png)
+	tests := []struct {
+		name, md, want string
+	}{
+		{"alt prefix", "![float-right: 示意图](" + src + ")\n\n正文环绕图片\n", "right"},
+		{"alt prefix in text", "正文 ![Float-Left:](" + src + ") 环绕\n", "left"},
+		{"html align", "<img src=\"" + src + "\" align=\"left\">\n", "left"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := convertMarkdown(t, tt.md, nil)
+			if strings.Contains(doc.document, "<wp:inline") {
+				t.Error("floating image emitted inline")
+			}
+			for _, want := range []string{"<wp:anchor ", "<wp:align>" + tt.want + "</wp:align>", `<wp:wrapSquare wrapText="bothSides"/>`} {
+				if !strings.Contains(doc.document, want) {
+					t.Errorf("document missing %s", want)
+				}
+			}
+			if strings.Contains(doc.document, "<w:jc ") {
+				t.Error("floating image paragraph should not be aligned")
+			}
+		})
+	}
+}
+
+func TestHTMLCaps(t *testing.T) {
+	md := "<p>普通 <small>Small Caps</small> <span style=\"font-variant: small-caps\">Variant</span> " +
+		"<span style=\"color: red; text-transform: uppercase\">upper</span> <span>plain</span></p>\n"
+	doc := convertMarkdown(t, md, nil)
+	if n := strings.Count(doc.document, "<w:smallCaps/>"); n != 2 {
+		t.Errorf("smallCaps runs = %d, want 2", n)
+	}
+	if n := strings.Count(doc.document, "<w:caps/>"); n != 1 {
+		t.Errorf("caps runs = %d, want 1", n)
+	}
+	if got := strings.Join(doc.texts(t), ""); !strings.Contains(got, "Small Caps") || !strings.Contains(got, "plain") {
+		t.Errorf("text = %q", got)
+	}
+}
+
+func TestHeadingSmallCaps(t *testing.T) {
+	doc := convertMarkdown(t, "# Title\n", func(cfg *config.Config) { cfg.Styles.Heading1.SmallCaps = true })
+	styles := doc.parts["word/styles.xml"]
+	i := strings.Index(styles, `w:styleId="Heading1"`)
+	end := strings.Index(styles[i:], "</w:style>")
+	if !strings.Contains(styles[i:i+end], "<w:smallCaps/>") {
+		t.Error("Heading1 style missing smallCaps")
+	}
+	if strings.Contains(styles[i+end:], "<w:smallCaps/>") {
+		t.Error("smallCaps leaked into other styles")
+	}
+}
+
+func TestNestedHTMLTable(t *testing.T) {
+	md := "<table>\n<thead><tr><th>项目</th><th>明细</th></tr></thead>\n" +
+		"<tbody><tr><td>费用</td><td>合计\n<table><tr><td>差旅</td><td><table><tr><td>机票</td></tr></table></td></tr></table>\n</td></tr></tbody>\n</table>\n"
+
+	doc := convertMarkdown(t, md, nil)
+	if n := strings.Count(doc.document, "<w:tbl>"); n != 2 {
+		t.Fatalf("tables = %d, want 2 (only one level of nesting)", n)
+	}
+	outer := strings.Index(doc.document, "<w:tbl>")
+	inner := strings.LastIndex(doc.document, "<w:tbl>")
+	if end := strings.Index(doc.document, "</w:tbl>"); end < inner {
+		t.Error("inner table should be inside the outer table")
+	}
+	if !strings.Contains(doc.document[outer:inner], "<w:tblHeader/>") {
+		t.Error("thead row should repeat as header")
+	}
+	got := doc.texts(t)
+	for _, want := range []string{"项目", "合计", "差旅", "机票"} {
+		if !slices.Contains(got, want) {
+			t.Errorf("texts = %q, missing %q", got, want)
+		}
+	}
+	// 嵌套表格位于单元格末尾时补一个空段落
+	if !strings.Contains(doc.document, "</w:tbl>\n                    <w:p/>") {
+		t.Error("cell ending with a nested table needs a trailing paragraph")
+	}
+}
+
+func TestHTMLTableCellAlign(t *testing.T) {
+	md := "<table>\n<tr align=\"right\" valign=\"bottom\"><td>行</td><td align=\"center\">属性</td>" +
+		"<td align=\"left\" style=\"text-align: justify; vertical-align: middle\">样式</td></tr>\n</table>\n"
+
+	doc := convertMarkdown(t, md, nil)
+	for _, want := range []string{`<w:jc w:val="end"/>`, `<w:jc w:val="center"/>`, `<w:jc w:val="justify"/>`, `<w:vAlign w:val="bottom"/>`, `<w:vAlign w:val="center"/>`} {
+		if !strings.Contains(doc.document, want) {
+			t.Errorf("document missing %s", want)
+		}
+	}
+	if n := strings.Count(doc.document, `<w:vAlign w:val="bottom"/>`); n != 2 {
+		t.Errorf("cells inheriting row valign = %d, want 2", n)
+	}
+}
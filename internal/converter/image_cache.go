@@ -0,0 +1,312 @@
+package converter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"md2word/internal/i18n"
+)
+
+// imageCacheEntry 缓存条目的元数据，与图片数据分文件保存
+type imageCacheEntry struct {
+	URL          string    `json:"url"`
+	ContentType  string    `json:"contentType"`
+	FetchedAt    time.Time `json:"fetchedAt"`
+	ExpiresAt    time.Time `json:"expiresAt"` // 零值表示不过期
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+}
+
+// fresh 判断缓存是否仍在有效期内
+func (e *imageCacheEntry) fresh(now time.Time) bool {
+	return e.ExpiresAt.IsZero() || now.Before(e.ExpiresAt)
+}
+
+// staleRetention 过期但可重新验证的条目在缓存中保留的时长
+const staleRetention = 7 * 24 * time.Hour
+
+// revalidatable 判断过期条目仍可用条件请求重新验证：带有验证器且过期未超过 staleRetention
+func (e *imageCacheEntry) revalidatable(now time.Time) bool {
+	return (e.ETag != "" || e.LastModified != "") && now.Sub(e.ExpiresAt) < staleRetention
+}
+
+// imageCache 以 URL 的 SHA-256 为键的网络图片磁盘缓存
+type imageCache struct {
+	dir      string
+	ttl      time.Duration
+	maxBytes int64 // 缓存总大小上限, 0 表示不限制
+}
+
+// imageCacheFor 返回本次转换使用的缓存，未开启时返回 nil。
+// 首次使用时清理一次缓存目录：删除无法重新验证的过期条目，并在超出大小上限时按获取时间从旧到新淘汰。
+func (c *Converter) imageCacheFor() *imageCache {
+	c.imageCacheOnce.Do(func() {
+		if !c.config.Images.Cache {
+			return
+		}
+		dir := c.config.Images.CacheDir
+		if dir == "" {
+			dir = filepath.Join(os.TempDir(), "md2word-image-cache")
+		}
+		c.imageCache = &imageCache{
+			dir:      dir,
+			ttl:      time.Duration(c.config.Images.CacheTTL) * time.Second,
+			maxBytes: int64(c.config.Images.CacheMaxSize) << 20,
+		}
+		if err := c.imageCache.prune(time.Now()); err != nil {
+			c.warn(i18n.ImageCachePrune, err)
+		}
+	})
+	return c.imageCache
+}
+
+func (ic *imageCache) paths(url string) (dataPath, metaPath string) {
+	sum := sha256.Sum256([]byte(url))
+	key := hex.EncodeToString(sum[:])
+	return filepath.Join(ic.dir, key+".bin"), filepath.Join(ic.dir, key+".json")
+}
+
+// load 读取缓存条目，不存在或已损坏时返回 nil
+func (ic *imageCache) load(url string) (*imageCacheEntry, []byte) {
+	dataPath, metaPath := ic.paths(url)
+	meta, err := os.ReadFile(metaPath)
+	if err != nil {
+		return nil, nil
+	}
+	var entry imageCacheEntry
+	if err := json.Unmarshal(meta, &entry); err != nil || entry.URL != url {
+		return nil, nil
+	}
+	data, err := os.ReadFile(dataPath)
+	if err != nil {
+		return nil, nil
+	}
+	return &entry, data
+}
+
+// store 写入缓存条目；先写临时文件再重命名，避免并发转换读到半个文件
+func (ic *imageCache) store(entry *imageCacheEntry, data []byte) error {
+	if err := os.MkdirAll(ic.dir, 0755); err != nil {
+		return err
+	}
+	meta, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	dataPath, metaPath := ic.paths(entry.URL)
+	if data != nil {
+		if err := writeFileAtomic(dataPath, data); err != nil {
+			return err
+		}
+	}
+	return writeFileAtomic(metaPath, meta)
+}
+
+// prune 删除无法重新验证的过期条目、残留的临时文件与缺少元数据的数据文件，再把总大小压到上限以内
+func (ic *imageCache) prune(now time.Time) error {
+	files, err := os.ReadDir(ic.dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	type cached struct {
+		key       string
+		size      int64
+		fetchedAt time.Time
+	}
+	var kept []cached
+	var total int64
+	for _, f := range files {
+		name := f.Name()
+		path := filepath.Join(ic.dir, name)
+		switch {
+		case strings.Contains(name, ".tmp-"):
+			// 写入中途退出留下的临时文件，一小时后视为残留
+			if info, err := f.Info(); err == nil && now.Sub(info.ModTime()) > time.Hour {
+				os.Remove(path)
+			}
+			continue
+		case strings.HasSuffix(name, ".bin"):
+			if _, err := os.Stat(strings.TrimSuffix(path, ".bin") + ".json"); os.IsNotExist(err) {
+				os.Remove(path)
+			}
+			continue
+		case !strings.HasSuffix(name, ".json"):
+			continue
+		}
+
+		key := strings.TrimSuffix(name, ".json")
+		dataPath := filepath.Join(ic.dir, key+".bin")
+		meta, err := os.ReadFile(path)
+		var entry imageCacheEntry
+		if err == nil {
+			err = json.Unmarshal(meta, &entry)
+		}
+		// 过期条目带有 ETag/Last-Modified 时保留一段时间，供下次使用时条件请求重新验证；
+		// 其余过期条目与超过保留期的条目删除，避免缓存无限增长
+		if err != nil || !entry.fresh(now) && !entry.revalidatable(now) {
+			os.Remove(path)
+			os.Remove(dataPath)
+			continue
+		}
+		info, err := os.Stat(dataPath)
+		if err != nil {
+			os.Remove(path)
+			continue
+		}
+		size := info.Size() + int64(len(meta))
+		kept = append(kept, cached{key: key, size: size, fetchedAt: entry.FetchedAt})
+		total += size
+	}
+
+	if ic.maxBytes <= 0 || total <= ic.maxBytes {
+		return nil
+	}
+	sort.Slice(kept, func(i, j int) bool { return kept[i].fetchedAt.Before(kept[j].fetchedAt) })
+	for _, e := range kept {
+		if total <= ic.maxBytes {
+			break
+		}
+		os.Remove(filepath.Join(ic.dir, e.key+".json"))
+		os.Remove(filepath.Join(ic.dir, e.key+".bin"))
+		total -= e.size
+	}
+	return nil
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// expiry 根据响应的缓存头计算过期时间，返回 store=false 表示不应缓存。
+// 优先级：Cache-Control no-store > no-cache > max-age > Expires > 配置的 TTL。
+func (ic *imageCache) expiry(h http.Header, now time.Time) (expires time.Time, store bool) {
+	noCache, maxAge := false, -1
+	for _, directive := range strings.Split(h.Get("Cache-Control"), ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		switch {
+		case directive == "no-store":
+			return time.Time{}, false
+		case directive == "no-cache":
+			noCache = true
+		case strings.HasPrefix(directive, "max-age="):
+			if secs, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age=")); err == nil {
+				maxAge = secs
+			}
+		}
+	}
+	if noCache {
+		// 可以缓存，但每次使用前都需重新验证
+		return now, true
+	}
+	if maxAge >= 0 {
+		return now.Add(time.Duration(maxAge) * time.Second), true
+	}
+	if v := h.Get("Expires"); v != "" {
+		if t, err := http.ParseTime(v); err == nil {
+			return t, true
+		}
+		// 无法解析的 Expires 视为已过期
+		return now, true
+	}
+	if ic.ttl > 0 {
+		return now.Add(ic.ttl), true
+	}
+	return time.Time{}, true
+}
+
+// downloadImage 下载网络图片；开启缓存时有效期内直接使用缓存，过期后带条件请求重新验证
+func (c *Converter) downloadImage(url string) ([]byte, string, error) {
+	cache := c.imageCacheFor()
+	now := time.Now()
+
+	var cached *imageCacheEntry
+	var cachedData []byte
+	if cache != nil {
+		cached, cachedData = cache.load(url)
+		if cached != nil && cached.fresh(now) {
+			return cachedData, cached.ContentType, nil
+		}
+	}
+
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if cached != nil {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	client := &http.Client{
+		Timeout: time.Duration(c.config.Images.DownloadTimeout) * time.Second,
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	// 缓存仍然有效：刷新过期时间后使用缓存数据
+	if cached != nil && resp.StatusCode == http.StatusNotModified {
+		if expires, ok := cache.expiry(resp.Header, now); ok {
+			cached.FetchedAt, cached.ExpiresAt = now, expires
+			if err := cache.store(cached, nil); err != nil {
+				c.warn(i18n.ImageCacheStore, err)
+			}
+		}
+		return cachedData, cached.ContentType, nil
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	contentType := resp.Header.Get("Content-Type")
+
+	if cache != nil && resp.StatusCode == http.StatusOK {
+		if expires, ok := cache.expiry(resp.Header, now); ok {
+			entry := &imageCacheEntry{
+				URL:          url,
+				ContentType:  contentType,
+				FetchedAt:    now,
+				ExpiresAt:    expires,
+				ETag:         resp.Header.Get("ETag"),
+				LastModified: resp.Header.Get("Last-Modified"),
+			}
+			if err := cache.store(entry, data); err != nil {
+				c.warn(i18n.ImageCacheStore, err)
+			}
+		}
+	}
+	return data, contentType, nil
+}
@@ -0,0 +1,208 @@
+package converter
+
+import (
+	"context"
+	"image/color"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"md2word/internal/config"
+	"md2word/internal/i18n"
+)
+
+// imageServer 返回提供一张 PNG 图片的测试服务器；handle 可设置响应头或改写响应，返回 true 表示已自行响应
+func imageServer(t *testing.T, handle func(w http.ResponseWriter, r *http.Request) bool) (*httptest.Server, *int) {
+	t.Helper()
+	png := makePNG(t, 4, 4, func(x, y int) color.NRGBA { return color.NRGBA{200, 0, 0, 255} })
+	hits := new(int)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*hits++
+		if handle != nil && handle(w, r) {
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(png)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, hits
+}
+
+// buildWithCache 以 cacheDir 为图片缓存目录转换 md，返回转换器以便检查警告
+func buildWithCache(t *testing.T, md, cacheDir string) *Converter {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.Mermaid.Enabled = false
+	cfg.Images.Cache = true
+	cfg.Images.CacheDir = cacheDir
+	c := NewConverter(cfg)
+	if _, err := c.Build(context.Background(), []byte(md), t.TempDir()); err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	return c
+}
+
+func TestImageCacheWarningsReachResult(t *testing.T) {
+	srv, _ := imageServer(t, nil)
+	// 缓存目录被同名文件占用：清理与写入都会失败
+	blocked := filepath.Join(t.TempDir(), "cache")
+	if err := os.WriteFile(blocked, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c := buildWithCache(t, "![图]("+srv.URL+"/a.png)\n", blocked)
+	prune := strings.SplitN(i18n.T(i18n.LangZH, i18n.ImageCachePrune), "%", 2)[0]
+	store := strings.SplitN(i18n.T(i18n.LangZH, i18n.ImageCacheStore), "%", 2)[0]
+	warnings := strings.Join(c.warnings, "\n")
+	if !strings.Contains(warnings, prune) || !strings.Contains(warnings, store) {
+		t.Errorf("cache warnings missing from result: %q", c.warnings)
+	}
+}
+
+func TestImageCacheHitAndMiss(t *testing.T) {
+	srv, hits := imageServer(t, func(w http.ResponseWriter, r *http.Request) bool {
+		w.Header().Set("Cache-Control", "max-age=3600")
+		return false
+	})
+	dir := t.TempDir()
+	md := "![图](" + srv.URL + "/a.png)\n"
+	for range 2 {
+		if c := buildWithCache(t, md, dir); len(c.warnings) > 0 {
+			t.Fatalf("warnings: %q", c.warnings)
+		}
+	}
+	if *hits != 1 {
+		t.Errorf("requests = %d, want 1 (second conversion served from cache)", *hits)
+	}
+	buildWithCache(t, "![图]("+srv.URL+"/b.png)\n", dir)
+	if *hits != 2 {
+		t.Errorf("requests = %d, want 2 (uncached URL downloaded)", *hits)
+	}
+}
+
+func TestImageCacheRevalidation(t *testing.T) {
+	var conditional string
+	srv, hits := imageServer(t, func(w http.ResponseWriter, r *http.Request) bool {
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("ETag", `"v1"`)
+		if conditional = r.Header.Get("If-None-Match"); conditional == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return true
+		}
+		return false
+	})
+	dir := t.TempDir()
+	md := "![图](" + srv.URL + "/a.png)\n"
+	buildWithCache(t, md, dir)
+	c := buildWithCache(t, md, dir)
+	if *hits != 2 || conditional != `"v1"` {
+		t.Errorf("requests = %d, If-None-Match = %q; want a conditional second request", *hits, conditional)
+	}
+	if len(c.warnings) > 0 {
+		t.Errorf("304 response should reuse the cached image, got warnings %q", c.warnings)
+	}
+}
+
+func TestImageCacheNoStore(t *testing.T) {
+	srv, hits := imageServer(t, func(w http.ResponseWriter, r *http.Request) bool {
+		w.Header().Set("Cache-Control", "no-store, max-age=3600")
+		return false
+	})
+	dir := t.TempDir()
+	md := "![图](" + srv.URL + "/a.png)\n"
+	buildWithCache(t, md, dir)
+	buildWithCache(t, md, dir)
+	if *hits != 2 {
+		t.Errorf("requests = %d, want 2", *hits)
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("no-store response was cached: %d files", len(files))
+	}
+}
+
+func TestImageCacheExpiry(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	expires := now.Add(2 * time.Hour).Format(http.TimeFormat)
+	ic := &imageCache{ttl: time.Minute}
+	tests := []struct {
+		name    string
+		header  map[string]string
+		want    time.Time
+		noStore bool
+	}{
+		{"ttl", nil, now.Add(time.Minute), false},
+		{"expires", map[string]string{"Expires": expires}, now.Add(2 * time.Hour), false},
+		{"bad expires", map[string]string{"Expires": "0"}, now, false},
+		{"max-age over expires", map[string]string{"Cache-Control": "public, max-age=60", "Expires": expires}, now.Add(time.Minute), false},
+		{"no-cache", map[string]string{"Cache-Control": "no-cache", "Expires": expires}, now, false},
+		{"no-store", map[string]string{"Cache-Control": "max-age=60, no-store"}, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		h := http.Header{}
+		for k, v := range tt.header {
+			h.Set(k, v)
+		}
+		got, store := ic.expiry(h, now)
+		if store == tt.noStore || !got.Equal(tt.want) {
+			t.Errorf("%s: expiry = %v, store = %v; want %v, %v", tt.name, got, store, tt.want, !tt.noStore)
+		}
+	}
+}
+
+func TestImageCachePrune(t *testing.T) {
+	now := time.Now()
+	ic := &imageCache{dir: t.TempDir()}
+	store := func(url string, fetched, expires time.Time, size int) {
+		t.Helper()
+		entry := &imageCacheEntry{URL: url, FetchedAt: fetched, ExpiresAt: expires}
+		if err := ic.store(entry, make([]byte, size)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	exists := func(url string) bool {
+		entry, _ := ic.load(url)
+		return entry != nil
+	}
+	store("expired", now.Add(-2*time.Hour), now.Add(-time.Hour), 10)
+	stale := &imageCacheEntry{URL: "stale", FetchedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour), ETag: `"v1"`}
+	if err := ic.store(stale, make([]byte, 10)); err != nil {
+		t.Fatal(err)
+	}
+	store("old", now.Add(-time.Hour), time.Time{}, 600)
+	store("new", now, time.Time{}, 600)
+	orphan, _ := ic.paths("orphan")
+	oldTmp := filepath.Join(ic.dir, "x.bin.tmp-1")
+	newTmp := filepath.Join(ic.dir, "y.bin.tmp-2")
+	for _, path := range []string{orphan, oldTmp, newTmp} {
+		if err := os.WriteFile(path, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Chtimes(oldTmp, now.Add(-2*time.Hour), now.Add(-2*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ic.prune(now); err != nil {
+		t.Fatal(err)
+	}
+	if exists("expired") || !exists("stale") || !exists("old") || !exists("new") {
+		t.Error("prune should drop only the expired entry without validators")
+	}
+	for path, want := range map[string]bool{orphan: false, oldTmp: false, newTmp: true} {
+		if _, err := os.Stat(path); (err == nil) != want {
+			t.Errorf("%s: exists = %v, want %v", filepath.Base(path), err == nil, want)
+		}
+	}
+
+	// 超出大小上限时先淘汰最早获取的条目
+	ic.maxBytes = 1100
+	if err := ic.prune(now); err != nil {
+		t.Fatal(err)
+	}
+	if exists("old") || !exists("new") {
+		t.Error("size cap should evict the oldest entry first")
+	}
+}
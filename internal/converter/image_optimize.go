@@ -0,0 +1,216 @@
+package converter
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"math"
+
+	"golang.org/x/image/draw"
+)
+
+const (
+	// minOptimizeBytes 小于该大小的 PNG 不值得重新编码
+	minOptimizeBytes = 64 << 10
+	// photoColorThreshold 采样点中不同颜色数超过该值视为照片
+	photoColorThreshold = 4096
+	// photoSampleGrid 颜色采样网格的边长，最多采样 photoSampleGrid² 个像素
+	photoSampleGrid = 256
+	// defaultJPEGQuality 未配置 images.jpegQuality 时的 JPEG 质量
+	defaultJPEGQuality = 85
+)
+
+// transcodeFormats 嵌入前转换为 PNG 的图片格式（image.Decode 返回的格式名）
+var transcodeFormats = map[string]bool{"bmp": true, "tiff": true}
+
+// transcodeImage 把 BMP、TIFF 转换为 PNG：体积更小，且各版本 Word 都能显示。
+// 其他格式或解码失败时原样返回
+func transcodeImage(data []byte, contentType string) ([]byte, string) {
+	if _, format, err := image.DecodeConfig(bytes.NewReader(data)); err != nil || !transcodeFormats[format] {
+		return data, contentType
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return data, contentType
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return data, contentType
+	}
+	return buf.Bytes(), "image/png"
+}
+
+// optimizeImage 在 images.optimize 开启时把照片类 PNG 重新编码为 JPEG，返回新的数据与内容类型。
+// 截图、图表等颜色较少的图片以及带透明度的图片保持 PNG；重新编码后没有变小时也保留原图。
+func (c *Converter) optimizeImage(data []byte, contentType string) ([]byte, string) {
+	if !c.config.Images.Optimize || contentType != "image/png" || len(data) < minOptimizeBytes {
+		return data, contentType
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil || !isOpaque(img) || !isPhotographic(img) {
+		return data, contentType
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: c.jpegQuality()}); err != nil || buf.Len() >= len(data) {
+		return data, contentType
+	}
+	return buf.Bytes(), "image/jpeg"
+}
+
+// jpegQuality 返回配置的 JPEG 质量 (1-100)
+func (c *Converter) jpegQuality() int {
+	q := c.config.Images.JPEGQuality
+	if q <= 0 || q > 100 {
+		return defaultJPEGQuality
+	}
+	return q
+}
+
+// isOpaque 判断图片是否完全不透明
+func isOpaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if _, _, _, a := img.At(x, y).RGBA(); a != 0xffff {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isPhotographic 在均匀网格上采样像素，不同颜色数足够多时视为照片
+func isPhotographic(img image.Image) bool {
+	b := img.Bounds()
+	stepX := max(b.Dx()/photoSampleGrid, 1)
+	stepY := max(b.Dy()/photoSampleGrid, 1)
+	colors := make(map[uint32]struct{})
+	for y := b.Min.Y; y < b.Max.Y; y += stepY {
+		for x := b.Min.X; x < b.Max.X; x += stepX {
+			r, g, bl, _ := img.At(x, y).RGBA()
+			colors[(r>>8)<<16|(g>>8)<<8|bl>>8] = struct{}{}
+			if len(colors) > photoColorThreshold {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// minLimitedSide 按字节预算缩小时图片短边的下限 (像素)
+const minLimitedSide = 16
+
+// limitImage 按 images.maxPixels 与 images.maxBytes 缩小并重新压缩图片，保持宽高比。
+// 只影响嵌入文档的数据，显示尺寸由调用方按原图计算。
+// JPEG 先按配置的质量重新压缩，其余格式重新编码为 PNG；无法解码的图片（如 SVG）原样返回。
+// changed 表示返回的是重新编码后的图片。
+func (c *Converter) limitImage(data []byte, contentType string) (out []byte, outType string, changed bool) {
+	maxPixels, maxBytes := c.config.Images.MaxPixels, c.config.Images.MaxBytes
+	if maxPixels <= 0 && maxBytes <= 0 {
+		return data, contentType, false
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return data, contentType, false
+	}
+
+	b := img.Bounds()
+	pixels := b.Dx() * b.Dy()
+	overPixels := maxPixels > 0 && pixels > maxPixels
+	overBytes := maxBytes > 0 && len(data) > maxBytes
+	if !overPixels && !overBytes {
+		return data, contentType, false
+	}
+
+	encode := encodePNG
+	outType = "image/png"
+	if contentType == "image/jpeg" {
+		encode = func(img image.Image) ([]byte, error) { return encodeJPEG(img, c.jpegQuality()) }
+		outType = "image/jpeg"
+	}
+
+	if overPixels {
+		img = scaleImage(img, math.Sqrt(float64(maxPixels)/float64(pixels)))
+	}
+	out, err = encode(img)
+	if err != nil {
+		return data, contentType, false
+	}
+
+	// 仍超出字节预算时按比例继续缩小，直到满足预算或图片过小
+	for maxBytes > 0 && len(out) > maxBytes {
+		b := img.Bounds()
+		factor := math.Max(math.Min(math.Sqrt(float64(maxBytes)/float64(len(out)))*0.9, 0.9), 0.25)
+		if float64(min(b.Dx(), b.Dy()))*factor < minLimitedSide {
+			break
+		}
+		img = scaleImage(img, factor)
+		if out, err = encode(img); err != nil {
+			return data, contentType, false
+		}
+	}
+
+	if !overPixels && len(out) >= len(data) {
+		return data, contentType, false
+	}
+	return out, outType, true
+}
+
+// scaleImage 按比例缩放图片
+func scaleImage(img image.Image, factor float64) image.Image {
+	b := img.Bounds()
+	w := max(int(float64(b.Dx())*factor), 1)
+	h := max(int(float64(b.Dy())*factor), 1)
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
+
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	err := png.Encode(&buf, img)
+	return buf.Bytes(), err
+}
+
+func encodeJPEG(img image.Image, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+	return buf.Bytes(), err
+}
+
+// whiteToAlpha 把 PNG 的白色背景转为透明：每个像素按与白色的差异取不透明度，
+// 抗锯齿边缘的浅色像素随之变为半透明的深色，放在任意底色上都不会出现白边。
+// 无法解码时返回原数据
+func whiteToAlpha(data []byte) []byte {
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return data
+	}
+	b := img.Bounds()
+	dst := image.NewNRGBA(b)
+	draw.Draw(dst, b, img, b.Min, draw.Src)
+	for i := 0; i < len(dst.Pix); i += 4 {
+		px := dst.Pix[i : i+4 : i+4]
+		// 不透明度取各通道与白色差距的最大值
+		alpha := 255 - min(px[0], px[1], px[2])
+		if alpha == 0 {
+			px[3] = 0
+			continue
+		}
+		for j := 0; j < 3; j++ {
+			// 去掉白色成分后的颜色: c' = 255 - (255-c)*255/alpha
+			px[j] = uint8(255 - (255-int(px[j]))*255/int(alpha))
+		}
+		px[3] = uint8(int(px[3]) * int(alpha) / 255)
+	}
+	out, err := encodePNG(dst)
+	if err != nil {
+		return data
+	}
+	return out
+}
@@ -0,0 +1,169 @@
+package converter
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/bmp"
+	"golang.org/x/image/tiff"
+
+	"md2word/internal/config"
+)
+
+// makePNG 生成 w×h 的 PNG，像素颜色由 fill 决定
+func makePNG(t *testing.T, w, h int, fill func(x, y int) color.NRGBA) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetNRGBA(x, y, fill(x, y))
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestOptimizeImage(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	noise := func(x, y int) color.NRGBA {
+		return color.NRGBA{uint8(rng.Intn(256)), uint8(rng.Intn(256)), uint8(rng.Intn(256)), 255}
+	}
+	stripes := func(x, y int) color.NRGBA {
+		if (x/20)%2 == 0 {
+			return color.NRGBA{255, 255, 255, 255}
+		}
+		return color.NRGBA{30, 30, 30, 255}
+	}
+	translucent := func(x, y int) color.NRGBA {
+		c := noise(x, y)
+		c.A = 128
+		return c
+	}
+
+	tests := []struct {
+		name     string
+		data     []byte
+		optimize bool
+		want     string
+	}{
+		{"photo", makePNG(t, 400, 400, noise), true, "image/jpeg"},
+		{"photo disabled", makePNG(t, 400, 400, noise), false, "image/png"},
+		{"screenshot", makePNG(t, 2000, 2000, stripes), true, "image/png"},
+		{"transparent photo", makePNG(t, 400, 400, translucent), true, "image/png"},
+		{"small photo", makePNG(t, 40, 40, noise), true, "image/png"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Images.Optimize = tt.optimize
+			data, contentType := NewConverter(cfg).optimizeImage(tt.data, "image/png")
+			if contentType != tt.want {
+				t.Fatalf("content type = %s, want %s", contentType, tt.want)
+			}
+			if contentType == "image/jpeg" && len(data) >= len(tt.data) {
+				t.Errorf("JPEG (%d bytes) is not smaller than PNG (%d bytes)", len(data), len(tt.data))
+			}
+		})
+	}
+}
+
+func TestLimitImage(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	noise := func(x, y int) color.NRGBA {
+		return color.NRGBA{uint8(rng.Intn(256)), uint8(rng.Intn(256)), uint8(rng.Intn(256)), 255}
+	}
+	data := makePNG(t, 400, 300, noise)
+
+	t.Run("disabled", func(t *testing.T) {
+		if _, _, changed := NewConverter(config.DefaultConfig()).limitImage(data, "image/png"); changed {
+			t.Fatal("image changed without limits")
+		}
+	})
+
+	t.Run("maxPixels", func(t *testing.T) {
+		cfg := config.DefaultConfig()
+		cfg.Images.MaxPixels = 30000
+		out, contentType, changed := NewConverter(cfg).limitImage(data, "image/png")
+		if !changed || contentType != "image/png" {
+			t.Fatalf("changed = %v, content type = %s", changed, contentType)
+		}
+		img, _, err := image.Decode(bytes.NewReader(out))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b := img.Bounds(); b.Dx()*b.Dy() > 30000 {
+			t.Errorf("size = %dx%d, want at most 30000 pixels", b.Dx(), b.Dy())
+		}
+	})
+
+	t.Run("maxBytes", func(t *testing.T) {
+		cfg := config.DefaultConfig()
+		cfg.Images.MaxBytes = len(data) / 4
+		out, _, changed := NewConverter(cfg).limitImage(data, "image/png")
+		if !changed || len(out) > cfg.Images.MaxBytes {
+			t.Errorf("changed = %v, %d bytes, want at most %d", changed, len(out), cfg.Images.MaxBytes)
+		}
+	})
+}
+
+func TestWhiteToAlpha(t *testing.T) {
+	src := makePNG(t, 3, 1, func(x, y int) color.NRGBA {
+		v := []uint8{255, 0, 128}[x]
+		return color.NRGBA{v, v, v, 255}
+	})
+	img, err := png.Decode(bytes.NewReader(whiteToAlpha(src)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []color.NRGBA{{0, 0, 0, 0}, {0, 0, 0, 255}, {0, 0, 0, 127}}
+	for x, w := range want {
+		got := color.NRGBAModel.Convert(img.At(x, 0)).(color.NRGBA)
+		if got.A != w.A || (w.A != 0 && got.R != w.R) {
+			t.Errorf("pixel %d = %v, want %v", x, got, w)
+		}
+	}
+}
+
+func TestTranscodeBMPAndTIFF(t *testing.T) {
+	src, err := png.Decode(bytes.NewReader(makePNG(t, 30, 20, func(x, y int) color.NRGBA {
+		return color.NRGBA{uint8(x * 8), uint8(y * 12), 0, 255}
+	})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoders := map[string]func(io.Writer, image.Image) error{
+		"bmp":  bmp.Encode,
+		"tiff": func(w io.Writer, m image.Image) error { return tiff.Encode(w, m, nil) },
+	}
+	for format, encode := range encoders {
+		var buf bytes.Buffer
+		if err := encode(&buf, src); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(t.TempDir(), "scan."+format)
+		if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		doc := convertMarkdown(t, "![scan]("+path+")\n", nil)
+		data, ok := doc.parts["word/media/image1.png"]
+		if !ok {
+			t.Errorf("%s: image not embedded as png", format)
+			continue
+		}
+		cfg, err := png.DecodeConfig(strings.NewReader(data))
+		if err != nil || cfg.Width != 30 || cfg.Height != 20 {
+			t.Errorf("%s: embedded png = %+v, %v", format, cfg, err)
+		}
+	}
+}
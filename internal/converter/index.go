@@ -0,0 +1,23 @@
+package converter
+
+import (
+	"md2word/internal/docx"
+	"md2word/internal/parser"
+)
+
+// indexInstr INDEX 域代码：两栏，按简体中文 (2052) 规则排序
+const indexInstr = `INDEX \c "2" \z "2052"`
+
+// processIndexEntry 把 {index: 术语} 输出为 XE 域，{INDEX} 输出为 INDEX 域；
+// 域只能位于段落中，表格单元格等其他容器内的标记被忽略
+func (c *Converter) processIndexEntry(node *parser.IndexEntry, p docx.RunContainer) {
+	para, ok := p.(*docx.Paragraph)
+	if !ok {
+		return
+	}
+	if node.Term == nil {
+		para.AddField(indexInstr, "右键单击并选择“更新域”以生成索引")
+		return
+	}
+	para.AddField("XE "+docx.FieldQuote(string(node.Term)), "")
+}
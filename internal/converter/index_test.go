@@ -0,0 +1,31 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+
+	"md2word/internal/config"
+)
+
+func TestIndexFields(t *testing.T) {
+	md := "部署{index: 部署:容器}说明{index: \"K8s\"}。\n\n{INDEX}\n"
+
+	doc := convertMarkdown(t, md, nil)
+	for _, want := range []string{
+		`> XE &#34;部署:容器&#34; </w:instrText>`,
+		`> XE &#34;\&#34;K8s\&#34;&#34; </w:instrText>`,
+		`> INDEX \c &#34;2&#34; \z &#34;2052&#34; </w:instrText>`,
+	} {
+		if !strings.Contains(doc.document, want) {
+			t.Errorf("document missing %s", want)
+		}
+	}
+	if texts := strings.Join(doc.texts(t), ""); strings.Contains(texts, "{index") || !strings.HasPrefix(texts, "部署说明。") {
+		t.Errorf("texts = %q", texts)
+	}
+
+	doc = convertMarkdown(t, md, func(cfg *config.Config) { cfg.Index.Enabled = false })
+	if strings.Contains(doc.document, "XE ") {
+		t.Error("XE field emitted while index is disabled")
+	}
+}
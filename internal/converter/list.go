@@ -0,0 +1,39 @@
+package converter
+
+import (
+	"strconv"
+
+	"md2word/internal/config"
+	"md2word/internal/docx"
+)
+
+// wordListFormats styles.list.orderedFormats 取值对应的 Word 编号格式
+var wordListFormats = map[string]string{
+	config.ListFormatDecimal:    docx.ListFormatDecimal,
+	config.ListFormatLowerAlpha: docx.ListFormatLowerLetter,
+	config.ListFormatUpperAlpha: docx.ListFormatUpperLetter,
+	config.ListFormatLowerRoman: docx.ListFormatLowerRoman,
+	config.ListFormatUpperRoman: docx.ListFormatUpperRoman,
+}
+
+// bulletSymbols 无序列表各级的项目符号，更深的层级循环使用
+var bulletSymbols = []string{"•", "◦", "▪"}
+
+// listLevels 返回列表 0~8 级的编号格式：有序列表按 styles.list.orderedFormats
+// （更深的层级沿用最后一项）显示为 "1." 等，无序列表为项目符号；序号与符号均加粗
+func (c *Converter) listLevels(ordered bool) []docx.ListLevel {
+	levels := make([]docx.ListLevel, 9)
+	formats := c.config.Styles.List.OrderedFormats
+	for i := range levels {
+		if !ordered {
+			levels[i] = docx.ListLevel{Format: docx.ListFormatBullet, Text: bulletSymbols[i%len(bulletSymbols)], Bold: true}
+			continue
+		}
+		format := config.ListFormatDecimal
+		if len(formats) > 0 {
+			format = formats[min(i, len(formats)-1)]
+		}
+		levels[i] = docx.ListLevel{Format: wordListFormats[format], Text: "%" + strconv.Itoa(i+1) + ".", Bold: true}
+	}
+	return levels
+}
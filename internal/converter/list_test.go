@@ -0,0 +1,118 @@
+package converter
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"md2word/internal/config"
+)
+
+// listItems 返回各列表项段落的 "文字:级别:所在列表的起始序号"，起始序号取自 numbering.xml 的 startOverride
+func listItems(t *testing.T, doc *convertedDoc) []string {
+	t.Helper()
+	starts := make(map[string]string)
+	for _, m := range regexp.MustCompile(`(?s)<w:num w:numId="(\d+)">.*?<w:startOverride w:val="(\d+)"/>`).FindAllStringSubmatch(doc.parts["word/numbering.xml"], -1) {
+		starts[m[1]] = m[2]
+	}
+	item := regexp.MustCompile(`(?s)<w:ilvl w:val="(\d+)"/><w:numId w:val="(\d+)"/>.*?<w:t[^>]*>([^<]*)</w:t>`)
+	var items []string
+	for _, p := range strings.Split(doc.document, "</w:p>") {
+		if m := item.FindStringSubmatch(p); m != nil {
+			start, ok := starts[m[2]]
+			if !ok {
+				t.Fatalf("numId %s has no num definition", m[2])
+			}
+			items = append(items, m[3]+":"+m[1]+":"+start)
+		}
+	}
+	return items
+}
+
+func TestOrderedListFormats(t *testing.T) {
+	md := "3. 第三步\n4. 第四步\n   1. 子项\n   2. 子项\n      1. 孙项\n"
+	doc := convertMarkdown(t, md, func(cfg *config.Config) {
+		cfg.Styles.List.OrderedFormats = []string{config.ListFormatDecimal, config.ListFormatLowerAlpha}
+	})
+	want := []string{"第三步:0:3", "第四步:0:3", "子项:1:1", "子项:1:1", "孙项:2:1"}
+	if got := listItems(t, doc); !slices.Equal(got, want) {
+		t.Errorf("list items = %q, want %q", got, want)
+	}
+	numbering := doc.parts["word/numbering.xml"]
+	for _, want := range []string{
+		`<w:lvl w:ilvl="0">
+            <w:start w:val="1"/>
+            <w:numFmt w:val="decimal"/>
+            <w:lvlText w:val="%1."/>`,
+		`<w:numFmt w:val="lowerLetter"/>
+            <w:lvlText w:val="%2."/>`,
+		`<w:numFmt w:val="lowerLetter"/>
+            <w:lvlText w:val="%3."/>`,
+	} {
+		if !strings.Contains(numbering, want) {
+			t.Errorf("numbering.xml missing %s", want)
+		}
+	}
+	if texts := strings.Join(doc.texts(t), "|"); strings.Contains(texts, "3.") || strings.Contains(texts, "a.") {
+		t.Errorf("numbers baked into text: %q", texts)
+	}
+}
+
+func TestBulletListNumbering(t *testing.T) {
+	doc := convertMarkdown(t, "- 甲\n  - 乙\n", nil)
+	if want := []string{"甲:0:1", "乙:1:1"}; !slices.Equal(listItems(t, doc), want) {
+		t.Errorf("list items = %q, want %q", listItems(t, doc), want)
+	}
+	numbering := doc.parts["word/numbering.xml"]
+	for _, want := range []string{`<w:numFmt w:val="bullet"/>
+            <w:lvlText w:val="•"/>`, `<w:lvlText w:val="◦"/>`, `<w:ind w:left="840" w:hanging="420"/>`} {
+		if !strings.Contains(numbering, want) {
+			t.Errorf("numbering.xml missing %s", want)
+		}
+	}
+	if strings.Contains(strings.Join(doc.texts(t), ""), "•") {
+		t.Error("bullet baked into text")
+	}
+}
+
+func TestOrderedListContinueNumbering(t *testing.T) {
+	md := "1. 第一步\n2. 第二步\n\n注意事项\n\n1. 第三步\n\n# 下一节\n\n1. 重新开始\n"
+	doc := convertMarkdown(t, md, func(cfg *config.Config) { cfg.Styles.List.ContinueNumbering = true })
+	items := listItems(t, doc)
+	for _, want := range []string{"第三步:0:3", "重新开始:0:1"} {
+		if !slices.Contains(items, want) {
+			t.Errorf("list items %q missing %q", items, want)
+		}
+	}
+
+	doc = convertMarkdown(t, md, nil)
+	if items := listItems(t, doc); !slices.Contains(items, "第三步:0:1") {
+		t.Errorf("numbering continued without continueNumbering: %q", items)
+	}
+}
+
+func TestListSpacing(t *testing.T) {
+	md := "正文\n\n- 甲\n- 乙\n  - 丙\n\n正文\n"
+	doc := convertMarkdown(t, md, func(cfg *config.Config) {
+		cfg.Styles.List.SpaceBefore = 120
+		cfg.Styles.List.SpaceAfter = 240
+	})
+	var before, after []string
+	for _, p := range strings.Split(doc.document, "</w:p>") {
+		for _, item := range []string{"甲", "乙", "丙"} {
+			if !strings.Contains(p, ">"+item+"<") {
+				continue
+			}
+			if strings.Contains(p, `w:before="120"`) {
+				before = append(before, item)
+			}
+			if strings.Contains(p, `w:after="240"`) {
+				after = append(after, item)
+			}
+		}
+	}
+	if strings.Join(before, ",") != "甲" || strings.Join(after, ",") != "丙" {
+		t.Errorf("spaceBefore on %v, spaceAfter on %v; want [甲] and [丙]", before, after)
+	}
+}
@@ -2,6 +2,7 @@ package converter
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -16,18 +17,22 @@ import (
 )
 
 // RenderMathJax 使用MathJax渲染LaTeX公式为图片
-func RenderMathJax(latex string, display bool) ([]byte, error) {
+// ctx 取消时终止正在运行的外部命令与网络请求
+func RenderMathJax(ctx context.Context, latex string, display bool) ([]byte, error) {
 	// 首先尝试使用本地的mathjax-node-cli
-	if data, err := renderMathJaxLocal(latex, display); err == nil {
+	if data, err := renderMathJaxLocal(ctx, latex, display); err == nil {
 		return data, nil
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	// 备用方案：使用在线服务
-	return renderMathJaxOnline(latex, display)
+	return renderMathJaxOnline(ctx, latex, display)
 }
 
 // renderMathJaxLocal 使用本地mathjax-node渲染
-func renderMathJaxLocal(latex string, display bool) ([]byte, error) {
+func renderMathJaxLocal(ctx context.Context, latex string, display bool) ([]byte, error) {
 	// 检查tex2svg是否可用
 	cmdName := "tex2svg"
 	if _, err := exec.LookPath(cmdName); err != nil {
@@ -50,22 +55,22 @@ func renderMathJaxLocal(latex string, display bool) ([]byte, error) {
 			args = append(args, "--display")
 		}
 		args = append(args, latex)
-		cmd = exec.Command(cmdName, args...)
+		cmd = exec.CommandContext(ctx, cmdName, args...)
 	} else {
 		args := []string{}
 		if display {
 			args = append(args, "--display")
 		}
 		args = append(args, latex)
-		cmd = exec.Command(cmdName, args...)
+		cmd = exec.CommandContext(ctx, cmdName, args...)
 		cmd.Stdout = nil
 	}
 
 	// 获取SVG输出
 	var svgBuf bytes.Buffer
-	cmd = exec.Command("tex2svg", latex)
+	cmd = exec.CommandContext(ctx, "tex2svg", latex)
 	if display {
-		cmd = exec.Command("tex2svg", "--display", latex)
+		cmd = exec.CommandContext(ctx, "tex2svg", "--display", latex)
 	}
 	cmd.Stdout = &svgBuf
 
@@ -74,11 +79,11 @@ func renderMathJaxLocal(latex string, display bool) ([]byte, error) {
 	}
 
 	// 将SVG转换为PNG
-	return convertSVGtoPNG(svgBuf.Bytes())
+	return convertSVGtoPNG(ctx, svgBuf.Bytes())
 }
 
 // renderMathJaxOnline 使用在线服务渲染
-func renderMathJaxOnline(latex string, display bool) ([]byte, error) {
+func renderMathJaxOnline(ctx context.Context, latex string, display bool) ([]byte, error) {
 	// 首先尝试 latex.codecogs.com 服务
 	// 注意：不要使用 url.QueryEscape，因为它会将空格编码为+，导致与LaTeX的+号混淆
 	// 手动编码特殊字符
@@ -100,33 +105,40 @@ func renderMathJaxOnline(latex string, display bool) ([]byte, error) {
 	apiURL := fmt.Sprintf("https://latex.codecogs.com/png.latex?\\dpi{%s}%s", dpi, encodedLatex)
 
 	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get(apiURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 	if err != nil {
 		// 备用方案：使用 quicklatex.com
-		return renderMathQuickLatex(latex, display)
+		return renderMathQuickLatex(ctx, latex, display)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		// 备用方案：使用 quicklatex.com
-		return renderMathQuickLatex(latex, display)
+		return renderMathQuickLatex(ctx, latex, display)
 	}
 
 	data, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return renderMathQuickLatex(latex, display)
+		return renderMathQuickLatex(ctx, latex, display)
 	}
 
 	// 检查返回的数据是否是有效的图片
 	if len(data) < 100 { // 太小可能是错误信息
-		return renderMathQuickLatex(latex, display)
+		return renderMathQuickLatex(ctx, latex, display)
 	}
 
 	return data, nil
 }
 
 // renderMathQuickLatex 使用 quicklatex.com 作为备用方案
-func renderMathQuickLatex(latex string, display bool) ([]byte, error) {
+func renderMathQuickLatex(ctx context.Context, latex string, display bool) ([]byte, error) {
 	// QuickLaTeX API
 	formData := url.Values{}
 	formData.Set("formula", latex)
@@ -140,7 +152,12 @@ func renderMathQuickLatex(latex string, display bool) ([]byte, error) {
 	formData.Set("remhost", "quicklatex.com")
 	
 	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.PostForm("https://quicklatex.com/latex3.f", formData)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://quicklatex.com/latex3.f", strings.NewReader(formData.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("quicklatex request failed: %w", err)
 	}
@@ -168,7 +185,11 @@ func renderMathQuickLatex(latex string, display bool) ([]byte, error) {
 	}
 
 	// 下载图片
-	imgResp, err := client.Get(imgURL)
+	imgReq, err := http.NewRequestWithContext(ctx, http.MethodGet, imgURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	imgResp, err := client.Do(imgReq)
 	if err != nil {
 		return nil, err
 	}
@@ -178,10 +199,10 @@ func renderMathQuickLatex(latex string, display bool) ([]byte, error) {
 }
 
 // convertSVGtoPNG 将SVG转换为PNG
-func convertSVGtoPNG(svg []byte) ([]byte, error) {
+func convertSVGtoPNG(ctx context.Context, svg []byte) ([]byte, error) {
 	// 尝试使用rsvg-convert
 	if _, err := exec.LookPath("rsvg-convert"); err == nil {
-		cmd := exec.Command("rsvg-convert", "-f", "png", "-d", "150", "-p", "150")
+		cmd := exec.CommandContext(ctx, "rsvg-convert", "-f", "png", "-d", "150", "-p", "150")
 		cmd.Stdin = bytes.NewReader(svg)
 		var out bytes.Buffer
 		cmd.Stdout = &out
@@ -205,7 +226,7 @@ func convertSVGtoPNG(svg []byte) ([]byte, error) {
 			return nil, err
 		}
 
-		cmd := exec.Command("inkscape", svgFile, "--export-type=png", "-o", pngFile, "-d", "150")
+		cmd := exec.CommandContext(ctx, "inkscape", svgFile, "--export-type=png", "-o", pngFile, "-d", "150")
 		if err := cmd.Run(); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,92 @@
+package converter
+
+import (
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"md2word/internal/config"
+)
+
+// fakeMathTools 在 PATH 前部放置假的 tex2svg 与 rsvg-convert，使公式离线渲染为固定的 PNG
+func fakeMathTools(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake tools are shell scripts")
+	}
+	dir := t.TempDir()
+	png := filepath.Join(dir, "formula.png")
+	if err := os.WriteFile(png, makePNG(t, 40, 20, func(x, y int) color.NRGBA {
+		if x > 10 && x < 30 {
+			return color.NRGBA{0, 0, 0, 255}
+		}
+		return color.NRGBA{255, 255, 255, 255}
+	}), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	scripts := map[string]string{
+		"tex2svg":      "#!/bin/sh\necho '<svg xmlns=\"http://www.w3.org/2000/svg\"/>'\n",
+		"rsvg-convert": "#!/bin/sh\ncat >/dev/null\ncat '" + png + "'\n",
+	}
+	for name, script := range scripts {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestInlineMathInListAndTable(t *testing.T) {
+	fakeMathTools(t)
+	md := "- 平方 $x^2$ 项\n  1. 嵌套 $y^2$\n\n| 公式 | 说明 |\n| --- | --- |\n| $z^2$ | 立方 |\n"
+	doc := convertMarkdown(t, md, nil)
+
+	if n := strings.Count(doc.document, "<w:drawing>"); n != 3 {
+		t.Errorf("formula images = %d, want 3", n)
+	}
+	texts := strings.Join(doc.texts(t), "")
+	if strings.Contains(texts, "$") {
+		t.Errorf("formula source left in %q", texts)
+	}
+	for _, want := range []string{"平方", "项", "嵌套", "立方"} {
+		if !strings.Contains(texts, want) {
+			t.Errorf("texts %q missing %q", texts, want)
+		}
+	}
+}
+
+func TestTransparentMath(t *testing.T) {
+	fakeMathTools(t)
+	for _, transparent := range []bool{false, true} {
+		doc := convertMarkdown(t, "面积 $x^2$\n", func(cfg *config.Config) { cfg.Math.Transparent = transparent })
+		found := false
+		for name, data := range doc.parts {
+			if !strings.HasPrefix(name, "word/media/") {
+				continue
+			}
+			found = true
+			img, err := png.Decode(strings.NewReader(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if opaque := isOpaque(img); opaque == transparent {
+				t.Errorf("transparent=%v: formula image opaque = %v", transparent, opaque)
+			}
+		}
+		if !found {
+			t.Errorf("transparent=%v: formula image missing", transparent)
+		}
+	}
+}
+
+func TestInlineMathBaselineShift(t *testing.T) {
+	fakeMathTools(t)
+	doc := convertMarkdown(t, "面积 $x^2$\n", func(cfg *config.Config) { cfg.Math.InlineBaselineShift = -3 })
+	if !strings.Contains(doc.document, `<w:position w:val="-6"/>`) {
+		t.Error("inline formula run missing w:position")
+	}
+}
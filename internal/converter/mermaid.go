@@ -1,12 +1,45 @@
 package converter
 
 import (
+	"context"
+	"encoding/base64"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"time"
 )
 
+// mermaidInkURL mermaid.ink 图片接口地址，后接 base64 编码的流程图代码
+var mermaidInkURL = "https://mermaid.ink/img/"
+
+// RenderMermaidInk 通过 mermaid.ink 在线服务把流程图渲染为 PNG，用于本机没有浏览器的情况
+func RenderMermaidInk(ctx context.Context, code string, theme string) ([]byte, error) {
+	query := url.Values{"type": {"png"}}
+	if theme != "" {
+		query.Set("theme", theme)
+	}
+	apiURL := mermaidInkURL + base64.URLEncoding.EncodeToString([]byte(code)) + "?" + query.Encode()
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("mermaid.ink 返回 %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
 // RenderMermaid 使用mermaid-cli渲染Mermaid图
 func RenderMermaid(code string, cliCmd string, theme string) ([]byte, error) {
 	if cliCmd == "" {
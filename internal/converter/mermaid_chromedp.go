@@ -3,6 +3,7 @@ package converter
 import (
 	"context"
 	_ "embed"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -31,28 +32,109 @@ func RenderMermaidChromedp(code string, theme string) ([]byte, error) {
 }
 
 func RenderMermaidWithContext(ctx context.Context, code string, theme string, width, height, scale int) ([]byte, error) {
-	if theme == "" {
-		theme = "default"
+	return renderMermaidPage(ctx, code, mermaidOptions{theme: theme, width: width, height: height, scale: scale}, nil)
+}
+
+// mermaidOptions 流程图渲染参数
+type mermaidOptions struct {
+	theme                string
+	themeVariables       map[string]string // 传给 mermaid.initialize 的 themeVariables
+	css                  string            // 以 themeCSS 写入 SVG 的自定义样式
+	fixedWidth           int               // 流程图的固定宽度（像素），0 表示按自然尺寸
+	tempDir              string            // 临时页面文件的父目录，为空时使用系统临时目录
+	width, height, scale int
+}
+
+// renderMermaidPage 在浏览器中渲染流程图并截图；svg 不为 nil 时一并取出序列化的 SVG，
+// 此时标签以 SVG 文本而非 HTML 输出，Word 不显示 foreignObject 中的内容
+func renderMermaidPage(ctx context.Context, code string, opts mermaidOptions, svg *string) ([]byte, error) {
+	htmlContent, err := mermaidHTML(code, opts, svg == nil)
+	if err != nil {
+		return nil, err
+	}
+
+	htmlPath, cleanup, err := writeMermaidPage(opts.tempDir, htmlContent)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	var buf []byte
+	// 增加超时时间，确保高质量渲染完成
+	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	absHtmlPath, err := filepath.Abs(htmlPath)
+	if err != nil {
+		return nil, err
 	}
-	if width <= 0 {
-		width = 1200
+	
+	// 改进的渲染流程，确保高质量输出
+	actions := []chromedp.Action{
+		chromedp.Navigate("file://" + absHtmlPath),
+		chromedp.Sleep(3 * time.Second), // 等待页面加载
+		chromedp.WaitVisible(`#diagram svg`, chromedp.ByQuery),
+		chromedp.Sleep(2 * time.Second), // 等待渲染完成
+		chromedp.Screenshot(`#diagram`, &buf, chromedp.NodeVisible, chromedp.ByID),
 	}
-	if height <= 0 {
-		height = 900
+	if svg != nil {
+		// XMLSerializer 输出带命名空间、可独立使用的 SVG 文档
+		actions = append(actions, chromedp.Evaluate(`new XMLSerializer().serializeToString(document.querySelector('#diagram svg'))`, svg))
 	}
-	if scale <= 0 {
-		scale = 2
+	err = chromedp.Run(timeoutCtx, actions...)
+
+	return buf, err
+}
+
+// writeMermaidPage 在 tempDir 下新建本次渲染独用的目录，写入 mermaid.min.js 与页面，
+// 返回页面路径与删除该目录的函数；并发渲染各用各的目录，互不覆盖
+func writeMermaidPage(tempDir, htmlContent string) (htmlPath string, cleanup func(), err error) {
+	if tempDir != "" {
+		if err := os.MkdirAll(tempDir, 0755); err != nil {
+			return "", nil, err
+		}
+	}
+	dir, err := os.MkdirTemp(tempDir, "md2word-mermaid-")
+	if err != nil {
+		return "", nil, err
 	}
+	cleanup = func() { os.RemoveAll(dir) }
 
-	homeDir, _ := os.UserHomeDir()
-	tmpDir := filepath.Join(homeDir, ".md2word-mermaid-tmp")
-	os.MkdirAll(tmpDir, 0755)
+	htmlPath = filepath.Join(dir, "render.html")
+	if err := os.WriteFile(filepath.Join(dir, "mermaid.min.js"), []byte(mermaidJS), 0644); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	if err := os.WriteFile(htmlPath, []byte(htmlContent), 0644); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return htmlPath, cleanup, nil
+}
 
-	jsPath := filepath.Join(tmpDir, "mermaid.min.js")
-	os.WriteFile(jsPath, []byte(mermaidJS), 0644)
+// mermaidHTML 生成渲染流程图的页面；htmlLabels 为 false 时节点标签输出为 SVG 文本
+func mermaidHTML(code string, opts mermaidOptions, htmlLabels bool) (string, error) {
+	theme, scale := opts.theme, opts.scale
+	if theme == "" {
+		theme = "default"
+	}
+	if scale <= 0 {
+		scale = 2
+	}
+	variables := opts.themeVariables
+	if variables == nil {
+		variables = map[string]string{}
+	}
+	themeVariables, err := json.Marshal(variables)
+	if err != nil {
+		return "", err
+	}
+	themeCSS, err := json.Marshal(opts.css)
+	if err != nil {
+		return "", err
+	}
 
-	htmlPath := filepath.Join(tmpDir, "render.html")
-	htmlContent := fmt.Sprintf(`<!DOCTYPE html>
+	return fmt.Sprintf(`<!DOCTYPE html>
 <html>
 <head>
     <meta charset="UTF-8">
@@ -81,10 +163,12 @@ func RenderMermaidWithContext(ctx context.Context, code string, theme string, wi
         mermaid.initialize({ 
             startOnLoad: true, 
             theme: '%s',
+            themeVariables: %s,
+            themeCSS: %s,
             securityLevel: 'loose',
             flowchart: {
                 useMaxWidth: false,
-                htmlLabels: true
+                htmlLabels: %t
             },
             sequence: {
                 useMaxWidth: false
@@ -101,31 +185,14 @@ func RenderMermaidWithContext(ctx context.Context, code string, theme string, wi
             if (svg) {
                 // 设置高质量渲染
                 svg.style.background = 'white';
-                svg.setAttribute('width', svg.getBBox().width * %d);
-                svg.setAttribute('height', svg.getBBox().height * %d);
+                // 指定了固定宽度时按该宽度等比缩放，否则保持自然尺寸
+                const box = svg.getBBox();
+                const width = %d || box.width;
+                svg.setAttribute('width', width * %d);
+                svg.setAttribute('height', box.height * width / box.width * %d);
             }
         }, 2000);
     </script>
 </body>
-</html>`, code, theme, scale, scale)
-
-	os.WriteFile(htmlPath, []byte(htmlContent), 0644)
-
-	var buf []byte
-	// 增加超时时间，确保高质量渲染完成
-	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
-	defer cancel()
-
-	absHtmlPath, _ := filepath.Abs(htmlPath)
-	
-	// 改进的渲染流程，确保高质量输出
-	err := chromedp.Run(timeoutCtx,
-		chromedp.Navigate("file://"+absHtmlPath),
-		chromedp.Sleep(3*time.Second), // 等待页面加载
-		chromedp.WaitVisible(`#diagram svg`, chromedp.ByQuery),
-		chromedp.Sleep(2*time.Second), // 等待渲染完成
-		chromedp.Screenshot(`#diagram`, &buf, chromedp.NodeVisible, chromedp.ByID),
-	)
-
-	return buf, err
+</html>`, code, theme, themeVariables, themeCSS, htmlLabels, max(opts.fixedWidth, 0), scale, scale), nil
 }
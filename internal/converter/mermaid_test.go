@@ -0,0 +1,176 @@
+package converter
+
+import (
+	"encoding/base64"
+	"image/color"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/yuin/goldmark/ast"
+
+	"md2word/internal/config"
+	"md2word/internal/parser"
+)
+
+func TestMermaidHTMLTheme(t *testing.T) {
+	html, err := mermaidHTML("graph TD; A-->B", mermaidOptions{
+		theme:          "base",
+		themeVariables: map[string]string{"primaryColor": "#E8F0FE"},
+		css:            ".node rect { rx: 6px; } </script>",
+	}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`theme: 'base'`,
+		`themeVariables: {"primaryColor":"#E8F0FE"},`,
+		`themeCSS: ".node rect { rx: 6px; } \u003c/script\u003e",`,
+		`htmlLabels: true`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("page missing %q", want)
+		}
+	}
+
+	html, err = mermaidHTML("graph TD; A-->B", mermaidOptions{}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`theme: 'default'`, `themeVariables: {},`, `htmlLabels: false`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("default page missing %q", want)
+		}
+	}
+}
+
+func TestMermaidJobWidth(t *testing.T) {
+	src := []byte("```mermaid width=640\ngraph TD; A-->B\n```\n\n```mermaid\ngraph TD; C-->D\n```\n")
+	cfg := config.DefaultConfig()
+	cfg.Mermaid.FixedWidth = 500
+	c := NewConverter(cfg)
+	c.source = src
+
+	var widths []int
+	for n := parser.NewMarkdownParser().Parse(src).FirstChild(); n != nil; n = n.NextSibling() {
+		if block, ok := n.(*ast.FencedCodeBlock); ok {
+			widths = append(widths, c.mermaidJob(block).width)
+		}
+	}
+	if len(widths) != 2 || widths[0] != 640 || widths[1] != 500 {
+		t.Errorf("widths = %v, want [640 500]", widths)
+	}
+
+	html, err := mermaidHTML("graph TD; A-->B", mermaidOptions{fixedWidth: 640, scale: 2}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(html, "const width = 640 || box.width;") {
+		t.Error("fixed width missing from page")
+	}
+}
+
+func TestMermaidOnlineFallback(t *testing.T) {
+	if _, err := FindChromePath(); err == nil {
+		t.Skip("browser available, fallback not used")
+	}
+	png := makePNG(t, 120, 60, func(x, y int) color.NRGBA { return color.NRGBA{200, 200, 255, 255} })
+	var gotCode, gotType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		code, _ := base64.URLEncoding.DecodeString(strings.TrimPrefix(r.URL.Path, "/img/"))
+		gotCode, gotType = string(code), r.URL.Query().Get("type")
+		w.Write(png)
+	}))
+	defer srv.Close()
+	old := mermaidInkURL
+	mermaidInkURL = srv.URL + "/img/"
+	defer func() { mermaidInkURL = old }()
+
+	md := "```mermaid\ngraph TD; A-->B\n```\n"
+	doc := convertMarkdown(t, md, func(cfg *config.Config) {
+		cfg.Mermaid.Enabled = true
+		cfg.Mermaid.OnlineFallback = true
+	})
+	if gotCode != "graph TD; A-->B\n" || gotType != "png" {
+		t.Errorf("request code=%q type=%q", gotCode, gotType)
+	}
+	if !strings.Contains(doc.document, "<w:drawing>") {
+		t.Error("diagram image missing")
+	}
+
+	// 不允许访问外部服务时不使用在线渲染
+	gotCode = ""
+	cfg := config.DefaultConfig()
+	cfg.Mermaid.OnlineFallback = true
+	cfg.Network.AllowExternal = false
+	if res := NewConverter(cfg).render(t.Context(), renderJob{kind: renderMermaid, source: "graph TD; A-->B"}); res.err == nil || gotCode != "" {
+		t.Errorf("online fallback used with network.allowExternal off: err=%v", res.err)
+	}
+}
+
+func TestChromeFlags(t *testing.T) {
+	off := false
+	flags := chromeFlags(config.ChromeConfig{
+		NoSandbox: &off,
+		Flags:     []string{"--disable-dev-shm-usage", "--lang=zh-CN", "--"},
+	})
+	if _, ok := flags["no-sandbox"]; ok {
+		t.Error("no-sandbox set although chrome.noSandbox is false")
+	}
+	if flags["disable-dev-shm-usage"] != true || flags["lang"] != "zh-CN" || len(flags) != 2 {
+		t.Errorf("flags = %v", flags)
+	}
+
+	on := true
+	if flags := chromeFlags(config.ChromeConfig{NoSandbox: &on}); flags["no-sandbox"] != true {
+		t.Errorf("flags = %v, want no-sandbox", flags)
+	}
+}
+
+func TestWriteMermaidPageConcurrent(t *testing.T) {
+	base := filepath.Join(t.TempDir(), "tmp")
+	const n = 16
+	paths := make([]string, n)
+	cleanups := make([]func(), n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			path, cleanup, err := writeMermaidPage(base, "page "+strconv.Itoa(i))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			paths[i], cleanups[i] = path, cleanup
+		}()
+	}
+	wg.Wait()
+	if t.Failed() {
+		return
+	}
+
+	for i, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "page " + strconv.Itoa(i); string(data) != want {
+			t.Errorf("page %d = %q, want %q", i, data, want)
+		}
+		if _, err := os.Stat(filepath.Join(filepath.Dir(path), "mermaid.min.js")); err != nil {
+			t.Errorf("page %d: mermaid.min.js missing: %v", i, err)
+		}
+	}
+	for _, cleanup := range cleanups {
+		cleanup()
+	}
+	if entries, err := os.ReadDir(base); err != nil || len(entries) != 0 {
+		t.Errorf("temp dirs left after cleanup: %v (err %v)", entries, err)
+	}
+}
@@ -0,0 +1,13 @@
+package converter
+
+import "md2word/internal/i18n"
+
+// msg 返回按配置语言翻译并格式化的消息
+func (c *Converter) msg(key string, args ...any) string {
+	return i18n.T(c.config.Lang, key, args...)
+}
+
+// errorf 返回按配置语言翻译的错误
+func (c *Converter) errorf(key string, args ...any) error {
+	return i18n.Errorf(c.config.Lang, key, args...)
+}
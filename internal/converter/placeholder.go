@@ -0,0 +1,54 @@
+package converter
+
+import (
+	"strings"
+
+	"md2word/internal/docx"
+	"md2word/internal/i18n"
+)
+
+// 渲染失败占位的内容类型，对应 errors.placeholderText 的键
+const (
+	placeholderMermaid = "mermaid"
+	placeholderMath    = "math"
+	placeholderImage   = "image"
+)
+
+// placeholderMessages errors.placeholderText 未配置时使用的占位标题消息
+var placeholderMessages = map[string]string{
+	placeholderMermaid: i18n.PlaceholderMermaid,
+	placeholderMath:    i18n.PlaceholderMath,
+	placeholderImage:   i18n.PlaceholderImage,
+}
+
+// placeholderText 返回 kind 类内容的占位标题
+func (c *Converter) placeholderText(kind string) string {
+	if text := c.config.Errors.PlaceholderText[kind]; text != "" {
+		return text
+	}
+	return c.msg(placeholderMessages[kind])
+}
+
+// placeholderColor 返回占位底色（不带 #）
+func (c *Converter) placeholderColor() string {
+	if color := c.config.Errors.PlaceholderColor; color != "" {
+		return strings.TrimPrefix(color, "#")
+	}
+	return "FFF3CD"
+}
+
+// addPlaceholderBlock 以带边框和底色的段落代替渲染失败的块级内容，标题之后附上原始内容
+func (c *Converter) addPlaceholderBlock(kind, source string) {
+	p := docx.NewParagraph("")
+	p.Shading = c.placeholderColor()
+	p.Border = true
+	p.AddRun(c.placeholderText(kind) + "\n").Bold = true
+	p.AddRun(source).FontName = "Consolas"
+	c.doc.AddParagraph(p)
+}
+
+// addPlaceholderRun 在段落内以带底色的文字代替渲染失败的行内内容
+func (c *Converter) addPlaceholderRun(p docx.RunContainer, kind, source string) {
+	run := p.AddRun(c.placeholderText(kind) + " " + source)
+	run.Shading = c.placeholderColor()
+}
@@ -0,0 +1,39 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+
+	"md2word/internal/config"
+)
+
+func TestFailurePlaceholders(t *testing.T) {
+	// 没有本地公式工具且不允许在线渲染，公式必然渲染失败
+	t.Setenv("PATH", t.TempDir())
+	md := "见图 ![图](missing.png)\n\n公式 $x^2$\n\n```math\nE=mc^2\n```\n"
+	doc := convertMarkdown(t, md, func(cfg *config.Config) {
+		cfg.Network.AllowExternal = false
+		cfg.Errors.PlaceholderText = map[string]string{"image": "[Image unavailable]", "math": "[Math failed]"}
+		cfg.Errors.PlaceholderColor = "#FFE0E0"
+	})
+
+	texts := strings.Join(doc.texts(t), "|")
+	for _, want := range []string{"[Image unavailable] missing.png", "[Math failed] $x^2$", "[Math failed]|E=mc^2"} {
+		if !strings.Contains(texts, want) {
+			t.Errorf("texts %q missing %q", texts, want)
+		}
+	}
+	if n := strings.Count(doc.document, `w:fill="FFE0E0"`); n != 3 {
+		t.Errorf("placeholder shading count = %d, want 3", n)
+	}
+}
+
+func TestPlaceholderLanguage(t *testing.T) {
+	md := "![图](missing.png)\n"
+	for lang, want := range map[string]string{"": "[图片加载失败] missing.png", "zh": "[图片加载失败] missing.png", "en": "[Image failed to load] missing.png"} {
+		doc := convertMarkdown(t, md, func(cfg *config.Config) { cfg.Lang = lang })
+		if texts := strings.Join(doc.texts(t), "|"); !strings.Contains(texts, want) {
+			t.Errorf("lang %q: texts %q missing %q", lang, texts, want)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+
+	"md2word/internal/config"
+)
+
+// errBrowserStart 浏览器无法启动，属于整体错误而非单个流程图的渲染失败
+var errBrowserStart = errors.New("启动浏览器失败")
+
+// renderKind 渲染任务类型
+type renderKind int
+
+const (
+	renderMermaid renderKind = iota
+	renderMath
+)
+
+// renderJob 一个生成图片的渲染任务
+type renderJob struct {
+	kind    renderKind
+	source  string // Mermaid 代码或 LaTeX 公式
+	display bool   // 公式是否为块级
+	width   int    // 流程图的固定宽度（像素），0 表示按自然尺寸
+}
+
+// key 相同内容的任务共用一次渲染结果
+func (j renderJob) key() string {
+	return fmt.Sprintf("%d|%t|%d|%s", j.kind, j.display, j.width, j.source)
+}
+
+// renderResult 渲染结果，错误在组装阶段按块处理
+type renderResult struct {
+	data []byte
+	svg  []byte // mermaid.format 为 svg 时的矢量图，data 为其 PNG 后备图
+	err  error
+}
+
+// prerender 第一遍遍历收集所有渲染任务并发执行，结果留给第二遍组装文档时取用
+func (c *Converter) prerender(ctx context.Context, root ast.Node) {
+	var jobs []renderJob
+	c.collectRenderJobs(root, &jobs)
+	c.renders = c.renderAll(ctx, jobs)
+}
+
+// collectRenderJobs 按 processNode 的分发规则收集渲染任务，
+// 只进入会被 processNode 递归处理的容器，避免渲染最终不会用到的内容
+func (c *Converter) collectRenderJobs(n ast.Node, jobs *[]renderJob) {
+	for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+		switch node := child.(type) {
+		case *ast.Paragraph:
+			c.collectInlineFormulas(node, jobs)
+		case *ast.List:
+			c.collectListFormulas(node, jobs)
+		case *east.Table:
+			for row := node.FirstChild(); row != nil; row = row.NextSibling() {
+				for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+					c.collectInlineFormulas(cell, jobs)
+				}
+			}
+		case *ast.FencedCodeBlock:
+			lang := strings.ToLower(string(node.Language(c.source)))
+			switch {
+			case lang == "mermaid" && c.config.Mermaid.Enabled:
+				*jobs = append(*jobs, c.mermaidJob(node))
+			case lang == "math" || lang == "latex":
+				*jobs = append(*jobs, renderJob{kind: renderMath, source: c.blockText(node), display: true})
+			}
+		case *ast.Heading, *ast.TextBlock, *ast.CodeBlock, *ast.Blockquote,
+			*ast.ThematicBreak, *ast.HTMLBlock:
+			// 这些节点不会产生公式或流程图图片
+		default:
+			c.collectRenderJobs(child, jobs)
+		}
+	}
+}
+
+// collectInlineFormulas 收集段落、列表项内容或表格单元格中的行内公式
+func (c *Converter) collectInlineFormulas(n ast.Node, jobs *[]renderJob) {
+	text := c.extractParagraphText(n)
+	if !strings.Contains(text, "$") {
+		return
+	}
+	for _, f := range c.parseInlineFormulas(text) {
+		*jobs = append(*jobs, renderJob{kind: renderMath, source: f.Formula})
+	}
+}
+
+// collectListFormulas 收集列表项中的行内公式：列表项的子块只按内联内容处理，嵌套列表递归收集
+func (c *Converter) collectListFormulas(list *ast.List, jobs *[]renderJob) {
+	for item := list.FirstChild(); item != nil; item = item.NextSibling() {
+		for block := item.FirstChild(); block != nil; block = block.NextSibling() {
+			if nested, ok := block.(*ast.List); ok {
+				c.collectListFormulas(nested, jobs)
+				continue
+			}
+			c.collectInlineFormulas(block, jobs)
+		}
+	}
+}
+
+// blockText 拼接代码块的全部行
+func (c *Converter) blockText(node ast.Node) string {
+	var lines []string
+	for i := 0; i < node.Lines().Len(); i++ {
+		line := node.Lines().At(i)
+		lines = append(lines, string(line.Value(c.source)))
+	}
+	return strings.Join(lines, "")
+}
+
+// renderAll 用有界工作池并发渲染公式；流程图共用一个浏览器实例，在单独的协程中顺序渲染。
+// ctx 取消后尚未开始的任务不再执行，正在运行的任务（外部命令、网络请求、浏览器操作）随之中止。
+func (c *Converter) renderAll(ctx context.Context, jobs []renderJob) map[string]*renderResult {
+	results := make(map[string]*renderResult)
+	var mermaidJobs, mathJobs []renderJob
+	for _, job := range jobs {
+		if _, ok := results[job.key()]; ok {
+			continue
+		}
+		results[job.key()] = nil
+		if job.kind == renderMermaid {
+			mermaidJobs = append(mermaidJobs, job)
+		} else {
+			mathJobs = append(mathJobs, job)
+		}
+	}
+
+	workers := c.config.Render.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	var mu sync.Mutex
+	durations := make(map[string]time.Duration)
+	run := func(job renderJob) {
+		if ctx.Err() != nil {
+			return
+		}
+		start := time.Now()
+		res := c.render(ctx, job)
+		mu.Lock()
+		results[job.key()] = res
+		durations[renderKindNames[job.kind]] += time.Since(start)
+		mu.Unlock()
+	}
+
+	var wg sync.WaitGroup
+	if len(mermaidJobs) > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, job := range mermaidJobs {
+				run(job)
+			}
+		}()
+	}
+
+	queue := make(chan renderJob)
+	for i := 0; i < workers && i < len(mathJobs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				run(job)
+			}
+		}()
+	}
+enqueue:
+	for _, job := range mathJobs {
+		select {
+		case queue <- job:
+		case <-ctx.Done():
+			break enqueue
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if c.durations != nil {
+		for name, d := range durations {
+			c.durations[name] += d
+		}
+	}
+
+	for key, res := range results {
+		if res == nil {
+			delete(results, key)
+		}
+	}
+	return results
+}
+
+// render 执行单个渲染任务
+func (c *Converter) render(ctx context.Context, job renderJob) *renderResult {
+	switch job.kind {
+	case renderMermaid:
+		chromeCtx, err := c.ensureChrome()
+		if err != nil {
+			if c.config.Mermaid.OnlineFallback && c.config.Network.AllowExternal {
+				data, err := RenderMermaidInk(ctx, job.source, c.config.Mermaid.Theme)
+				return &renderResult{data: data, err: err}
+			}
+			return &renderResult{err: fmt.Errorf("%w: %v", errBrowserStart, err)}
+		}
+		// 浏览器在整次转换中复用，本次渲染的浏览器操作随 ctx 一起取消
+		tabCtx, cancel := context.WithCancel(chromeCtx)
+		defer cancel()
+		stop := context.AfterFunc(ctx, cancel)
+		defer stop()
+
+		m := c.config.Mermaid
+		opts := mermaidOptions{
+			theme:          m.Theme,
+			themeVariables: m.ThemeVariables,
+			css:            m.CSS,
+			fixedWidth:     job.width,
+			tempDir:        c.config.TempDir,
+			width:          m.Width,
+			height:         m.Height,
+			scale:          m.Scale,
+		}
+		res := &renderResult{}
+		if m.Format == config.MermaidFormatSVG {
+			var svg string
+			res.data, res.err = renderMermaidPage(tabCtx, job.source, opts, &svg)
+			res.svg = []byte(svg)
+		} else {
+			res.data, res.err = renderMermaidPage(tabCtx, job.source, opts, nil)
+		}
+		if ctx.Err() != nil {
+			return &renderResult{err: ctx.Err()}
+		}
+		return res
+	default:
+		render := RenderMathJax
+		if !c.config.Network.AllowExternal {
+			render = renderMathJaxLocal
+		}
+		data, err := render(ctx, job.source, job.display)
+		if err == nil && c.config.Math.Transparent {
+			data = whiteToAlpha(data)
+		}
+		return &renderResult{data: data, err: err}
+	}
+}
+
+// renderedImage 取预渲染的图片；未预渲染的任务（如重新解析的缩进代码块中的内容）即时渲染
+func (c *Converter) renderedImage(job renderJob) ([]byte, error) {
+	res := c.rendered(job)
+	return res.data, res.err
+}
+
+// rendered 取预渲染结果，未预渲染时即时渲染
+func (c *Converter) rendered(job renderJob) *renderResult {
+	if res, ok := c.renders[job.key()]; ok {
+		return res
+	}
+	return c.render(c.ctx, job)
+}
+
+// mermaidJob 流程图代码块的渲染任务：宽度取代码块信息串中的 width=（如 ```mermaid width=800），
+// 未指定时使用 mermaid.fixedWidth
+func (c *Converter) mermaidJob(node *ast.FencedCodeBlock) renderJob {
+	job := renderJob{kind: renderMermaid, source: c.blockText(node), width: c.config.Mermaid.FixedWidth}
+	if node.Info != nil {
+		_, attrs, _ := strings.Cut(strings.TrimSpace(string(node.Info.Segment.Value(c.source))), " ")
+		if w, err := strconv.Atoi(parseDirectiveArgs(attrs)["width"]); err == nil && w > 0 {
+			job.width = w
+		}
+	}
+	return job
+}
@@ -0,0 +1,47 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"md2word/internal/config"
+)
+
+func TestConvertCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cfg := config.DefaultConfig()
+	cfg.Mermaid.Enabled = false
+	out := filepath.Join(t.TempDir(), "out.docx")
+
+	start := time.Now()
+	err := NewConverter(cfg).Convert(ctx, []byte("formula $x^2$ and $y$\n\n```math\nE=mc^2\n```\n"), out)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("canceled conversion took %v", elapsed)
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Errorf("output file should not exist after cancellation")
+	}
+}
+
+func TestRenderAllSkipsJobsAfterCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := NewConverter(config.DefaultConfig())
+	results := c.renderAll(ctx, []renderJob{
+		{kind: renderMath, source: "a"},
+		{kind: renderMath, source: "b"},
+	})
+	if len(results) != 0 {
+		t.Errorf("got %d results, want none after cancellation", len(results))
+	}
+}
@@ -0,0 +1,82 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// ConvertResult 一次转换的结果信息，供把 md2word 作为服务使用时汇报转换情况
+type ConvertResult struct {
+	OutputPath string
+	Size       int64  // 输出文件的字节数
+	Images     int    // 嵌入的图片文件数（含公式、流程图）
+	Title      string // 文档标题：第一个一级标题的文字，没有一级标题时取第一个标题
+	Warnings   []string
+
+	// 各阶段耗时：parse（解析）、render（预渲染总耗时）、build（组装文档）、save（写出文件），
+	// 以及 mermaid、math（各类渲染任务耗时之和，并发执行时可能大于 render）
+	Durations map[string]time.Duration
+}
+
+// 渲染任务类型在 ConvertResult.Durations 中的名称
+var renderKindNames = map[renderKind]string{
+	renderMermaid: "mermaid",
+	renderMath:    "math",
+}
+
+// ConvertWithResult 与 Convert 相同，另外返回输出大小、图片数、警告与各阶段耗时等结果信息
+func (c *Converter) ConvertWithResult(ctx context.Context, content []byte, outputPath string) (*ConvertResult, error) {
+	if err := c.convert(ctx, content, filepath.Dir(outputPath), outputPath); err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(outputPath)
+	if err != nil {
+		return nil, err
+	}
+	return &ConvertResult{
+		OutputPath: outputPath,
+		Size:       info.Size(),
+		Images:     c.doc.ImageCount(),
+		Title:      c.title,
+		Warnings:   append([]string(nil), c.warnings...),
+		Durations:  c.durations,
+	}, nil
+}
+
+// warn 记录一条不影响输出的问题（按配置语言翻译的消息 key），同时打印到控制台
+func (c *Converter) warn(key string, args ...any) {
+	msg := c.msg(key, args...)
+	fmt.Println(msg)
+	c.warnings = append(c.warnings, msg)
+}
+
+// timed 执行 fn 并把耗时累加到 durations[name]
+func (c *Converter) timed(name string, fn func()) {
+	start := time.Now()
+	fn()
+	c.durations[name] += time.Since(start)
+}
+
+// documentTitle 返回第一个一级标题的文字，没有一级标题时取第一个标题
+func (c *Converter) documentTitle(root ast.Node) string {
+	var first string
+	for n := root.FirstChild(); n != nil; n = n.NextSibling() {
+		h, ok := n.(*ast.Heading)
+		if !ok {
+			continue
+		}
+		text := c.extractParagraphText(h)
+		if h.Level == 1 {
+			return text
+		}
+		if first == "" {
+			first = text
+		}
+	}
+	return first
+}
@@ -0,0 +1,50 @@
+package converter
+
+import (
+	"context"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"md2word/internal/config"
+)
+
+func TestConvertWithResult(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "pic.png"), makePNG(t, 20, 10, func(x, y int) color.NRGBA { return color.NRGBA{0, 128, 0, 255} }), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	md := "## 前言\n\n# 用户手册\n\n![图](pic.png)\n\n![缺失](missing.png)\n"
+
+	cfg := config.DefaultConfig()
+	cfg.Mermaid.Enabled = false
+	out := filepath.Join(dir, "out.docx")
+	res, err := NewConverter(cfg).ConvertWithResult(context.Background(), []byte(md), out)
+	if err != nil {
+		t.Fatalf("ConvertWithResult: %v", err)
+	}
+
+	info, err := os.Stat(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.OutputPath != out || res.Size != info.Size() {
+		t.Errorf("output = %q (%d bytes), want %q (%d bytes)", res.OutputPath, res.Size, out, info.Size())
+	}
+	if res.Title != "用户手册" {
+		t.Errorf("title = %q, want 用户手册", res.Title)
+	}
+	if res.Images != 1 {
+		t.Errorf("images = %d, want 1", res.Images)
+	}
+	if len(res.Warnings) != 1 || !strings.Contains(res.Warnings[0], "missing.png") {
+		t.Errorf("warnings = %q, want one about missing.png", res.Warnings)
+	}
+	for _, name := range []string{"parse", "render", "build", "save"} {
+		if _, ok := res.Durations[name]; !ok {
+			t.Errorf("durations missing %q: %v", name, res.Durations)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/xml"
+	"mime"
+	"strconv"
+	"strings"
+
+	"md2word/internal/config"
+	"md2word/internal/docx"
+	"md2word/internal/i18n"
+)
+
+// isSVG 判断图片是否为 SVG：按内容类型，或 http.DetectContentType 识别为 XML/文本时按根元素判断
+func isSVG(data []byte, contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "image/svg+xml":
+		return true
+	case "text/xml", "text/plain", "application/xml":
+		head := data[:min(len(data), 1024)]
+		return bytes.Contains(head, []byte("<svg"))
+	}
+	return false
+}
+
+// svgDimensions 返回 SVG 的像素尺寸：优先 width/height 属性，其次 viewBox；都无法解析时与位图一样按 300x200
+func svgDimensions(data []byte) (int, int) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return 300, 200
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if start.Name.Local != "svg" {
+			return 300, 200
+		}
+		var width, height float64
+		var viewBox string
+		for _, a := range start.Attr {
+			switch a.Name.Local {
+			case "width":
+				width = svgLength(a.Value)
+			case "height":
+				height = svgLength(a.Value)
+			case "viewBox":
+				viewBox = a.Value
+			}
+		}
+		if width > 0 && height > 0 {
+			return int(width + 0.5), int(height + 0.5)
+		}
+		if f := strings.Fields(strings.ReplaceAll(viewBox, ",", " ")); len(f) == 4 {
+			w, errW := strconv.ParseFloat(f[2], 64)
+			h, errH := strconv.ParseFloat(f[3], 64)
+			if errW == nil && errH == nil && w > 0 && h > 0 {
+				return int(w + 0.5), int(h + 0.5)
+			}
+		}
+		return 300, 200
+	}
+}
+
+// svgLength 解析 SVG 的 width/height 属性为像素，百分比等无法换算的取 0
+func svgLength(s string) float64 {
+	pt, hasUnit, err := config.ParseLength(s)
+	if err != nil {
+		return 0
+	}
+	if hasUnit {
+		return pt / 0.75
+	}
+	return pt
+}
+
+// addSVGImage 按 images.svg 嵌入 SVG 图片：
+// embed 嵌入矢量图并尽量附带 PNG 后备图，rasterize 只嵌入 PNG，reject 以占位代替（返回 nil）
+func (c *Converter) addSVGImage(src string, data []byte, p docx.RunContainer) *docx.Run {
+	mode := c.config.Images.SVG
+	if mode == config.ImageSVGReject {
+		c.warn(i18n.SVGRejected, src)
+		c.addPlaceholderRun(p, placeholderImage, src)
+		return nil
+	}
+
+	width, height := svgDimensions(data)
+	displayW, displayH := c.calculateOptimalImageSize(width, height)
+	png, err := convertSVGtoPNG(c.ctx, data)
+	if err != nil {
+		if mode == config.ImageSVGRasterize {
+			c.warn(i18n.ImageLoadFailed, src, err)
+			c.addPlaceholderRun(p, placeholderImage, src)
+			return nil
+		}
+		// 没有转换工具：只嵌入 SVG，支持 SVG 的 Word 版本仍能显示
+		rID := c.doc.AddImage(data, "image/svg+xml", width, height)
+		return p.AddImageRun(rID, int64(displayW)*9525, int64(displayH)*9525)
+	}
+
+	pngW, pngH := c.getImageDimensions(png)
+	rID := c.doc.AddImage(png, "image/png", pngW, pngH)
+	run := p.AddImageRun(rID, int64(displayW)*9525, int64(displayH)*9525)
+	if mode != config.ImageSVGRasterize {
+		run.SVGRelID = c.doc.AddImage(data, "image/svg+xml", width, height)
+	}
+	return run
+}
@@ -0,0 +1,82 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"md2word/internal/config"
+)
+
+func TestSVGDimensions(t *testing.T) {
+	tests := []struct {
+		svg  string
+		w, h int
+	}{
+		{`<svg xmlns="http://www.w3.org/2000/svg" width="120" height="80"/>`, 120, 80},
+		{`<?xml version="1.0"?><svg width="30pt" height="15pt"/>`, 40, 20},
+		{`<svg width="100%" viewBox="0 0 64 32"/>`, 64, 32},
+		{`<html/>`, 300, 200},
+	}
+	for _, tt := range tests {
+		if w, h := svgDimensions([]byte(tt.svg)); w != tt.w || h != tt.h {
+			t.Errorf("svgDimensions(%s) = %dx%d, want %dx%d", tt.svg, w, h, tt.w, tt.h)
+		}
+	}
+}
+
+func TestSVGImageModes(t *testing.T) {
+	svg := filepath.Join(t.TempDir(), "logo.svg")
+	if err := os.WriteFile(svg, []byte(`<?xml version="1.0"?>`+"\n"+`<svg xmlns="http://www.w3.org/2000/svg" width="40" height="20"/>`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	md := "![logo](" + svg + ")\n"
+	fakeMathTools(t)
+
+	tests := []struct {
+		mode        string
+		media       []string
+		svgBlip     bool
+		placeholder bool
+	}{
+		{config.ImageSVGEmbed, []string{"image1.png", "image2.svg"}, true, false},
+		{config.ImageSVGRasterize, []string{"image1.png"}, false, false},
+		{config.ImageSVGReject, nil, false, true},
+	}
+	for _, tt := range tests {
+		doc := convertMarkdown(t, md, func(cfg *config.Config) { cfg.Images.SVG = tt.mode })
+		var media []string
+		for name := range doc.parts {
+			if strings.HasPrefix(name, "word/media/") {
+				media = append(media, strings.TrimPrefix(name, "word/media/"))
+			}
+		}
+		sort.Strings(media)
+		if strings.Join(media, ",") != strings.Join(tt.media, ",") {
+			t.Errorf("%s: media = %v, want %v", tt.mode, media, tt.media)
+		}
+		if got := strings.Contains(doc.document, "asvg:svgBlip"); got != tt.svgBlip {
+			t.Errorf("%s: svgBlip = %v, want %v", tt.mode, got, tt.svgBlip)
+		}
+		if got := strings.Contains(strings.Join(doc.texts(t), "|"), "[图片加载失败]"); got != tt.placeholder {
+			t.Errorf("%s: placeholder = %v, want %v", tt.mode, got, tt.placeholder)
+		}
+	}
+}
+
+func TestSVGWithoutConverter(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	svg := filepath.Join(t.TempDir(), "logo.svg")
+	if err := os.WriteFile(svg, []byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 10 10"/>`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	doc := convertMarkdown(t, "![logo]("+svg+")\n", nil)
+	if _, ok := doc.parts["word/media/image1.svg"]; !ok {
+		t.Error("svg media part missing")
+	}
+	if !strings.Contains(doc.parts["[Content_Types].xml"], `Extension="svg" ContentType="image/svg+xml"`) {
+		t.Error("svg content type not declared")
+	}
+}
@@ -0,0 +1,207 @@
+package converter
+
+import (
+	"strings"
+	"unicode"
+
+	"md2word/internal/config"
+	"md2word/internal/docx"
+)
+
+const (
+	// cellPaddingTwips 单元格左右默认内边距之和 (2 × 108 twips)
+	cellPaddingTwips = 216
+	// minCellPaddingTwips shrinkFont 策略允许缩小到的最小左右内边距之和
+	minCellPaddingTwips = 40
+	// minShrinkFontSize shrinkFont 策略允许缩小到的最小字号 (pt)
+	minShrinkFontSize = 5
+)
+
+// columnWidths 估算表格每列的最小宽度（最长不可断开单词）与理想宽度（整段不换行），单位 twips。
+// padding 为单元格左右内边距之和。
+func columnWidths(table *docx.Table, fontSize float64, padding int) (minWidths, maxWidths []int) {
+	for _, row := range table.Rows {
+		for i, cell := range row.Cells {
+			for len(minWidths) <= i {
+				minWidths = append(minWidths, 0)
+				maxWidths = append(maxWidths, 0)
+			}
+			for _, p := range cell.Paragraphs {
+				minW, maxW := paragraphWidths(p, fontSize)
+				minWidths[i] = max(minWidths[i], minW+padding)
+				maxWidths[i] = max(maxWidths[i], maxW+padding)
+			}
+		}
+	}
+	return minWidths, maxWidths
+}
+
+// paragraphWidths 估算段落的最小宽度与理想宽度 (twips)
+func paragraphWidths(p *docx.Paragraph, fontSize float64) (minW, maxW int) {
+	for _, run := range p.Runs() {
+		if run.IsImage {
+			w := int(run.ImageWidth / 635) // 1 twip = 635 EMU
+			minW = max(minW, w)
+			maxW += w
+			continue
+		}
+		size := fontSize
+		if run.FontSize > 0 {
+			size = run.FontSize
+		}
+		for _, word := range strings.Fields(run.Text) {
+			minW = max(minW, textWidthTwips(word, size, true))
+		}
+		maxW += textWidthTwips(run.Text, size, false)
+	}
+	return minW, maxW
+}
+
+// textWidthTwips 粗略估算文本宽度：全角字符按 1 个字号宽，半角按 0.55 个字号宽。
+// unbroken=true 时返回最长不可断开片段的宽度（全角字符之间可以断行）。
+func textWidthTwips(text string, fontSize float64, unbroken bool) int {
+	full := fontSize * 20
+	var width, segment float64
+	for _, r := range text {
+		switch {
+		case isWideRune(r) && unbroken:
+			width = max(width, segment, full)
+			segment = 0
+		case isWideRune(r):
+			segment += full
+		default:
+			segment += full * 0.55
+		}
+	}
+	return int(max(width, segment))
+}
+
+// isWideRune 判断是否为全角（中日韩等）字符
+func isWideRune(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) ||
+		(r >= 0x3000 && r <= 0x303F) || (r >= 0xFF00 && r <= 0xFFEF)
+}
+
+// sum 求和
+func sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+// scaleColumns 按理想宽度的比例分配可用宽度，并固定表格布局
+func scaleColumns(table *docx.Table, maxWidths []int, available int) {
+	widths := make([]int, len(maxWidths))
+	capped := make([]int, len(maxWidths))
+	for i, w := range maxWidths {
+		capped[i] = min(w, available)
+	}
+	total := sum(capped)
+	for i, w := range capped {
+		widths[i] = w * available / total
+	}
+
+	table.ColWidths = widths
+	table.FixedLayout = true
+	for _, row := range table.Rows {
+		for i, cell := range row.Cells {
+			if i < len(widths) {
+				cell.Width = widths[i]
+			}
+		}
+	}
+}
+
+// shrinkTableFont 按比例缩小表格内所有文字的字号与单元格内边距，返回缩小后的默认字号与内边距；
+// codeSize 为行内代码样式的字号
+func shrinkTableFont(table *docx.Table, baseSize, codeSize, factor float64) (float64, int) {
+	size := max(baseSize*factor, minShrinkFontSize)
+	padding := max(int(cellPaddingTwips*factor), minCellPaddingTwips)
+	table.CellMargin = padding / 2
+	for _, row := range table.Rows {
+		for _, cell := range row.Cells {
+			for _, p := range cell.Paragraphs {
+				for _, run := range p.Runs() {
+					if run.IsImage {
+						continue
+					}
+					switch {
+					case run.FontSize > 0:
+						run.FontSize = max(run.FontSize*factor, minShrinkFontSize)
+					case run.IsCode:
+						run.FontSize = max(codeSize*factor, minShrinkFontSize)
+					default:
+						run.FontSize = size
+					}
+				}
+			}
+		}
+	}
+	return size, padding
+}
+
+// setTableWidth 按 table.width 设置表格宽度
+func (c *Converter) setTableWidth(table *docx.Table) {
+	width := c.config.Table.Width
+	switch {
+	case width.Percent > 0:
+		table.Width = int(width.Percent*50 + 0.5)
+		table.WidthPct = true
+	case width.Twips > 0:
+		table.Width = int(width.Twips)
+	}
+}
+
+// addTable 将表格添加到文档，按 table.overflow 处理超出页面宽度的表格：
+//   - scale:      按比例压缩列宽到页面内容宽度
+//   - shrinkFont: 按比例缩小单元格字号与内边距（不小于下限），仍放不下时改用横向页面
+//   - rotate:     把表格放入单独的横向节（仍放不下时再压缩列宽）
+//
+// 以各列最长不可断开单词之和判断是否超宽。
+func (c *Converter) addTable(table *docx.Table) {
+	fontSize := float64(c.config.Table.Size)
+	if fontSize <= 0 {
+		fontSize = float64(c.config.Styles.Body.Size)
+	}
+	fontSize = c.config.ScaleFont(fontSize)
+	available := c.contentWidthTwips()
+	minWidths, maxWidths := columnWidths(table, fontSize, cellPaddingTwips)
+	required := sum(minWidths)
+
+	if required <= available || len(minWidths) == 0 {
+		c.doc.AddParagraph(docx.NewTableElement(table))
+		return
+	}
+
+	switch c.config.Table.Overflow {
+	case config.TableOverflowScale:
+		scaleColumns(table, maxWidths, available)
+	case config.TableOverflowShrinkFont:
+		_, codeSize := c.config.InlineCodeFont()
+		size, padding := shrinkTableFont(table, fontSize, codeSize, float64(available)/float64(required))
+		// 字号与内边距都有下限，夹紧后需重新测量
+		minWidths, maxWidths = columnWidths(table, size, padding)
+		if required = sum(minWidths); required > available {
+			c.addLandscapeTable(table, required, maxWidths)
+			return
+		}
+	case config.TableOverflowRotate:
+		c.addLandscapeTable(table, required, maxWidths)
+		return
+	}
+	c.doc.AddParagraph(docx.NewTableElement(table))
+}
+
+// addLandscapeTable 把表格放入单独的横向节，横向仍放不下时压缩列宽
+func (c *Converter) addLandscapeTable(table *docx.Table, required int, maxWidths []int) {
+	landscape := docx.LandscapeContentWidthTwips() - int(c.config.Page.Margins.Gutter)
+	if required > landscape {
+		scaleColumns(table, maxWidths, landscape)
+	}
+	cur := c.doc.CurrentSection()
+	c.doc.AddSectionBreak(&docx.Section{PageNumberFormat: cur.PageNumberFormat, Landscape: true})
+	c.doc.AddParagraph(docx.NewTableElement(table))
+	c.doc.AddSectionBreak(&docx.Section{PageNumberFormat: cur.PageNumberFormat})
+}
@@ -0,0 +1,110 @@
+package converter
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"md2word/internal/config"
+)
+
+// wideTable 生成 cols 列、每格为长度 wordLen 的不可断开单词的表格
+func wideTable(cols, wordLen int) string {
+	word := strings.Repeat("x", wordLen)
+	header := "|" + strings.Repeat(" h |", cols)
+	sep := "|" + strings.Repeat("---|", cols)
+	row := "|" + strings.Repeat(" "+word+" |", cols)
+	return header + "\n" + sep + "\n" + row + "\n"
+}
+
+func TestShrinkFontFitsTable(t *testing.T) {
+	doc := convertMarkdown(t, wideTable(6, 20), func(cfg *config.Config) {
+		cfg.Table.Overflow = config.TableOverflowShrinkFont
+	})
+	if strings.Contains(doc.document, `w:orient="landscape"`) {
+		t.Error("table that fits after shrinking should stay in portrait")
+	}
+	if !strings.Contains(doc.document, "<w:tblCellMar>") {
+		t.Error("shrunk table should scale cell margins")
+	}
+}
+
+func TestShrinkFontFallsBackToLandscape(t *testing.T) {
+	doc := convertMarkdown(t, wideTable(10, 40), func(cfg *config.Config) {
+		cfg.Table.Overflow = config.TableOverflowShrinkFont
+	})
+	if !strings.Contains(doc.document, `w:orient="landscape"`) {
+		t.Error("table still too wide at the minimum font size should move to a landscape section")
+	}
+}
+
+func TestInvalidTableOverflow(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Table.Overflow = "squeeze"
+	if err := NewConverter(cfg).Convert(context.Background(), []byte("text"), t.TempDir()+"/out.docx"); err == nil {
+		t.Fatal("expected error for unknown table.overflow")
+	}
+}
+
+func TestTableWidth(t *testing.T) {
+	const md = "| a | b |\n|---|---|\n| 1 | 2 |\n"
+	tests := []struct {
+		name  string
+		width config.TableWidth
+		want  string
+	}{
+		{"auto", config.TableWidth{}, `<w:tblW w:w="0" w:type="auto"/>`},
+		{"percent", config.TableWidth{Percent: 100}, `<w:tblW w:w="5000" w:type="pct"/>`},
+		{"absolute", config.TableWidth{Twips: 6000}, `<w:tblW w:w="6000" w:type="dxa"/>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := convertMarkdown(t, md, func(cfg *config.Config) { cfg.Table.Width = tt.width })
+			if !strings.Contains(doc.document, tt.want) {
+				t.Errorf("document missing %s", tt.want)
+			}
+		})
+	}
+}
+
+func TestTablePipesInCells(t *testing.T) {
+	md := "| 语法 | 说明 |\n|---|---|\n| `a\\|b` | 按位或 |\n| x \\| y | 转义的竖线 |\n\n正文 \\*不是强调\\* 与 `c\\|d`\n"
+	doc := convertMarkdown(t, md, nil)
+	if n := strings.Count(doc.document, "<w:tc>"); n != 6 {
+		t.Errorf("cells = %d, want 6", n)
+	}
+	texts := strings.Join(doc.texts(t), "")
+	for _, want := range []string{"a|b按位或", "x | y转义的竖线", "正文 *不是强调* 与 c\\|d"} {
+		if !strings.Contains(texts, want) {
+			t.Errorf("texts %q missing %q", texts, want)
+		}
+	}
+}
+
+func TestTableColumnAlign(t *testing.T) {
+	doc := convertMarkdown(t, "| 名称 | 状态 | 金额 |\n|---|:---:|---:|\n| 甲 | 完成 | 12 |\n", nil)
+	for jc, want := range map[string]int{"start": 2, "center": 2, "end": 2} {
+		if n := strings.Count(doc.document, `<w:jc w:val="`+jc+`"/>`); n != want {
+			t.Errorf(`<w:jc w:val="%s"/> count = %d, want %d`, jc, n, want)
+		}
+	}
+}
+
+func TestTableHeaderRow(t *testing.T) {
+	doc := convertMarkdown(t, "| 名称 | 金额 |\n|---|---|\n| 甲 | 12 |\n", func(cfg *config.Config) {
+		cfg.Table.HeaderBackground = "#F2F2F2"
+	})
+	rows := strings.Split(doc.document, "<w:tr>")[1:]
+	if len(rows) != 2 {
+		t.Fatalf("rows = %d, want 2", len(rows))
+	}
+	if !strings.Contains(rows[0], "<w:tblHeader/>") || strings.Count(rows[0], "<w:b/>") != 2 {
+		t.Errorf("header row should repeat and be bold:\n%s", rows[0])
+	}
+	if strings.Count(rows[0], `w:fill="F2F2F2"`) != 2 {
+		t.Error("header cells not shaded")
+	}
+	if strings.Contains(rows[1], "<w:tblHeader/>") || strings.Contains(rows[1], "<w:b/>") || strings.Contains(rows[1], "<w:shd ") {
+		t.Error("body row styled as header")
+	}
+}
@@ -0,0 +1,111 @@
+package converter
+
+import (
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+
+	"md2word/internal/docx"
+)
+
+// 任务列表复选框字符
+const (
+	checkboxUnchecked = "☐" // U+2610
+	checkboxChecked   = "☒" // U+2612
+)
+
+// taskListBoxWidth 表格排版时复选框列的宽度 (twips)
+const taskListBoxWidth = 440
+
+// taskCheckBox 返回列表项开头的任务复选框，不是任务项时为 nil
+func taskCheckBox(item *ast.ListItem) *east.TaskCheckBox {
+	first := item.FirstChild()
+	if first == nil {
+		return nil
+	}
+	box, _ := first.FirstChild().(*east.TaskCheckBox)
+	return box
+}
+
+// checkboxGlyph 返回复选框对应的字符
+func checkboxGlyph(box *east.TaskCheckBox) string {
+	if box.IsChecked {
+		return checkboxChecked
+	}
+	return checkboxUnchecked
+}
+
+// addTaskCheckBox 在段落开头加复选框：段落按列表层级缩进，复选框悬挂在文字左侧，字号与正文一致
+func (c *Converter) addTaskCheckBox(p *docx.Paragraph, box *east.TaskCheckBox, level int) {
+	p.Indent = docx.ListIndentStep * (min(level, 8) + 1)
+	p.FirstLineIndent = -docx.ListIndentStep
+	run := p.AddRun(checkboxGlyph(box) + "\t")
+	run.FontSize = c.config.ScaleFont(float64(c.config.Styles.Body.Size))
+}
+
+// strikeTaskText 给已完成任务项的文字加删除线 (styles.taskList.strikeCompleted)
+func strikeTaskText(runs []*docx.Run) {
+	for _, run := range runs {
+		run.Strike = true
+	}
+}
+
+// isTaskList 判断列表的每一项都是任务项
+func isTaskList(list *ast.List) bool {
+	items := 0
+	for child := list.FirstChild(); child != nil; child = child.NextSibling() {
+		item, ok := child.(*ast.ListItem)
+		if !ok || taskCheckBox(item) == nil {
+			return false
+		}
+		items++
+	}
+	return items > 0
+}
+
+// processTaskListTable 把任务列表排成两列无边框表格（styles.taskList.layout: table）：
+// 左列为复选框，右列为项目文字；嵌套列表的项目作为后续行，文字按层级缩进
+func (c *Converter) processTaskListTable(list *ast.List) {
+	table := docx.NewTable()
+	table.HasBorders = false
+	table.FixedLayout = true
+	table.ColWidths = []int{taskListBoxWidth, c.contentWidthTwips() - taskListBoxWidth}
+	c.addTaskListRows(table, list, 0)
+	c.doc.AddParagraph(docx.NewTableElement(table))
+}
+
+// addTaskListRows 把 list 的每一项加为表格的一行，level 为嵌套层级
+func (c *Converter) addTaskListRows(table *docx.Table, list *ast.List, level int) {
+	for child := list.FirstChild(); child != nil; child = child.NextSibling() {
+		item, ok := child.(*ast.ListItem)
+		if !ok {
+			continue
+		}
+		row := table.AddRow(false)
+		boxCell, textCell := row.AddCell(), row.AddCell()
+		boxCell.Width, textCell.Width = table.ColWidths[0], table.ColWidths[1]
+		box := taskCheckBox(item)
+		if box != nil {
+			boxCell.SetText(checkboxGlyph(box), false)
+		}
+
+		p := docx.NewParagraph("")
+		p.Indent = level * 360
+		var nestedLists []*ast.List
+		for n := item.FirstChild(); n != nil; n = n.NextSibling() {
+			if nested, ok := n.(*ast.List); ok {
+				nestedLists = append(nestedLists, nested)
+				continue
+			}
+			c.processInlineContent(n, p)
+		}
+		if box != nil && box.IsChecked && c.config.Styles.TaskList.StrikeCompleted {
+			strikeTaskText(p.Runs())
+		}
+		c.attachPendingComments(p)
+		textCell.AddParagraph(p)
+
+		for _, nested := range nestedLists {
+			c.addTaskListRows(table, nested, level+1)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+
+	"md2word/internal/config"
+)
+
+func TestTaskListTable(t *testing.T) {
+	md := "- [x] 准备材料\n- [ ] 提交审批\n  - [ ] 部门签字\n\n* 普通列表\n"
+	doc := convertMarkdown(t, md, func(cfg *config.Config) {
+		cfg.Styles.TaskList.Layout = config.TaskListTable
+	})
+	if n := strings.Count(doc.document, "<w:tbl>"); n != 1 {
+		t.Fatalf("tables = %d, want 1", n)
+	}
+	if !strings.Contains(doc.document, `<w:insideV w:val="nil"/>`) {
+		t.Error("task list table should be borderless")
+	}
+	if n := strings.Count(doc.document, "<w:tr>"); n != 3 {
+		t.Errorf("rows = %d, want 3 (nested item as its own row)", n)
+	}
+	texts := strings.Join(doc.texts(t), "|")
+	for _, want := range []string{"☒|准备材料", "☐|提交审批", "☐|部门签字", "普通列表"} {
+		if !strings.Contains(texts, want) {
+			t.Errorf("texts %q missing %q", texts, want)
+		}
+	}
+	if !strings.Contains(doc.document, `<w:ind w:left="360"/>`) {
+		t.Error("nested item text should be indented")
+	}
+}
+
+func TestTaskListInline(t *testing.T) {
+	doc := convertMarkdown(t, "- [x] 完成\n- [ ] 待办\n", nil)
+	if strings.Contains(doc.document, "<w:tbl>") {
+		t.Error("inline layout should not build a table")
+	}
+}
+
+func TestTaskListCheckboxGlyphs(t *testing.T) {
+	md := "- [x] 完成\n- [ ] 待办\n  - [ ] 子任务\n"
+	doc := convertMarkdown(t, md, func(cfg *config.Config) {
+		cfg.Styles.TaskList.StrikeCompleted = true
+	})
+	if strings.Contains(doc.document, "<w:numPr>") {
+		t.Error("task items should not carry list numbering")
+	}
+	texts := strings.Join(doc.texts(t), "|")
+	for _, want := range []string{"☒|完成", "☐|待办", "☐|子任务"} {
+		if !strings.Contains(texts, want) {
+			t.Errorf("texts %q missing %q", texts, want)
+		}
+	}
+	if !strings.Contains(doc.document, `<w:ind w:left="840" w:hanging="420"/>`) {
+		t.Error("nested task item should be indented one level")
+	}
+	if n := strings.Count(doc.document, "<w:strike/>"); n != 1 {
+		t.Errorf("struck runs = %d, want 1 (completed item text only)", n)
+	}
+}
@@ -0,0 +1,38 @@
+package converter
+
+import (
+	"time"
+
+	"md2word/internal/i18n"
+	"md2word/internal/parser"
+)
+
+// SetVariables 设置 {{name}} 占位符的替换值，覆盖同名的内置变量（date：转换当天日期）
+func (c *Converter) SetVariables(vars map[string]string) {
+	c.variables = vars
+}
+
+// variable 返回变量值：调用方设置的值优先，其次为内置变量
+func (c *Converter) variable(name string) (string, bool) {
+	if v, ok := c.variables[name]; ok {
+		return v, true
+	}
+	switch name {
+	case "date":
+		return time.Now().Format("2006-01-02"), true
+	}
+	return "", false
+}
+
+// variableText 返回 {{name}} 的值；未定义的变量保留原文，
+// variables.strict 时记录第一个未定义的变量，转换结束后报错
+func (c *Converter) variableText(node *parser.Variable) string {
+	name := string(node.Name)
+	if v, ok := c.variable(name); ok {
+		return v
+	}
+	if c.config.Variables.Strict && c.variableErr == nil {
+		c.variableErr = c.errorf(i18n.UndefinedVariable, name)
+	}
+	return string(node.Segment.Value(c.source))
+}
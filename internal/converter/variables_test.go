@@ -0,0 +1,52 @@
+package converter
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"md2word/internal/config"
+	"md2word/internal/docx"
+)
+
+func TestVariables(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Mermaid.Enabled = false
+	conv := NewConverter(cfg)
+	conv.SetVariables(map[string]string{"version": "v1.2", "date": "2024-01-01"})
+	elems, err := conv.Build(context.Background(), []byte("# 手册 {{version}}\n\n发布于 {{ date }}，作者 {{author}}。\n"), t.TempDir())
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	var texts []string
+	for _, elem := range elems {
+		if p, ok := elem.(*docx.Paragraph); ok {
+			for _, run := range p.Runs() {
+				texts = append(texts, run.Text)
+			}
+		}
+	}
+	got := strings.Join(texts, "")
+	for _, want := range []string{"手册 v1.2", "发布于 2024-01-01，作者 {{author}}。"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+}
+
+func TestVariablesBuiltinAndStrict(t *testing.T) {
+	doc := convertMarkdown(t, "日期: {{date}}\n", nil)
+	if want := "日期: " + time.Now().Format("2006-01-02"); !strings.Contains(strings.Join(doc.texts(t), ""), want) {
+		t.Errorf("texts = %q, want %q", doc.texts(t), want)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Mermaid.Enabled = false
+	cfg.Variables.Strict = true
+	err := NewConverter(cfg).Convert(context.Background(), []byte("版本 {{version}}\n"), filepath.Join(t.TempDir(), "out.docx"))
+	if err == nil || !strings.Contains(err.Error(), "{{version}}") {
+		t.Errorf("Convert error = %v, want undefined variable error", err)
+	}
+}
@@ -0,0 +1,98 @@
+package converter
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce 连续保存时等待文件静止的时间，期间的多次变化只触发一次转换
+const watchDebounce = 300 * time.Millisecond
+
+// Watch 转换 path 到 outputPath，之后监视 Markdown 文件及其引用的本地图片，
+// 变化后重新转换，直到 ctx 被取消（此时返回 ctx.Err()）。
+// 每次转换完成后以转换错误（成功时为 nil）调用 onResult，监视本身出错时也经 onResult 报告；
+// 转换失败不会结束监视。
+// 各次转换共用一个浏览器实例
+func (c *Converter) Watch(ctx context.Context, path, outputPath string, onResult func(error)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	c.keepBrowser = true
+	defer func() {
+		c.keepBrowser = false
+		c.Close()
+	}()
+
+	// 监视所在目录而非文件本身：编辑器常以“写临时文件再改名”的方式保存
+	watchedDirs := make(map[string]bool)
+	var watched map[string]bool
+	updateWatches := func() {
+		watched = map[string]bool{absPath(path): true}
+		for _, f := range c.localFiles {
+			watched[absPath(f)] = true
+		}
+		for f := range watched {
+			dir := filepath.Dir(f)
+			if watchedDirs[dir] {
+				continue
+			}
+			if err := watcher.Add(dir); err != nil {
+				report(onResult, err)
+				continue
+			}
+			watchedDirs[dir] = true
+		}
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			err := c.convertFile(ctx, path, outputPath)
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			report(onResult, err)
+			updateWatches()
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Has(fsnotify.Chmod) && !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) {
+				continue
+			}
+			if watched[absPath(event.Name)] {
+				timer.Reset(watchDebounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			report(onResult, err)
+		}
+	}
+}
+
+// report 调用可选的结果回调
+func report(onResult func(error), err error) {
+	if onResult != nil {
+		onResult(err)
+	}
+}
+
+// absPath 返回用于比较的绝对路径，失败时返回清理后的原路径
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
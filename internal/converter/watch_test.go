@@ -0,0 +1,69 @@
+package converter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"md2word/internal/config"
+)
+
+func TestWatchReconvertsOnChange(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "doc.md")
+	out := filepath.Join(dir, "doc.docx")
+	if err := os.WriteFile(src, []byte("第一版\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Mermaid.Enabled = false
+	cfg.Variables.Strict = true
+	ctx, cancel := context.WithCancel(context.Background())
+	results := make(chan error, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- NewConverter(cfg).Watch(ctx, src, out, func(err error) { results <- err })
+	}()
+
+	next := func() error {
+		t.Helper()
+		select {
+		case err := <-results:
+			return err
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for conversion")
+			return nil
+		}
+	}
+
+	if err := next(); err != nil {
+		t.Fatalf("initial conversion: %v", err)
+	}
+
+	// 转换失败不结束监视
+	if err := os.WriteFile(src, []byte("{{missing}}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := next(); err == nil {
+		t.Fatal("expected error for undefined variable")
+	}
+
+	if err := os.WriteFile(src, []byte("第二版\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := next(); err != nil {
+		t.Fatalf("reconversion: %v", err)
+	}
+	if texts := strings.Join(readDocx(t, out).texts(t), ""); texts != "第二版" {
+		t.Errorf("texts = %q, want 第二版", texts)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Watch returned %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,94 @@
+package converter
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+
+	"md2word/internal/docx"
+	"md2word/internal/parser"
+)
+
+// wikiImageExts 按图片嵌入的 ![[...]] 扩展名，其他嵌入按链接处理
+var wikiImageExts = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".bmp": true, ".webp": true, ".svg": true,
+}
+
+// wikiKey 链接目标对应的标题匹配键：[[笔记#标题]] 取 # 之后的部分，忽略大小写
+func wikiKey(target string) string {
+	if i := strings.LastIndex(target, "#"); i >= 0 {
+		target = target[i+1:]
+	}
+	return strings.ToLower(strings.TrimSpace(target))
+}
+
+// collectWikiAnchors 找出被 WikiLink 引用的标题并为其分配书签名，
+// 同名标题只有第一个作为链接目标；书签名按全部标题依次生成，保证互不重复
+func (c *Converter) collectWikiAnchors(root ast.Node) {
+	c.headingBookmarks = nil
+	c.wikiAnchors = nil
+
+	targets := make(map[string]bool)
+	var headings []*ast.Heading
+	ast.Walk(root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *parser.WikiLink:
+			targets[wikiKey(string(node.Target))] = true
+		case *ast.Heading:
+			headings = append(headings, node)
+		}
+		return ast.WalkContinue, nil
+	})
+	if len(targets) == 0 {
+		return
+	}
+
+	c.headingBookmarks = make(map[*ast.Heading]string)
+	c.wikiAnchors = make(map[string]string)
+	names := newAnchorNames(c.config.TOC.AnchorPrefix)
+	for _, h := range headings {
+		name := names.heading(h)
+		var text strings.Builder
+		c.extractTextFromNode(h, &text)
+		keys := []string{wikiKey(text.String())}
+		// 带编号的标题也可以用去掉编号后的文字引用
+		if num := docx.ParseHeadingNumber(text.String()); num != nil {
+			keys = append(keys, wikiKey(num.Text))
+		}
+		for _, key := range keys {
+			if !targets[key] || c.wikiAnchors[key] != "" {
+				continue
+			}
+			c.headingBookmarks[h] = name
+			c.wikiAnchors[key] = name
+		}
+	}
+}
+
+// processWikiLink 处理 [[...]] 链接与 ![[...]] 嵌入：
+// 图片嵌入按图片插入，链接指向同名标题的书签，找不到目标时只显示文字
+func (c *Converter) processWikiLink(node *parser.WikiLink, p docx.RunContainer, bold, italic, code, strike bool) {
+	target := string(node.Target)
+	if node.Embed && wikiImageExts[strings.ToLower(filepath.Ext(target))] {
+		c.addImage(target, p)
+		return
+	}
+
+	label := target
+	if node.Label != nil {
+		label = string(node.Label)
+	}
+	anchor := c.wikiAnchors[wikiKey(target)]
+	para, ok := p.(*docx.Paragraph)
+	if anchor == "" || !ok {
+		c.addTextRun(p, label, bold, italic, code, strike)
+		return
+	}
+	link := para.AddAnchorLink(anchor)
+	c.addTextRun(link, label, bold, italic, code, strike)
+	c.styleLinkRuns(link)
+}
@@ -0,0 +1,72 @@
+package converter
+
+import (
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"md2word/internal/config"
+)
+
+func TestWikiLinks(t *testing.T) {
+	md := "# 1.2 安装步骤\n\n参见 [[安装步骤]]、[[其他笔记#1.2 安装步骤|这里]] 与 [[不存在的页面]]。\n"
+	doc := convertMarkdown(t, md, nil)
+
+	if n := strings.Count(doc.document, `<w:bookmarkStart w:id=`); n != 1 {
+		t.Fatalf("bookmark count = %d, want 1", n)
+	}
+	if n := strings.Count(doc.document, `<w:hyperlink w:anchor="h12"`); n != 2 {
+		t.Errorf("anchor link count = %d, want 2", n)
+	}
+	texts := strings.Join(doc.texts(t), "")
+	for _, want := range []string{"安装步骤", "这里", "不存在的页面"} {
+		if !strings.Contains(texts, want) {
+			t.Errorf("texts %q missing %q", texts, want)
+		}
+	}
+	if strings.Contains(texts, "[[") {
+		t.Errorf("wiki link markup left in %q", texts)
+	}
+}
+
+func TestWikiLinkAnchorNames(t *testing.T) {
+	md := "# 安装\n\n## Setup Guide\n\n## Setup Guide\n\n# 配置\n\n见 [[安装]]、[[Setup Guide]] 与 [[配置]]。\n"
+	doc := convertMarkdown(t, md, func(cfg *config.Config) { cfg.TOC.AnchorPrefix = "doc1_" })
+
+	for _, name := range []string{"doc1_heading", "doc1_setup_guide", "doc1_heading_1"} {
+		if !strings.Contains(doc.document, `w:name="`+name+`"`) {
+			t.Errorf("bookmark %q missing", name)
+		}
+		if !strings.Contains(doc.document, `w:anchor="`+name+`"`) {
+			t.Errorf("link to %q missing", name)
+		}
+	}
+	if n := strings.Count(doc.document, `<w:bookmarkStart w:id=`); n != 3 {
+		t.Errorf("bookmark count = %d, want 3", n)
+	}
+}
+
+func TestAnchorNamesUniqueAndValid(t *testing.T) {
+	names := newAnchorNames("")
+	long := strings.Repeat("a", 50)
+	got := []string{names.unique(sanitizeBookmarkName(long)), names.unique(sanitizeBookmarkName(long)), names.unique(sanitizeBookmarkName("1-intro")), names.unique(sanitizeBookmarkName(""))}
+	want := []string{strings.Repeat("a", 40), strings.Repeat("a", 38) + "_1", "h1_intro", "heading"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("name %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestWikiEmbedImage(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "pic.png"), makePNG(t, 20, 10, func(x, y int) color.NRGBA { return color.NRGBA{0, 0, 200, 255} }), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	doc := convertMarkdown(t, "![["+filepath.Join(dir, "pic.png")+"]]\n", nil)
+	if !strings.Contains(doc.document, "<w:drawing>") {
+		t.Error("embedded image missing")
+	}
+}
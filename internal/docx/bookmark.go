@@ -0,0 +1,33 @@
+package docx
+
+import "strconv"
+
+// BookmarkStart 书签起点（段落子元素）
+type BookmarkStart struct {
+	ID   int
+	Name string
+}
+
+// ToXML 转换为XML
+func (b *BookmarkStart) ToXML() string {
+	return `
+            <w:bookmarkStart w:id="` + strconv.Itoa(b.ID) + `" w:name="` + XMLEscape(b.Name) + `"/>`
+}
+
+// BookmarkEnd 书签终点（段落子元素）
+type BookmarkEnd struct {
+	ID int
+}
+
+// ToXML 转换为XML
+func (b *BookmarkEnd) ToXML() string {
+	return `
+            <w:bookmarkEnd w:id="` + strconv.Itoa(b.ID) + `"/>`
+}
+
+// AddBookmark 以书签 name 包住段落现有的全部内容，id 由 Document.NextAnnotationID 分配；
+// 超链接设置 Anchor 为 name 即可跳转到该段落
+func (p *Paragraph) AddBookmark(id int, name string) {
+	p.Children = append([]ParagraphChild{&BookmarkStart{ID: id, Name: name}}, p.Children...)
+	p.Children = append(p.Children, &BookmarkEnd{ID: id})
+}
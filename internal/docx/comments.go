@@ -0,0 +1,98 @@
+package docx
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+)
+
+const relTypeComments = relTypeBase + "comments"
+
+// Comment 批注（comments.xml 中的一条）
+type Comment struct {
+	ID     int
+	Author string
+	Text   string
+}
+
+// AddComment 登记一条批注并返回其ID，由 CommentRangeStart/CommentRangeEnd 标记批注范围
+func (d *Document) AddComment(author, text string) int {
+	d.ensureCommentsPart()
+	id := d.NextAnnotationID()
+	d.comments = append(d.comments, Comment{ID: id, Author: author, Text: text})
+	return id
+}
+
+// ensureCommentsPart 首次使用时登记 comments.xml 的关系
+func (d *Document) ensureCommentsPart() {
+	if d.commentsRelID == "" {
+		d.commentsRelID = d.addRelationship(relTypeComments, "comments.xml", "")
+	}
+}
+
+// writeComments 写入 word/comments.xml；未登记批注部件时不写
+func (d *Document) writeComments(w partCreator) error {
+	if d.commentsRelID == "" {
+		return nil
+	}
+	f, err := w.Create("word/comments.xml")
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:comments xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">`)
+	for _, c := range d.comments {
+		buf.WriteString(`
+    <w:comment w:id="`)
+		writeInt(&buf, c.ID)
+		buf.WriteString(`" w:author="`)
+		writeEscaped(&buf, c.Author)
+		buf.WriteString(`">
+        <w:p>
+            <w:r>`)
+		writeTextElement(&buf, c.Text)
+		buf.WriteString(`
+            </w:r>
+        </w:p>
+    </w:comment>`)
+	}
+	buf.WriteString(`
+</w:comments>`)
+
+	_, err = io.Copy(f, &buf)
+	return err
+}
+
+// CommentRangeStart 批注范围起点（段落子元素）
+type CommentRangeStart struct {
+	ID int
+}
+
+// ToXML 转换为XML
+func (c *CommentRangeStart) ToXML() string {
+	return `
+            <w:commentRangeStart w:id="` + strconv.Itoa(c.ID) + `"/>`
+}
+
+// CommentRangeEnd 批注范围终点，其后紧跟显示批注标记的引用运行（段落子元素）
+type CommentRangeEnd struct {
+	ID int
+}
+
+// ToXML 转换为XML
+func (c *CommentRangeEnd) ToXML() string {
+	id := strconv.Itoa(c.ID)
+	return `
+            <w:commentRangeEnd w:id="` + id + `"/>
+            <w:r>
+                <w:commentReference w:id="` + id + `"/>
+            </w:r>`
+}
+
+// AddComment 以批注 id 包住段落现有的全部内容
+func (p *Paragraph) AddComment(id int) {
+	p.Children = append([]ParagraphChild{&CommentRangeStart{ID: id}}, p.Children...)
+	p.Children = append(p.Children, &CommentRangeEnd{ID: id})
+}
@@ -6,8 +6,10 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io"
+	"mime"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"md2word/internal/config"
 )
@@ -23,9 +25,26 @@ type Document struct {
 	elements       []Element
 	images         map[string]*ImageData
 	imageCount     int
+	imageExts      map[string]bool // 已嵌入图片的扩展名，决定 [Content_Types].xml 声明哪些图片格式
+	relCount       int // 已分配的关系ID数，document.xml.rels 中所有关系共用
 	rels           []Relationship
 	contentRels    []Relationship
 	numberingState *NumberingState
+	section        *Section // 当前节
+	footerRelID    string   // 页码页脚的关系ID，为空表示无页脚
+	headerRelID    string   // 页眉的关系ID，为空表示无页眉
+	firstHeaderID  string   // 首页页眉的关系ID，为空表示首页页眉空白（仅 differentFirst 时使用）
+	evenHeaderID   string   // 偶数页页眉的关系ID，为空表示偶数页页眉空白（仅 evenAndOdd 时使用）
+	firstSection   *Section // 第一节，首页不同的页眉页脚只作用于它
+	headerFooters  []headerFooterPart
+	comments       []Comment
+	commentsRelID  string // comments.xml 的关系ID，为空表示无批注部件
+	source         []byte // 嵌入的 Markdown 源文件，见 EmbedSource
+	sourceRelID    string // 源文件自定义 XML 部件的关系ID，为空表示未嵌入
+	annotations    int    // 已分配的批注/修订ID数
+	fontEmbeds     map[string][]*FontEmbed
+	fontEmbedOrder []string
+	stream         *documentStream // 流式写入状态，为 nil 表示普通模式
 }
 
 // ImageData 图片数据
@@ -44,67 +63,136 @@ type Relationship struct {
 	TargetMode string
 }
 
+// 文档关系类型
+const (
+	relTypeBase      = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/"
+	relTypeStyles    = relTypeBase + "styles"
+	relTypeNumbering = relTypeBase + "numbering"
+	relTypeSettings  = relTypeBase + "settings"
+	relTypeFontTable = relTypeBase + "fontTable"
+	relTypeFooter    = relTypeBase + "footer"
+	relTypeHeader    = relTypeBase + "header"
+	relTypeImage     = relTypeBase + "image"
+	relTypeHyperlink = relTypeBase + "hyperlink"
+)
+
 // NewDocument 创建新文档
 func NewDocument(cfg *config.Config) *Document {
-	return &Document{
+	d := &Document{
 		config:         cfg,
 		elements:       make([]Element, 0),
 		images:         make(map[string]*ImageData),
+		imageExts:      make(map[string]bool),
 		rels:           make([]Relationship, 0),
 		numberingState: NewNumberingState(),
+		section: &Section{
+			PageNumberFormat: cfg.Page.PageNumber.Format,
+			PageNumberStart:  cfg.Page.PageNumber.Start,
+		},
+	}
+	d.addRelationship(relTypeStyles, "styles.xml", "")
+	d.addRelationship(relTypeNumbering, "numbering.xml", "")
+	d.addRelationship(relTypeSettings, "settings.xml", "")
+	d.addRelationship(relTypeFontTable, "fontTable.xml", "")
+	if cfg.Page.PageNumber.Enabled {
+		d.footerRelID = d.addHeaderFooter(relTypeFooter, "footer1.xml", GeneratePageNumberFooter())
+	}
+	if text := cfg.Page.Header.Text; text != "" {
+		d.headerRelID = d.addHeaderFooter(relTypeHeader, "header1.xml", GenerateHeader(text))
 	}
+	if text := cfg.Page.Header.FirstPage; cfg.Page.Header.DifferentFirst && text != "" {
+		d.firstHeaderID = d.addHeaderFooter(relTypeHeader, "header2.xml", GenerateHeader(text))
+	}
+	if text := cfg.Page.Header.EvenPage; cfg.Page.Header.EvenAndOdd && text != "" {
+		d.evenHeaderID = d.addHeaderFooter(relTypeHeader, "header3.xml", GenerateHeader(text))
+	}
+	d.firstSection = d.section
+	return d
+}
+
+// addRelationship 登记 document.xml 的一条关系并返回新分配的ID。
+// 所有部件、图片、超链接都经由此处按序分配，保证ID唯一。
+func (d *Document) addRelationship(relType, target, targetMode string) string {
+	d.relCount++
+	rID := fmt.Sprintf("rId%d", d.relCount)
+	d.contentRels = append(d.contentRels, Relationship{
+		ID:         rID,
+		Type:       relType,
+		Target:     target,
+		TargetMode: targetMode,
+	})
+	return rID
 }
 
 // AddParagraph 添加段落
 func (d *Document) AddParagraph(p Element) {
+	if d.stream != nil {
+		d.stream.writeElement(p)
+		return
+	}
 	d.elements = append(d.elements, p)
 }
 
+// ImageCount 返回已加入文档的图片文件数
+func (d *Document) ImageCount() int {
+	return d.imageCount
+}
+
+// imageTypes 可嵌入的图片格式：扩展名与声明的内容类型，按 [Content_Types].xml 中的顺序排列
+var imageTypes = []struct{ ext, contentType string }{
+	{"png", "image/png"},
+	{"jpg", "image/jpeg"},
+	{"gif", "image/gif"},
+	{"svg", "image/svg+xml"},
+	{"bmp", "image/bmp"},
+	{"tiff", "image/tiff"},
+}
+
+// imageExtension 返回内容类型对应的扩展名，不在 imageTypes 中的按 png 写入
+func imageExtension(contentType string) string {
+	for _, t := range imageTypes {
+		if t.contentType == contentType {
+			return t.ext
+		}
+	}
+	return "png"
+}
+
 // AddImage 添加图片并返回关系ID
 func (d *Document) AddImage(data []byte, contentType string, width, height int) string {
 	d.imageCount++
-	rID := fmt.Sprintf("rId%d", d.imageCount+10) // 预留前10个ID给其他关系
 	imgName := fmt.Sprintf("image%d", d.imageCount)
 
-	ext := ".png"
-	switch contentType {
-	case "image/jpeg":
-		ext = ".jpg"
-	case "image/gif":
-		ext = ".gif"
-	case "image/svg+xml":
-		ext = ".svg"
+	// 去掉 "; charset=utf-8" 等参数，否则 SVG 会被当作 PNG 写入
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		contentType = mediaType
 	}
+	ext := imageExtension(contentType)
+	d.imageExts[ext] = true
+	ext = "." + ext
 
-	d.images[imgName+ext] = &ImageData{
+	img := &ImageData{
 		Data:        data,
 		ContentType: contentType,
 		Width:       width,
 		Height:      height,
 	}
+	if d.stream != nil {
+		d.stream.writeImage(imgName+ext, img)
+	} else {
+		d.images[imgName+ext] = img
+	}
 
-	d.contentRels = append(d.contentRels, Relationship{
-		ID:     rID,
-		Type:   "http://schemas.openxmlformats.org/officeDocument/2006/relationships/image",
-		Target: "media/" + imgName + ext,
-	})
-
-	return rID
+	return d.addRelationship(relTypeImage, "media/"+imgName+ext, "")
 }
 
 // AddHyperlink 添加超链接关系并返回ID
+// 以 www. 开头、缺少协议的地址会补全为 http://，否则 Word 会把它当作相对路径
 func (d *Document) AddHyperlink(target string) string {
-	d.imageCount++ // 复用计数器或独立计数
-	rID := fmt.Sprintf("rId%d", d.imageCount+1000)
-
-	d.contentRels = append(d.contentRels, Relationship{
-		ID:         rID,
-		Type:       "http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink",
-		Target:     target,
-		TargetMode: "External",
-	})
-
-	return rID
+	if strings.HasPrefix(strings.ToLower(target), "www.") {
+		target = "http://" + target
+	}
+	return d.addRelationship(relTypeHyperlink, target, "External")
 }
 
 // GetNumberingState 获取编号状态
@@ -113,7 +201,47 @@ func (d *Document) GetNumberingState() *NumberingState {
 }
 
 // Save 保存为DOCX文件
+// 流式模式下内容已写入 StartStreaming 指定的文件，path 被忽略。
 func (d *Document) Save(path string) error {
+	if d.stream != nil {
+		return d.finishStreaming()
+	}
+
+	// 预先读取嵌入字体，内容类型与字体表都依赖它
+	embeds, embedOrder, err := loadEmbeddedFonts(d.config)
+	if err != nil {
+		return err
+	}
+	d.fontEmbeds, d.fontEmbedOrder = embeds, embedOrder
+
+	// 先在内存中生成全部部件，校验通过后再落盘
+	w := newPackageParts()
+
+	// [Content_Types].xml 作为第一个条目写入
+	if err := d.writeContentTypes(w); err != nil {
+		return err
+	}
+
+	if err := d.writePackageParts(w); err != nil {
+		return err
+	}
+
+	// 写入word/document.xml
+	if err := d.writeDocument(w); err != nil {
+		return err
+	}
+
+	// 写入图片
+	for name, img := range d.images {
+		if err := d.writeImage(w, name, img); err != nil {
+			return err
+		}
+	}
+
+	if err := d.validate(w.sources()); err != nil {
+		return err
+	}
+
 	// 确保目录存在
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -127,14 +255,16 @@ func (d *Document) Save(path string) error {
 	}
 	defer file.Close()
 
-	w := zip.NewWriter(file)
-	defer w.Close()
-
-	// 写入[Content_Types].xml
-	if err := d.writeContentTypes(w); err != nil {
+	zw := zip.NewWriter(file)
+	if err := w.writeTo(zw); err != nil {
+		zw.Close()
 		return err
 	}
+	return zw.Close()
+}
 
+// writePackageParts 写入除内容类型、正文与图片外的所有部件
+func (d *Document) writePackageParts(w partCreator) error {
 	// 写入_rels/.rels
 	if err := d.writeRels(w); err != nil {
 		return err
@@ -155,46 +285,80 @@ func (d *Document) Save(path string) error {
 		return err
 	}
 
-	// 写入word/document.xml
-	if err := d.writeDocument(w); err != nil {
+	// 写入word/settings.xml
+	if err := d.writeSettings(w); err != nil {
 		return err
 	}
 
-	// 写入图片
-	for name, img := range d.images {
-		if err := d.writeImage(w, name, img); err != nil {
-			return err
-		}
+	// 写入word/fontTable.xml
+	if err := d.writeFontTable(w); err != nil {
+		return err
 	}
 
-	return nil
+	// 写入word/comments.xml（批注）
+	if err := d.writeComments(w); err != nil {
+		return err
+	}
+
+	// 写入 customXml/item1.xml（嵌入的源文件）
+	if err := d.writeSource(w); err != nil {
+		return err
+	}
+
+	// 写入页眉页脚
+	return d.writeHeaderFooters(w)
 }
 
 // writeContentTypes 写入内容类型定义
-func (d *Document) writeContentTypes(w *zip.Writer) error {
+func (d *Document) writeContentTypes(w partCreator) error {
 	f, err := w.Create("[Content_Types].xml")
 	if err != nil {
 		return err
 	}
 
+	// 只声明用到的图片格式；流式模式下内容类型先于图片写出，声明全部支持的格式
+	var images string
+	for _, t := range imageTypes {
+		if d.stream != nil || d.imageExts[t.ext] {
+			images += `
+    <Default Extension="` + t.ext + `" ContentType="` + t.contentType + `"/>`
+		}
+	}
+
+	var extra string
+	if len(d.fontEmbeds) > 0 {
+		extra += `
+    <Default Extension="odttf" ContentType="application/vnd.openxmlformats-officedocument.obfuscatedFont"/>`
+	}
+	if d.commentsRelID != "" {
+		extra += `
+    <Override PartName="/word/comments.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.comments+xml"/>`
+	}
+	if d.sourceRelID != "" {
+		extra += `
+    <Override PartName="/customXml/itemProps1.xml" ContentType="application/vnd.openxmlformats-officedocument.customXmlProperties+xml"/>`
+	}
+	for _, part := range d.headerFooters {
+		extra += `
+    <Override PartName="/word/` + part.name + `" ContentType="` + part.contentType() + `"/>`
+	}
+
 	content := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
 <Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
     <Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
-    <Default Extension="xml" ContentType="application/xml"/>
-    <Default Extension="png" ContentType="image/png"/>
-    <Default Extension="jpg" ContentType="image/jpeg"/>
-    <Default Extension="jpeg" ContentType="image/jpeg"/>
-    <Default Extension="gif" ContentType="image/gif"/>
+    <Default Extension="xml" ContentType="application/xml"/>` + images + `
     <Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>
     <Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>
     <Override PartName="/word/numbering.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.numbering+xml"/>
+    <Override PartName="/word/settings.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.settings+xml"/>
+    <Override PartName="/word/fontTable.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.fontTable+xml"/>` + extra + `
 </Types>`
 	_, err = io.WriteString(f, content)
 	return err
 }
 
 // writeRels 写入根关系
-func (d *Document) writeRels(w *zip.Writer) error {
+func (d *Document) writeRels(w partCreator) error {
 	f, err := w.Create("_rels/.rels")
 	if err != nil {
 		return err
@@ -209,7 +373,7 @@ func (d *Document) writeRels(w *zip.Writer) error {
 }
 
 // writeDocumentRels 写入文档关系
-func (d *Document) writeDocumentRels(w *zip.Writer) error {
+func (d *Document) writeDocumentRels(w partCreator) error {
 	f, err := w.Create("word/_rels/document.xml.rels")
 	if err != nil {
 		return err
@@ -217,17 +381,15 @@ func (d *Document) writeDocumentRels(w *zip.Writer) error {
 
 	var buf bytes.Buffer
 	buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
-<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
-    <Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
-    <Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/numbering" Target="numbering.xml"/>`)
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
 
 	for _, rel := range d.contentRels {
 		if rel.TargetMode != "" {
 			buf.WriteString(fmt.Sprintf(`
-    <Relationship Id="%s" Type="%s" Target="%s" TargetMode="%s"/>`, rel.ID, rel.Type, rel.Target, rel.TargetMode))
+    <Relationship Id="%s" Type="%s" Target="%s" TargetMode="%s"/>`, rel.ID, rel.Type, XMLEscape(rel.Target), rel.TargetMode))
 		} else {
 			buf.WriteString(fmt.Sprintf(`
-    <Relationship Id="%s" Type="%s" Target="%s"/>`, rel.ID, rel.Type, rel.Target))
+    <Relationship Id="%s" Type="%s" Target="%s"/>`, rel.ID, rel.Type, XMLEscape(rel.Target)))
 		}
 	}
 
@@ -239,7 +401,7 @@ func (d *Document) writeDocumentRels(w *zip.Writer) error {
 }
 
 // writeStyles 写入样式定义
-func (d *Document) writeStyles(w *zip.Writer) error {
+func (d *Document) writeStyles(w partCreator) error {
 	f, err := w.Create("word/styles.xml")
 	if err != nil {
 		return err
@@ -251,51 +413,120 @@ func (d *Document) writeStyles(w *zip.Writer) error {
 }
 
 // writeNumbering 写入编号定义
-func (d *Document) writeNumbering(w *zip.Writer) error {
+func (d *Document) writeNumbering(w partCreator) error {
 	f, err := w.Create("word/numbering.xml")
 	if err != nil {
 		return err
 	}
 
-	numbering := GenerateNumberingXML(d.numberingState.GetNumberingInstances())
+	numbering := GenerateNumberingXML(d.numberingState.GetNumberingInstances(), d.numberingState.lists)
 	_, err = io.WriteString(f, numbering)
 	return err
 }
 
+// writeSettings 写入文档设置
+func (d *Document) writeSettings(w partCreator) error {
+	f, err := w.Create("word/settings.xml")
+	if err != nil {
+		return err
+	}
+
+	settings := GenerateSettings(d.config)
+	_, err = io.WriteString(f, settings)
+	return err
+}
+
+// writeFontTable 写入字体表
+func (d *Document) writeFontTable(w partCreator) error {
+	f, err := w.Create("word/fontTable.xml")
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(f, GenerateFontTable(d.config, d.fontEmbeds, d.fontEmbedOrder)); err != nil {
+		return err
+	}
+	if len(d.fontEmbeds) == 0 {
+		return nil
+	}
+
+	// 嵌入字体文件及 fontTable.xml 的关系
+	var rels bytes.Buffer
+	rels.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for _, name := range d.fontEmbedOrder {
+		for _, embed := range d.fontEmbeds[name] {
+			rels.WriteString(fmt.Sprintf(`
+    <Relationship Id="%s" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/font" Target="fonts/%s"/>`, embed.RelID, embed.PartName))
+
+			ff, err := w.Create("word/fonts/" + embed.PartName)
+			if err != nil {
+				return err
+			}
+			if _, err := ff.Write(embed.Data); err != nil {
+				return err
+			}
+		}
+	}
+	rels.WriteString(`
+</Relationships>`)
+
+	rf, err := w.Create("word/_rels/fontTable.xml.rels")
+	if err != nil {
+		return err
+	}
+	_, err = rf.Write(rels.Bytes())
+	return err
+}
+
 // writeDocument 写入文档内容
-func (d *Document) writeDocument(w *zip.Writer) error {
+func (d *Document) writeDocument(w partCreator) error {
 	f, err := w.Create("word/document.xml")
 	if err != nil {
 		return err
 	}
 
+	var buf bytes.Buffer
+	buf.WriteString(d.documentHeaderXML())
+	for _, elem := range d.elements {
+		writeElementXML(&buf, elem)
+	}
+	buf.WriteString(d.documentTrailerXML())
+
+	_, err = f.Write(buf.Bytes())
+	return err
+}
+
+// documentHeaderXML document.xml 中正文元素之前的部分
+func (d *Document) documentHeaderXML() string {
 	var buf bytes.Buffer
 	buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
 <w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"
             xmlns:wp="http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing"
             xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"
             xmlns:pic="http://schemas.openxmlformats.org/drawingml/2006/picture"
-            xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
-    <w:body>`)
+            xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`)
 
-	for _, elem := range d.elements {
-		buf.WriteString(elem.ToXML())
+	// 页面背景色（需配合 settings.xml 中的 displayBackgroundShape）
+	if d.config.Page.Background != "" {
+		buf.WriteString(`
+    <w:background w:color="` + strings.TrimPrefix(d.config.Page.Background, "#") + `"/>`)
 	}
 
-	buf.WriteString(fmt.Sprintf(`
-        <w:sectPr>
-            <w:pgSz w:w="%d" w:h="%d"/>
-            <w:pgMar w:top="%d" w:right="%d" w:bottom="%d" w:left="%d" w:header="851" w:footer="992" w:gutter="0"/>
-        </w:sectPr>
-    </w:body>
-</w:document>`, PageWidthTwips, PageHeightTwips, MarginTop, MarginRight, MarginBottom, MarginLeft))
+	buf.WriteString(`
+    <w:body>`)
+	return buf.String()
+}
 
-	_, err = f.Write(buf.Bytes())
-	return err
+// documentTrailerXML document.xml 中正文元素之后的部分（最后一节的属性）
+func (d *Document) documentTrailerXML() string {
+	return d.sectionPropertiesXML(d.section) + `
+    </w:body>
+</w:document>`
 }
 
 // writeImage 写入图片文件
-func (d *Document) writeImage(w *zip.Writer, name string, img *ImageData) error {
+func (d *Document) writeImage(w partCreator, name string, img *ImageData) error {
 	f, err := w.Create("word/media/" + name)
 	if err != nil {
 		return err
@@ -327,6 +558,11 @@ func ContentWidthTwips() int {
 	return PageWidthTwips - MarginLeft - MarginRight
 }
 
+// LandscapeContentWidthTwips 返回横向页面内容区可用宽度（页面高度 - 上下边距）
+func LandscapeContentWidthTwips() int {
+	return PageHeightTwips - MarginTop - MarginBottom
+}
+
 // ContentWidthPx 把内容宽度换算为 96DPI 下的像素数。
 // 1 twip = 1/1440 inch，96 DPI 下 1 inch = 96 px，故 1 twip = 96/1440 px。
 func ContentWidthPx() int {
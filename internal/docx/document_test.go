@@ -0,0 +1,332 @@
+package docx
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"md2word/internal/config"
+)
+
+func TestSaveDeclaresImageContentTypes(t *testing.T) {
+	images := []struct {
+		contentType string
+		data        []byte
+		part        string
+	}{
+		{"image/png", []byte("\x89PNG\r\n\x1a\n"), "image1.png"},
+		{"image/jpeg", []byte("\xff\xd8\xff"), "image1.jpg"},
+		{"image/gif", []byte("GIF89a"), "image1.gif"},
+		{"image/svg+xml", []byte(`<svg xmlns="http://www.w3.org/2000/svg"/>`), "image1.svg"},
+		{"image/svg+xml; charset=utf-8", []byte(`<svg xmlns="http://www.w3.org/2000/svg"/>`), "image1.svg"},
+	}
+	for _, img := range images {
+		t.Run(img.contentType, func(t *testing.T) {
+			doc := NewDocument(config.DefaultConfig())
+			rID := doc.AddImage(img.data, img.contentType, 10, 10)
+			p := NewParagraph("")
+			p.AddImageRun(rID, 9525, 9525)
+			doc.AddParagraph(p)
+			out := filepath.Join(t.TempDir(), "out.docx")
+			if err := doc.Save(out); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+			if _, ok := readZipParts(t, out)["word/media/"+img.part]; !ok {
+				t.Errorf("media part %s missing", img.part)
+			}
+		})
+	}
+}
+
+func TestContentTypesDeclareUsedImageFormats(t *testing.T) {
+	doc := NewDocument(config.DefaultConfig())
+	rID := doc.AddImage([]byte("\xff\xd8\xff"), "image/jpeg", 10, 10)
+	p := NewParagraph("")
+	p.AddImageRun(rID, 9525, 9525)
+	doc.AddParagraph(p)
+	out := filepath.Join(t.TempDir(), "out.docx")
+	if err := doc.Save(out); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	types := readZipParts(t, out)["[Content_Types].xml"]
+	if !strings.Contains(types, `<Default Extension="jpg" ContentType="image/jpeg"/>`) {
+		t.Error("jpg content type missing")
+	}
+	for _, ext := range []string{"png", "jpeg", "gif", "svg"} {
+		if strings.Contains(types, `Extension="`+ext+`"`) {
+			t.Errorf("unused %s content type declared", ext)
+		}
+	}
+}
+
+func TestSVGImageWithFallback(t *testing.T) {
+	doc := NewDocument(config.DefaultConfig())
+	pngID := doc.AddImage([]byte("\x89PNG\r\n\x1a\n"), "image/png", 10, 10)
+	svgID := doc.AddImage([]byte(`<svg xmlns="http://www.w3.org/2000/svg"/>`), "image/svg+xml", 10, 10)
+	p := NewParagraph("")
+	p.AddImageRun(pngID, 9525, 9525).SVGRelID = svgID
+	doc.AddParagraph(p)
+	out := filepath.Join(t.TempDir(), "out.docx")
+	if err := doc.Save(out); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	parts := readZipParts(t, out)
+	if !strings.Contains(parts["word/document.xml"], `<asvg:svgBlip xmlns:asvg="http://schemas.microsoft.com/office/drawing/2016/SVG/main" r:embed="`+svgID+`"/>`) {
+		t.Error("svgBlip extension missing")
+	}
+	if _, ok := parts["word/media/image2.svg"]; !ok {
+		t.Error("svg part missing")
+	}
+}
+
+func TestBodyTextStyle(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Styles.Body.SpaceAfter = 120
+	styles := GenerateStyles(cfg)
+	start := strings.Index(styles, `w:styleId="BodyText"`)
+	end := strings.Index(styles, `w:styleId="Heading1"`)
+	if start < 0 || end < start {
+		t.Fatal("BodyText style missing")
+	}
+	body := styles[start:end]
+	for _, want := range []string{
+		`<w:basedOn w:val="Normal"/>`,
+		`<w:spacing w:before="0" w:after="120" w:line="360" w:lineRule="auto"/>`,
+		`<w:ind w:firstLine="420"/>`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("BodyText style missing %s:\n%s", want, body)
+		}
+	}
+}
+
+func TestQuoteStyle(t *testing.T) {
+	styles := GenerateStyles(config.DefaultConfig())
+	start := strings.Index(styles, `w:styleId="Quote"`)
+	end := strings.Index(styles, `w:styleId="Heading1"`)
+	if start < 0 || end < start {
+		t.Fatal("Quote style missing")
+	}
+	quote := styles[start:end]
+	for _, want := range []string{"<w:pBdr>", `w:fill="F0F0F0"`, `<w:ind w:left="360"/>`, `<w:next w:val="BodyText"/>`} {
+		if !strings.Contains(quote, want) {
+			t.Errorf("Quote style missing %s:\n%s", want, quote)
+		}
+	}
+}
+
+func TestCodeBlockStyle(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Styles.CodeBlock.Font = "Menlo"
+	cfg.Styles.CodeBlock.Size = 9
+	cfg.Styles.CodeBlock.LineSpacing = 40
+	styles := GenerateStyles(cfg)
+	start := strings.Index(styles, `w:styleId="CodeBlock"`)
+	if start < 0 {
+		t.Fatal("CodeBlock style missing")
+	}
+	code := styles[start : start+strings.Index(styles[start:], "</w:style>")]
+	for _, want := range []string{
+		`<w:spacing w:before="20" w:after="20" w:line="240" w:lineRule="auto"/>`,
+		`w:ascii="Menlo"`,
+		`<w:sz w:val="18"/>`,
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("CodeBlock style missing %s:\n%s", want, code)
+		}
+	}
+}
+
+func TestHeadingStyleColor(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Styles.Heading2.Color = "#1F4E79"
+	styles := GenerateStyles(cfg)
+	start := strings.Index(styles, `w:styleId="Heading2"`)
+	end := strings.Index(styles, `w:styleId="Heading3"`)
+	if start < 0 || end < start {
+		t.Fatal("heading styles missing")
+	}
+	if !strings.Contains(styles[start:end], `<w:color w:val="1F4E79"/>`) {
+		t.Errorf("Heading2 style has no color:\n%s", styles[start:end])
+	}
+	if strings.Contains(styles[:start], `<w:color w:val="1F4E79"/>`) {
+		t.Error("color leaked into other styles")
+	}
+}
+
+func TestStyleItalic(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Styles.Body.Italic = true
+	cfg.Styles.Heading1.Italic = true
+	styles := GenerateStyles(cfg)
+	for _, id := range []string{"Normal", "Heading1"} {
+		start := strings.Index(styles, `w:styleId="`+id+`"`)
+		end := start + strings.Index(styles[start:], "</w:style>")
+		style := styles[start:end]
+		if !strings.Contains(style, "<w:i/>") {
+			t.Errorf("%s style is not italic", id)
+		}
+		if strings.Index(style, "<w:sz ") < strings.Index(style, "<w:iCs/>") {
+			t.Errorf("%s style: sz must follow i/iCs in rPr", id)
+		}
+	}
+	start := strings.Index(styles, `w:styleId="Heading2"`)
+	end := start + strings.Index(styles[start:], "</w:style>")
+	if strings.Contains(styles[start:end], "<w:i/>") {
+		t.Error("Heading2 style is italic")
+	}
+}
+
+func TestStyleBackground(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Styles.Body.Background = "#FFFDE7"
+	cfg.Styles.Heading1.Background = "#DDEEFF"
+	styles := GenerateStyles(cfg)
+	for id, fill := range map[string]string{"Normal": "FFFDE7", "Heading1": "DDEEFF"} {
+		start := strings.Index(styles, `w:styleId="`+id+`"`)
+		end := start + strings.Index(styles[start:], "</w:style>")
+		if !strings.Contains(styles[start:end], `<w:shd w:val="clear" w:color="auto" w:fill="`+fill+`"/>`) {
+			t.Errorf("%s style has no %s shading", id, fill)
+		}
+	}
+}
+
+func TestHeadingLetterSpacing(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Styles.Heading1.LetterSpacing = 40
+	styles := GenerateStyles(cfg)
+	start := strings.Index(styles, `w:styleId="Heading1"`)
+	end := start + strings.Index(styles[start:], "</w:style>")
+	if !strings.Contains(styles[start:end], `<w:spacing w:val="40"/>`) {
+		t.Errorf("Heading1 style has no letter spacing:\n%s", styles[start:end])
+	}
+	if strings.Contains(styles[end:], `<w:spacing w:val=`) {
+		t.Error("letter spacing leaked into other styles")
+	}
+}
+
+func TestRunLetterSpacing(t *testing.T) {
+	run := &Run{Text: "标题", Spacing: -10}
+	if got := run.ToXML(); !strings.Contains(got, `<w:spacing w:val="-10"/>`) {
+		t.Errorf("run XML missing letter spacing:\n%s", got)
+	}
+	if got := (&Run{Text: "正文"}).ToXML(); strings.Contains(got, "<w:rPr>") {
+		t.Errorf("plain run has properties:\n%s", got)
+	}
+}
+
+func TestParagraphBorderStyle(t *testing.T) {
+	box := (&Paragraph{Border: true}).ToXML()
+	for _, side := range []string{"top", "left", "bottom", "right"} {
+		if !strings.Contains(box, `<w:`+side+` w:val="single" w:sz="4"`) {
+			t.Errorf("default border missing %s:\n%s", side, box)
+		}
+	}
+
+	p := &Paragraph{Border: true, BorderStyle: &ParagraphBorder{Left: true, Color: "#C00000", Width: 24, Style: BorderDouble}}
+	got := p.ToXML()
+	if !strings.Contains(got, `<w:left w:val="double" w:sz="24" w:space="4" w:color="C00000"/>`) {
+		t.Errorf("custom border missing left side:\n%s", got)
+	}
+	if strings.Contains(got, "<w:top ") || strings.Contains(got, "C0C0C0") {
+		t.Errorf("custom border should replace the default box:\n%s", got)
+	}
+}
+
+func TestMirrorMarginsAndGutter(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Page.Margins.Mirror = true
+	cfg.Page.Margins.Gutter = 567
+	if !strings.Contains(GenerateSettings(cfg), "<w:mirrorMargins/>") {
+		t.Error("settings missing mirrorMargins")
+	}
+	doc := NewDocument(cfg)
+	doc.AddSectionBreak(&Section{Landscape: true})
+	if !strings.Contains(doc.sectionPropertiesXML(doc.CurrentSection()), `w:gutter="567"`) {
+		t.Error("landscape pgMar missing gutter")
+	}
+	if !strings.Contains(doc.documentTrailerXML(), `w:gutter="567"`) {
+		t.Error("pgMar missing gutter")
+	}
+}
+
+func TestLineNumbers(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Page.LineNumbers.Enabled = true
+	cfg.Page.LineNumbers.CountBy = 5
+	cfg.Page.LineNumbers.Restart = config.LineNumbersNewPage
+	sectPr := NewDocument(cfg).documentTrailerXML()
+	ln := strings.Index(sectPr, `<w:lnNumType w:countBy="5" w:restart="newPage"/>`)
+	if ln < 0 || ln < strings.Index(sectPr, "<w:pgMar") {
+		t.Errorf("lnNumType missing or misplaced:\n%s", sectPr)
+	}
+
+	cfg.Page.LineNumbers.Enabled = false
+	if strings.Contains(NewDocument(cfg).documentTrailerXML(), "lnNumType") {
+		t.Error("lnNumType emitted while disabled")
+	}
+}
+
+func TestHeadingKeepWithNext(t *testing.T) {
+	for _, keep := range []bool{true, false} {
+		cfg := config.DefaultConfig()
+		cfg.Headings.KeepWithNext = keep
+		styles := GenerateStyles(cfg)
+		start := strings.Index(styles, `w:styleId="Heading1"`)
+		end := start + strings.Index(styles[start:], "</w:style>")
+		if got := strings.Contains(styles[start:end], "<w:keepNext/>"); got != keep {
+			t.Errorf("keepWithNext=%v: Heading1 keepNext = %v", keep, got)
+		}
+	}
+}
+
+func TestFontScale(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Styles.Body.Size = 12
+	cfg.Styles.Heading1.Size = 20
+	cfg.FontScale = 1.5
+	styles := GenerateStyles(cfg)
+	for _, want := range []string{`<w:sz w:val="36"/>`, `<w:sz w:val="60"/>`} {
+		if !strings.Contains(styles, want) {
+			t.Errorf("styles missing %s", want)
+		}
+	}
+}
+
+func TestListNumberingOrder(t *testing.T) {
+	ns := NewNumberingState()
+	ns.GetOrCreateNumberingInstance(&HeadingNumber{Level: 1, Values: []int{2}})
+	levels := []ListLevel{{Format: ListFormatDecimal, Text: "%1."}}
+	a := ns.AddListNumbering(levels, 0, 3)
+	b := ns.AddListNumbering(levels, 1, 1)
+	if a == b || a == 1 {
+		t.Fatalf("numIds = %d, %d; want distinct ids after the heading instance", a, b)
+	}
+	xml := GenerateNumberingXML(ns.GetNumberingInstances(), ns.lists)
+	if n := strings.Count(xml, `<w:abstractNum `); n != 2 {
+		t.Errorf("abstractNums = %d, want 2 (headings + one shared list format)", n)
+	}
+	if strings.LastIndex(xml, "<w:abstractNum ") > strings.Index(xml, "<w:num ") {
+		t.Error("abstractNum after num")
+	}
+	if !strings.Contains(xml, `<w:lvlOverride w:ilvl="1">
+            <w:startOverride w:val="1"/>`) {
+		t.Error("nested list start override missing")
+	}
+}
+
+func TestHeadingOutlineLevels(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.TOC.MaxLevel = 2
+	styles := GenerateStyles(cfg)
+	for level, want := range map[int]string{1: "0", 2: "1", 3: "9", 9: "9"} {
+		start := strings.Index(styles, fmt.Sprintf(`w:styleId="Heading%d"`, level))
+		end := start + strings.Index(styles[start:], "</w:style>")
+		if !strings.Contains(styles[start:end], `<w:outlineLvl w:val="`+want+`"/>`) {
+			t.Errorf("Heading%d outline level should be %s", level, want)
+		}
+	}
+}
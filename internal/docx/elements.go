@@ -0,0 +1,198 @@
+package docx
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Elements 返回已加入文档主体的元素（按文档顺序）
+// 流式模式下元素写出后即被丢弃，返回 nil
+func (d *Document) Elements() []Element {
+	if d.stream != nil {
+		return nil
+	}
+	return append([]Element(nil), d.elements...)
+}
+
+// Table 返回表格元素包装的表格
+func (te *TableElement) Table() *Table {
+	return te.table
+}
+
+// Section 返回分节符所结束的节
+func (b *SectionBreak) Section() *Section {
+	return b.section
+}
+
+// 元素 JSON 中的类型标记
+const (
+	jsonTypeParagraph     = "paragraph"
+	jsonTypeTable         = "table"
+	jsonTypeRawXML        = "rawXML"
+	jsonTypeSectionBreak  = "sectionBreak"
+	jsonTypeRun           = "run"
+	jsonTypeHyperlink     = "hyperlink"
+	jsonTypeCommentStart  = "commentStart"
+	jsonTypeCommentEnd    = "commentEnd"
+	jsonTypeRevision      = "revision"
+	jsonTypeBookmarkStart = "bookmarkStart"
+	jsonTypeBookmarkEnd   = "bookmarkEnd"
+	jsonTypeField         = "field"
+)
+
+// jsonNode 带类型标记的元素，用于序列化接口类型的元素与段落子元素
+type jsonNode struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// MarshalElements 把元素序列化为 JSON，保留段落、表格、分节符等结构信息，
+// 可用于检查或对比转换结果
+func MarshalElements(elems []Element) ([]byte, error) {
+	nodes := make([]jsonNode, 0, len(elems))
+	for _, elem := range elems {
+		var typ string
+		var value any
+		switch e := elem.(type) {
+		case *Paragraph:
+			typ, value = jsonTypeParagraph, e
+		case *TableElement:
+			typ, value = jsonTypeTable, e.table
+		case *RawXML:
+			typ, value = jsonTypeRawXML, e
+		case *SectionBreak:
+			typ, value = jsonTypeSectionBreak, e.section
+		default:
+			return nil, fmt.Errorf("不支持序列化的元素类型: %T", elem)
+		}
+		node, err := newJSONNode(typ, value)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return json.Marshal(nodes)
+}
+
+// UnmarshalElements 从 MarshalElements 的输出还原元素，
+// 分节符绑定到 d，还原的元素可再经 AddParagraph 加入文档
+func (d *Document) UnmarshalElements(data []byte) ([]Element, error) {
+	var nodes []jsonNode
+	if err := json.Unmarshal(data, &nodes); err != nil {
+		return nil, err
+	}
+	elems := make([]Element, 0, len(nodes))
+	for _, node := range nodes {
+		var elem Element
+		var target any
+		switch node.Type {
+		case jsonTypeParagraph:
+			p := &Paragraph{}
+			elem, target = p, p
+		case jsonTypeTable:
+			t := &Table{}
+			elem, target = NewTableElement(t), t
+		case jsonTypeRawXML:
+			r := &RawXML{}
+			elem, target = r, r
+		case jsonTypeSectionBreak:
+			sec := &Section{}
+			elem, target = &SectionBreak{doc: d, section: sec}, sec
+		default:
+			return nil, fmt.Errorf("未知的元素类型: %q", node.Type)
+		}
+		if err := json.Unmarshal(node.Value, target); err != nil {
+			return nil, fmt.Errorf("解析 %s 元素失败: %w", node.Type, err)
+		}
+		elems = append(elems, elem)
+	}
+	return elems, nil
+}
+
+func newJSONNode(typ string, value any) (jsonNode, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return jsonNode{}, err
+	}
+	return jsonNode{Type: typ, Value: raw}, nil
+}
+
+// paragraphJSON 段落的 JSON 形式，Children 以带类型标记的节点表示
+type paragraphJSON struct {
+	*paragraphFields
+	Children []jsonNode
+}
+
+// paragraphFields 去掉方法集的 Paragraph，避免 MarshalJSON 递归
+type paragraphFields Paragraph
+
+// MarshalJSON 序列化段落，子元素带类型标记以便还原
+func (p *Paragraph) MarshalJSON() ([]byte, error) {
+	children := make([]jsonNode, 0, len(p.Children))
+	for _, child := range p.Children {
+		var typ string
+		switch child.(type) {
+		case *Run:
+			typ = jsonTypeRun
+		case *Hyperlink:
+			typ = jsonTypeHyperlink
+		case *CommentRangeStart:
+			typ = jsonTypeCommentStart
+		case *CommentRangeEnd:
+			typ = jsonTypeCommentEnd
+		case *Revision:
+			typ = jsonTypeRevision
+		case *BookmarkStart:
+			typ = jsonTypeBookmarkStart
+		case *BookmarkEnd:
+			typ = jsonTypeBookmarkEnd
+		case *Field:
+			typ = jsonTypeField
+		default:
+			return nil, fmt.Errorf("不支持序列化的段落子元素类型: %T", child)
+		}
+		node, err := newJSONNode(typ, child)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, node)
+	}
+	return json.Marshal(paragraphJSON{(*paragraphFields)(p), children})
+}
+
+// UnmarshalJSON 还原 MarshalJSON 输出的段落
+func (p *Paragraph) UnmarshalJSON(data []byte) error {
+	aux := paragraphJSON{paragraphFields: (*paragraphFields)(p)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	p.Children = make([]ParagraphChild, 0, len(aux.Children))
+	for _, node := range aux.Children {
+		var child ParagraphChild
+		switch node.Type {
+		case jsonTypeRun:
+			child = &Run{}
+		case jsonTypeHyperlink:
+			child = &Hyperlink{}
+		case jsonTypeCommentStart:
+			child = &CommentRangeStart{}
+		case jsonTypeCommentEnd:
+			child = &CommentRangeEnd{}
+		case jsonTypeRevision:
+			child = &Revision{}
+		case jsonTypeBookmarkStart:
+			child = &BookmarkStart{}
+		case jsonTypeBookmarkEnd:
+			child = &BookmarkEnd{}
+		case jsonTypeField:
+			child = &Field{}
+		default:
+			return fmt.Errorf("未知的段落子元素类型: %q", node.Type)
+		}
+		if err := json.Unmarshal(node.Value, child); err != nil {
+			return err
+		}
+		p.Children = append(p.Children, child)
+	}
+	return nil
+}
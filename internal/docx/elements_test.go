@@ -0,0 +1,61 @@
+package docx
+
+import (
+	"bytes"
+	"testing"
+
+	"md2word/internal/config"
+)
+
+func TestMarshalElementsRoundTrip(t *testing.T) {
+	doc := NewDocument(config.DefaultConfig())
+
+	p := NewParagraph("Heading1")
+	p.Align = "center"
+	p.AddFormattedRun("标题", true, false, false)
+	link := p.AddHyperlink(doc.AddHyperlink("https://example.com"))
+	link.AddRun("链接")
+	doc.AddParagraph(p)
+
+	table := NewTable()
+	table.ColWidths = []int{1000, 2000}
+	row := table.AddRow(true)
+	row.AddCell().SetText("a", true)
+	row.AddCell().SetText("b", false)
+	doc.AddParagraph(NewTableElement(table))
+
+	doc.AddSectionBreak(&Section{Landscape: true})
+
+	raw, err := NewRawXML(`<w:p/>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.AddParagraph(raw)
+
+	elems := doc.Elements()
+	data, err := MarshalElements(elems)
+	if err != nil {
+		t.Fatalf("MarshalElements: %v", err)
+	}
+	got, err := doc.UnmarshalElements(data)
+	if err != nil {
+		t.Fatalf("UnmarshalElements: %v", err)
+	}
+	if len(got) != len(elems) {
+		t.Fatalf("got %d elements, want %d", len(got), len(elems))
+	}
+	for i := range elems {
+		want, have := elems[i].ToXML(), got[i].ToXML()
+		if want != have {
+			t.Errorf("element %d (%T) XML differs after round trip:\nwant %s\ngot  %s", i, elems[i], want, have)
+		}
+	}
+
+	again, err := MarshalElements(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, again) {
+		t.Errorf("JSON differs after round trip:\nwant %s\ngot  %s", data, again)
+	}
+}
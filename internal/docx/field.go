@@ -0,0 +1,58 @@
+package docx
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Field 复杂域（段落子元素），如 XE 索引项、INDEX 索引
+type Field struct {
+	Instr  string // 域代码，如 `XE "术语"`
+	Result string // 更新前显示的域结果，为空时不写结果部分（如 XE 域）
+}
+
+// AddField 在段落末尾添加一个域
+func (p *Paragraph) AddField(instr, result string) *Field {
+	f := &Field{Instr: instr, Result: result}
+	p.Children = append(p.Children, f)
+	return f
+}
+
+// ToXML 转换为XML
+func (f *Field) ToXML() string {
+	var buf bytes.Buffer
+	f.WriteXML(&buf)
+	return buf.String()
+}
+
+// WriteXML 把域XML写入 buf
+func (f *Field) WriteXML(buf *bytes.Buffer) {
+	buf.WriteString(`
+            <w:r>
+                <w:fldChar w:fldCharType="begin"/>
+            </w:r>
+            <w:r>`)
+	writeTextTag(buf, "w:instrText", " "+f.Instr+" ")
+	buf.WriteString(`
+            </w:r>`)
+	if f.Result != "" {
+		buf.WriteString(`
+            <w:r>
+                <w:fldChar w:fldCharType="separate"/>
+            </w:r>
+            <w:r>`)
+		writeTextTag(buf, "w:t", f.Result)
+		buf.WriteString(`
+            </w:r>`)
+	}
+	buf.WriteString(`
+            <w:r>
+                <w:fldChar w:fldCharType="end"/>
+            </w:r>`)
+}
+
+// FieldQuote 把文字转为域代码中的带引号参数，转义其中的引号与反斜杠
+func FieldQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
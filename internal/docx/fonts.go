@@ -0,0 +1,246 @@
+package docx
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"md2word/internal/config"
+)
+
+// FontInfo 字体元数据，供 Word 在缺少字体时选择相近的替代字体
+type FontInfo struct {
+	AltName string // 备用名称（通常为英文名）
+	Panose  string // PANOSE-1 分类码
+	Charset string // 字符集: 86=GB2312, 00=ANSI
+	Family  string // 字体族: roman, swiss, modern, script, decorative, auto
+	Pitch   string // 字距: fixed, variable
+}
+
+// knownFonts 常用字体的元数据
+var knownFonts = map[string]FontInfo{
+	"宋体":              {AltName: "SimSun", Panose: "02010600030101010101", Charset: "86", Family: "auto", Pitch: "variable"},
+	"SimSun":          {AltName: "宋体", Panose: "02010600030101010101", Charset: "86", Family: "auto", Pitch: "variable"},
+	"黑体":              {AltName: "SimHei", Panose: "02010609060101010101", Charset: "86", Family: "modern", Pitch: "fixed"},
+	"SimHei":          {AltName: "黑体", Panose: "02010609060101010101", Charset: "86", Family: "modern", Pitch: "fixed"},
+	"微软雅黑":            {AltName: "Microsoft YaHei", Panose: "020B0503020204020204", Charset: "86", Family: "swiss", Pitch: "variable"},
+	"Microsoft YaHei": {AltName: "微软雅黑", Panose: "020B0503020204020204", Charset: "86", Family: "swiss", Pitch: "variable"},
+	"楷体":              {AltName: "KaiTi", Panose: "02010609060101010101", Charset: "86", Family: "modern", Pitch: "fixed"},
+	"仿宋":              {AltName: "FangSong", Panose: "02010609060101010101", Charset: "86", Family: "modern", Pitch: "fixed"},
+	"Consolas":        {Panose: "020B0609020204030204", Charset: "00", Family: "modern", Pitch: "fixed"},
+	"Courier New":     {Panose: "02070309020205020404", Charset: "00", Family: "modern", Pitch: "fixed"},
+	"Times New Roman": {Panose: "02020603050405020304", Charset: "00", Family: "roman", Pitch: "variable"},
+	"Arial":           {Panose: "020B0604020202020204", Charset: "00", Family: "swiss", Pitch: "variable"},
+	"Calibri":         {Panose: "020F0502020204030204", Charset: "00", Family: "swiss", Pitch: "variable"},
+}
+
+// UsedFont 文档中使用的字体及其回退链
+type UsedFont struct {
+	Name     string
+	Fallback []string
+}
+
+// UsedFonts 收集配置中使用的字体（按首次出现顺序去重）
+func UsedFonts(cfg *config.Config) []UsedFont {
+	var fonts []UsedFont
+	index := make(map[string]int)
+	add := func(name string, fallback []string) {
+		if name == "" {
+			return
+		}
+		if i, ok := index[name]; ok {
+			if len(fonts[i].Fallback) == 0 {
+				fonts[i].Fallback = fallback
+			}
+			return
+		}
+		index[name] = len(fonts)
+		fonts = append(fonts, UsedFont{Name: name, Fallback: fallback})
+	}
+
+	add(cfg.Styles.Body.Font, cfg.Styles.Body.FontFallback)
+	for level := 1; level <= 9; level++ {
+		style := cfg.GetHeadingStyle(level)
+		add(style.Font, style.FontFallback)
+	}
+	add(cfg.Styles.Code.Font, cfg.Styles.Code.FontFallback)
+	add(cfg.Styles.CodeBlock.Font, cfg.Styles.CodeBlock.FontFallback)
+	add(cfg.Table.Font, nil)
+	add("Consolas", nil) // 行内代码固定使用
+
+	// 回退字体本身也需要声明
+	for i := 0; i < len(fonts); i++ {
+		for _, fb := range fonts[i].Fallback {
+			add(fb, nil)
+		}
+	}
+	return fonts
+}
+
+// GenerateFontTable 生成 fontTable.xml 内容
+// 每个字体声明 PANOSE/字符集/字体族；配置了回退链时，第一个回退字体作为 altName，
+// 未知字体的分类信息取自回退链中第一个已知字体，使 Word 的替换结果可控。
+// embeds 中的字体会输出 embedRegular 等引用；未在样式中使用的嵌入字体也会被声明。
+func GenerateFontTable(cfg *config.Config, embeds map[string][]*FontEmbed, embedOrder []string) string {
+	var buf bytes.Buffer
+
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:fonts xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+         xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`)
+
+	fonts := UsedFonts(cfg)
+	declared := make(map[string]bool)
+	for _, font := range fonts {
+		declared[font.Name] = true
+	}
+	for _, name := range embedOrder {
+		if !declared[name] {
+			fonts = append(fonts, UsedFont{Name: name})
+		}
+	}
+
+	for _, font := range fonts {
+		info, known := knownFonts[font.Name]
+		if len(font.Fallback) > 0 {
+			info.AltName = font.Fallback[0]
+		}
+		if !known {
+			for _, fb := range font.Fallback {
+				if fbInfo, ok := knownFonts[fb]; ok {
+					info.Panose, info.Charset, info.Family, info.Pitch = fbInfo.Panose, fbInfo.Charset, fbInfo.Family, fbInfo.Pitch
+					break
+				}
+			}
+		}
+
+		buf.WriteString(`
+    <w:font w:name="` + XMLEscape(font.Name) + `">`)
+		if info.AltName != "" {
+			buf.WriteString(`
+        <w:altName w:val="` + XMLEscape(info.AltName) + `"/>`)
+		}
+		if info.Panose != "" {
+			buf.WriteString(`
+        <w:panose1 w:val="` + info.Panose + `"/>`)
+		}
+		if info.Charset != "" {
+			buf.WriteString(`
+        <w:charset w:val="` + info.Charset + `"/>`)
+		}
+		family := info.Family
+		if family == "" {
+			family = "auto"
+		}
+		buf.WriteString(`
+        <w:family w:val="` + family + `"/>`)
+		if info.Pitch != "" {
+			buf.WriteString(`
+        <w:pitch w:val="` + info.Pitch + `"/>`)
+		}
+		for _, embed := range embeds[font.Name] {
+			buf.WriteString(`
+        <w:embed` + embed.Kind + ` r:id="` + embed.RelID + `" w:fontKey="` + embed.Key + `"/>`)
+		}
+		buf.WriteString(`
+    </w:font>`)
+	}
+
+	buf.WriteString(`
+</w:fonts>`)
+
+	return buf.String()
+}
+
+// fontEmbedKinds 嵌入字体的字形变体，顺序与 fontTable 中 embed* 元素顺序一致
+var fontEmbedKinds = []string{"Regular", "Bold", "Italic", "BoldItalic"}
+
+// FontEmbed 已嵌入的字体文件
+type FontEmbed struct {
+	Kind     string // Regular, Bold, Italic, BoldItalic
+	RelID    string // fontTable.xml.rels 中的关系ID
+	Key      string // 混淆密钥 GUID, 形如 {XXXXXXXX-XXXX-XXXX-XXXX-XXXXXXXXXXXX}
+	PartName string // zip 内文件名, 如 font1.odttf
+	Data     []byte // 已混淆的字体数据
+}
+
+// newFontKey 生成随机的混淆密钥 GUID
+func newFontKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	h := strings.ToUpper(hex.EncodeToString(b))
+	return fmt.Sprintf("{%s-%s-%s-%s-%s}", h[0:8], h[8:12], h[12:16], h[16:20], h[20:32]), nil
+}
+
+// ObfuscateFont 按 OOXML 嵌入字体规则混淆字体数据：
+// 将 GUID 去掉括号和连字符后的 16 字节逆序作为密钥，与字体前 32 字节逐字节异或。
+func ObfuscateFont(data []byte, key string) ([]byte, error) {
+	digits := strings.NewReplacer("{", "", "}", "", "-", "").Replace(key)
+	raw, err := hex.DecodeString(digits)
+	if err != nil || len(raw) != 16 {
+		return nil, fmt.Errorf("无效的字体密钥: %s", key)
+	}
+	if len(data) < 32 {
+		return nil, fmt.Errorf("字体数据过短")
+	}
+
+	out := make([]byte, len(data))
+	copy(out, data)
+	for i := 0; i < 32; i++ {
+		out[i] ^= raw[15-i%16]
+	}
+	return out, nil
+}
+
+// loadEmbeddedFonts 读取并混淆配置中要嵌入的字体文件，返回字体名到嵌入文件的映射
+func loadEmbeddedFonts(cfg *config.Config) (map[string][]*FontEmbed, []string, error) {
+	embeds := make(map[string][]*FontEmbed)
+	var order []string
+	if !cfg.Fonts.Embed {
+		return embeds, order, nil
+	}
+
+	count := 0
+	for _, file := range cfg.Fonts.Files {
+		paths := map[string]string{
+			"Regular":    file.Regular,
+			"Bold":       file.Bold,
+			"Italic":     file.Italic,
+			"BoldItalic": file.BoldItalic,
+		}
+		for _, kind := range fontEmbedKinds {
+			path := paths[kind]
+			if path == "" {
+				continue
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, nil, fmt.Errorf("读取嵌入字体失败: %w", err)
+			}
+			key, err := newFontKey()
+			if err != nil {
+				return nil, nil, err
+			}
+			obfuscated, err := ObfuscateFont(data, key)
+			if err != nil {
+				return nil, nil, fmt.Errorf("嵌入字体 %s: %w", path, err)
+			}
+			count++
+			if _, ok := embeds[file.Font]; !ok {
+				order = append(order, file.Font)
+			}
+			embeds[file.Font] = append(embeds[file.Font], &FontEmbed{
+				Kind:     kind,
+				RelID:    fmt.Sprintf("rId%d", count),
+				Key:      key,
+				PartName: fmt.Sprintf("font%d.odttf", count),
+				Data:     obfuscated,
+			})
+		}
+	}
+	return embeds, order, nil
+}
@@ -0,0 +1,60 @@
+package docx
+
+import (
+	"bytes"
+	"io"
+)
+
+// headerFooterPart 页眉或页脚部件
+type headerFooterPart struct {
+	relType string
+	name    string // word/ 下的部件名，如 header1.xml
+	xml     string
+}
+
+// contentType 部件的内容类型
+func (p headerFooterPart) contentType() string {
+	if p.relType == relTypeHeader {
+		return "application/vnd.openxmlformats-officedocument.wordprocessingml.header+xml"
+	}
+	return "application/vnd.openxmlformats-officedocument.wordprocessingml.footer+xml"
+}
+
+// addHeaderFooter 登记一个页眉/页脚部件并返回其关系ID
+func (d *Document) addHeaderFooter(relType, name, content string) string {
+	d.headerFooters = append(d.headerFooters, headerFooterPart{relType: relType, name: name, xml: content})
+	return d.addRelationship(relType, name, "")
+}
+
+// writeHeaderFooters 写入全部页眉页脚部件
+func (d *Document) writeHeaderFooters(w partCreator) error {
+	for _, part := range d.headerFooters {
+		f, err := w.Create("word/" + part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.xml); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GenerateHeader 生成居中显示 text 的页眉
+func GenerateHeader(text string) string {
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:hdr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+       xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+    <w:p>
+        <w:pPr>
+            <w:jc w:val="center"/>
+        </w:pPr>
+        <w:r>`)
+	writeTextElement(&buf, text)
+	buf.WriteString(`
+        </w:r>
+    </w:p>
+</w:hdr>`)
+	return buf.String()
+}
@@ -0,0 +1,118 @@
+package docx
+
+import (
+	"archive/zip"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"md2word/internal/config"
+)
+
+// readZipParts 读出 docx 中的全部部件
+func readZipParts(t *testing.T, path string) map[string]string {
+	t.Helper()
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	parts := make(map[string]string)
+	for _, f := range zr.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts[f.Name] = string(data)
+	}
+	return parts
+}
+
+func TestDifferentFirstPageHeader(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Page.PageNumber.Enabled = true
+	cfg.Page.Header.Text = "年度报告"
+	cfg.Page.Header.DifferentFirst = true
+	cfg.Page.Header.FirstPage = "封面"
+
+	doc := NewDocument(cfg)
+	doc.AddParagraph(NewParagraph(""))
+	doc.AddSectionBreak(&Section{})
+	doc.AddParagraph(NewParagraph(""))
+	path := filepath.Join(t.TempDir(), "out.docx")
+	if err := doc.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	parts := readZipParts(t, path)
+	if !strings.Contains(parts["word/header1.xml"], "年度报告") || !strings.Contains(parts["word/header2.xml"], "封面") {
+		t.Error("header parts missing text")
+	}
+	body := parts["word/document.xml"]
+	if n := strings.Count(body, "<w:titlePg/>"); n != 1 {
+		t.Errorf("titlePg appears %d times, want 1 (first section only)", n)
+	}
+	if n := strings.Count(body, `<w:headerReference w:type="first"`); n != 1 {
+		t.Errorf("first header referenced %d times, want 1", n)
+	}
+	if n := strings.Count(body, `<w:headerReference w:type="default"`); n != 2 {
+		t.Errorf("default header referenced %d times, want 2", n)
+	}
+	if first := strings.Index(body, "<w:titlePg/>"); first > strings.Index(body, "</w:sectPr>") {
+		t.Error("titlePg is not in the first section")
+	}
+}
+
+func TestBlankFirstPageHeader(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Page.Header.Text = "年度报告"
+	cfg.Page.Header.DifferentFirst = true
+
+	doc := NewDocument(cfg)
+	doc.AddParagraph(NewParagraph(""))
+	path := filepath.Join(t.TempDir(), "out.docx")
+	if err := doc.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	parts := readZipParts(t, path)
+	if _, ok := parts["word/header2.xml"]; ok {
+		t.Error("blank first-page header should not need a part")
+	}
+	if !strings.Contains(parts["word/document.xml"], "<w:titlePg/>") {
+		t.Error("titlePg missing")
+	}
+}
+
+func TestEvenAndOddHeaders(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Page.PageNumber.Enabled = true
+	cfg.Page.Header.Text = "第一章"
+	cfg.Page.Header.EvenAndOdd = true
+	cfg.Page.Header.EvenPage = "年度报告"
+
+	doc := NewDocument(cfg)
+	doc.AddParagraph(NewParagraph(""))
+	path := filepath.Join(t.TempDir(), "out.docx")
+	if err := doc.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	parts := readZipParts(t, path)
+	if !strings.Contains(parts["word/settings.xml"], "<w:evenAndOddHeaders/>") {
+		t.Error("settings missing evenAndOddHeaders")
+	}
+	if !strings.Contains(parts["word/header3.xml"], "年度报告") {
+		t.Error("even header part missing")
+	}
+	body := parts["word/document.xml"]
+	for _, ref := range []string{`<w:headerReference w:type="even"`, `<w:footerReference w:type="even"`, `<w:footerReference w:type="default"`} {
+		if !strings.Contains(body, ref) {
+			t.Errorf("sectPr missing %s", ref)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package docx
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// 列表编号格式 (numFmt)
+const (
+	ListFormatDecimal     = "decimal"
+	ListFormatLowerLetter = "lowerLetter"
+	ListFormatUpperLetter = "upperLetter"
+	ListFormatLowerRoman  = "lowerRoman"
+	ListFormatUpperRoman  = "upperRoman"
+	ListFormatBullet      = "bullet"
+)
+
+// ListIndentStep 列表每级的缩进 (twips)，编号悬挂在文字左侧
+const ListIndentStep = 420
+
+// ListLevel 列表一级的编号格式
+type ListLevel struct {
+	Format string // numFmt，见 ListFormat* 常量
+	Text   string // lvlText：有序列表如 "%1."，项目符号为符号字符
+	Bold   bool   // 编号加粗
+}
+
+// listAbstract 一种列表格式对应的抽象编号
+type listAbstract struct {
+	id     int
+	levels []ListLevel
+}
+
+// ListNum 列表编号实例：每个列表一个，在第 Level 级从 Start 开始编号
+type ListNum struct {
+	NumID    int
+	Level    int
+	Start    int
+	abstract *listAbstract
+}
+
+// AddListNumbering 为一个列表登记编号实例并返回 numId：
+// levels 为 0~8 各级的格式，格式相同的列表共用一个抽象编号；
+// 列表位于第 level 级，从 start 开始编号。每个列表使用独立的实例，Word 中各列表分别重新编号
+func (ns *NumberingState) AddListNumbering(levels []ListLevel, level, start int) int {
+	key := fmt.Sprint(levels)
+	var abstract *listAbstract
+	for _, a := range ns.listAbstracts {
+		if fmt.Sprint(a.levels) == key {
+			abstract = a
+			break
+		}
+	}
+	if abstract == nil {
+		// 抽象编号 0 留给标题编号
+		abstract = &listAbstract{id: len(ns.listAbstracts) + 1, levels: levels}
+		ns.listAbstracts = append(ns.listAbstracts, abstract)
+	}
+	num := &ListNum{NumID: ns.nextNumId, Level: min(max(level, 0), 8), Start: start, abstract: abstract}
+	ns.lists = append(ns.lists, num)
+	ns.nextNumId++
+	return num.NumID
+}
+
+// AddListNumbering 为一个列表登记编号实例并返回 numId（见 NumberingState.AddListNumbering）
+func (d *Document) AddListNumbering(levels []ListLevel, level, start int) int {
+	return d.numberingState.AddListNumbering(levels, level, start)
+}
+
+// writeListAbstracts 写出 lists 用到的抽象编号，每个只写一次
+func writeListAbstracts(buf *bytes.Buffer, lists []*ListNum) {
+	written := make(map[*listAbstract]bool)
+	for _, num := range lists {
+		a := num.abstract
+		if written[a] {
+			continue
+		}
+		written[a] = true
+		fmt.Fprintf(buf, `
+    <w:abstractNum w:abstractNumId="%d">
+        <w:multiLevelType w:val="hybridMultilevel"/>`, a.id)
+		for ilvl, lvl := range a.levels[:min(len(a.levels), 9)] {
+			fmt.Fprintf(buf, `
+        <w:lvl w:ilvl="%d">
+            <w:start w:val="1"/>
+            <w:numFmt w:val="%s"/>
+            <w:lvlText w:val="%s"/>
+            <w:lvlJc w:val="left"/>
+            <w:pPr>
+                <w:ind w:left="%d" w:hanging="%d"/>
+            </w:pPr>`, ilvl, lvl.Format, XMLEscape(lvl.Text), ListIndentStep*(ilvl+1), ListIndentStep)
+			if lvl.Bold {
+				buf.WriteString(`
+            <w:rPr>
+                <w:b/>
+            </w:rPr>`)
+			}
+			buf.WriteString(`
+        </w:lvl>`)
+		}
+		buf.WriteString(`
+    </w:abstractNum>`)
+	}
+}
+
+// writeListNums 写出列表编号实例；起始值写为所在级别的 startOverride，
+// 使引用同一抽象编号的各个列表分别从头编号
+func writeListNums(buf *bytes.Buffer, lists []*ListNum) {
+	for _, num := range lists {
+		fmt.Fprintf(buf, `
+    <w:num w:numId="%d">
+        <w:abstractNumId w:val="%d"/>
+        <w:lvlOverride w:ilvl="%d">
+            <w:startOverride w:val="%d"/>
+        </w:lvlOverride>
+    </w:num>`, num.NumID, num.abstract.id, num.Level, num.Start)
+	}
+}
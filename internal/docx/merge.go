@@ -0,0 +1,623 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 合并时不复制的正文关系：这些部件在 base 中已有对应部件，
+// 编号与批注另行合并，样式、设置、字体表等沿用 base
+var mergeSharedRelTypes = map[string]bool{
+	relTypeStyles:                     true,
+	relTypeNumbering:                  true,
+	relTypeSettings:                   true,
+	relTypeFontTable:                  true,
+	relTypeComments:                   true,
+	relTypeBase + "stylesWithEffects": true,
+	relTypeBase + "webSettings":       true,
+	relTypeBase + "theme":             true,
+	relTypeBase + "footnotes":         true,
+	relTypeBase + "endnotes":          true,
+	relTypeBase + "customXml":         true,
+	relTypeBase + "glossaryDocument":  true,
+	"http://schemas.microsoft.com/office/2011/relationships/people":                true,
+	"http://schemas.microsoft.com/office/2011/relationships/commentsExtended":      true,
+	"http://schemas.microsoft.com/office/2016/09/relationships/commentsIds":        true,
+	"http://schemas.microsoft.com/office/2018/08/relationships/commentsExtensible": true,
+}
+
+const mergeDocumentPart = "word/document.xml"
+
+var (
+	mergeRelRefPattern     = regexp.MustCompile(`(\br:[A-Za-z]+=")([^"]*)(")`)
+	mergeAnnotationPattern = regexp.MustCompile(`(\bw:id=")(\d+)(")`)
+	mergeDocPrPattern      = regexp.MustCompile(`(<wp:docPr\b[^>]*?\bid=")(\d+)(")`)
+	mergeNumIDPattern      = regexp.MustCompile(`(<w:numId w:val=")(\d+)(")`)
+	mergeAbstractIDPattern = regexp.MustCompile(`(\bw:abstractNumId(?:="| w:val="))(\d+)(")`)
+	mergeNumDefPattern     = regexp.MustCompile(`(<w:num\b[^>]*?\bw:numId=")(\d+)(")`)
+	mergeAbstractNumBlock  = regexp.MustCompile(`(?s)<w:abstractNum\b.*?</w:abstractNum>`)
+	mergeNumBlock          = regexp.MustCompile(`(?s)<w:num\b[^>]*>.*?</w:num>`)
+	mergeCommentBlock      = regexp.MustCompile(`(?s)<w:comment\b.*?</w:comment>`)
+	mergeNamespacePattern  = regexp.MustCompile(`\bxmlns:([A-Za-z0-9]+)="[^"]*"`)
+)
+
+// mergePackage 读入内存的 DOCX 包
+type mergePackage struct {
+	names []string
+	parts map[string][]byte
+}
+
+// mergeRel 正文关系（含类型）
+type mergeRel struct {
+	ID         string `xml:"Id,attr"`
+	Type       string `xml:"Type,attr"`
+	Target     string `xml:"Target,attr"`
+	TargetMode string `xml:"TargetMode,attr"`
+}
+
+// mergeContentTypes 带内容类型的 [Content_Types].xml
+type mergeContentTypes struct {
+	Defaults []struct {
+		Extension   string `xml:"Extension,attr"`
+		ContentType string `xml:"ContentType,attr"`
+	} `xml:"Default"`
+	Overrides []struct {
+		PartName    string `xml:"PartName,attr"`
+		ContentType string `xml:"ContentType,attr"`
+	} `xml:"Override"`
+}
+
+// Merge 把 appendPath 文档的正文追加到 basePath 文档之后，写入 outputPath：
+//   - 追加的正文另起一节，保留两份文档各自的页面设置
+//   - 图片、页眉页脚等部件复制到新名称下，超链接重建关系，正文中的关系 ID 重新映射
+//   - 编号定义与批注合并，编号、批注/书签/修订 ID 顺延，避免与 base 冲突
+//   - 样式、设置、字体表沿用 base
+func Merge(basePath, appendPath, outputPath string) error {
+	base, err := readMergePackage(basePath)
+	if err != nil {
+		return err
+	}
+	extra, err := readMergePackage(appendPath)
+	if err != nil {
+		return err
+	}
+
+	m := &merger{base: base, extra: extra, copied: make(map[string]string)}
+	if err := m.merge(); err != nil {
+		return err
+	}
+	if errs := validateParts(base.sources()); len(errs) > 0 {
+		msgs := make([]string, len(errs))
+		for i, e := range errs {
+			msgs[i] = e.Error()
+		}
+		return fmt.Errorf("合并结果校验失败:\n  %s", strings.Join(msgs, "\n  "))
+	}
+	return base.save(outputPath)
+}
+
+// readMergePackage 读取 DOCX 的全部部件
+func readMergePackage(name string) (*mergePackage, error) {
+	zr, err := zip.OpenReader(name)
+	if err != nil {
+		return nil, fmt.Errorf("打开 %s 失败: %w", name, err)
+	}
+	defer zr.Close()
+
+	pkg := &mergePackage{parts: make(map[string][]byte)}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("读取 %s 中的 %s 失败: %w", name, f.Name, err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("读取 %s 中的 %s 失败: %w", name, f.Name, err)
+		}
+		pkg.set(f.Name, data)
+	}
+	if _, ok := pkg.parts[mergeDocumentPart]; !ok {
+		return nil, fmt.Errorf("%s 不是有效的 DOCX: 缺少 %s", name, mergeDocumentPart)
+	}
+	return pkg, nil
+}
+
+// set 新建或覆盖部件，新部件追加在末尾
+func (p *mergePackage) set(name string, data []byte) {
+	if _, ok := p.parts[name]; !ok {
+		p.names = append(p.names, name)
+	}
+	p.parts[name] = data
+}
+
+// sources 返回供校验读取的部件集合
+func (p *mergePackage) sources() map[string]partOpener {
+	m := make(map[string]partOpener, len(p.parts))
+	for name, data := range p.parts {
+		m[name] = func() (io.Reader, error) { return bytes.NewReader(data), nil }
+	}
+	return m
+}
+
+// save 按部件顺序写出 zip
+func (p *mergePackage) save(name string) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+	file, err := os.Create(name)
+	if err != nil {
+		return fmt.Errorf("创建文件失败: %w", err)
+	}
+	defer file.Close()
+
+	zw := zip.NewWriter(file)
+	for _, part := range p.names {
+		f, err := zw.Create(part)
+		if err != nil {
+			zw.Close()
+			return err
+		}
+		if _, err := f.Write(p.parts[part]); err != nil {
+			zw.Close()
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// rels 读取部件的关系，关系文件不存在时返回 nil
+func (p *mergePackage) rels(part string) ([]mergeRel, error) {
+	data, ok := p.parts[relsPartName(part)]
+	if !ok {
+		return nil, nil
+	}
+	var doc struct {
+		Rels []mergeRel `xml:"Relationship"`
+	}
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%s 解析失败: %w", relsPartName(part), err)
+	}
+	return doc.Rels, nil
+}
+
+// setRels 写回部件的关系文件
+func (p *mergePackage) setRels(part string, rels []mergeRel) {
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for _, rel := range rels {
+		buf.WriteString(`
+    <Relationship Id="` + rel.ID + `" Type="` + rel.Type + `" Target="` + XMLEscape(rel.Target) + `"`)
+		if rel.TargetMode != "" {
+			buf.WriteString(` TargetMode="` + rel.TargetMode + `"`)
+		}
+		buf.WriteString(`/>`)
+	}
+	buf.WriteString(`
+</Relationships>`)
+	p.set(relsPartName(part), buf.Bytes())
+}
+
+// contentTypes 解析 [Content_Types].xml
+func (p *mergePackage) contentTypes() (*mergeContentTypes, error) {
+	var ct mergeContentTypes
+	if err := xml.Unmarshal(p.parts["[Content_Types].xml"], &ct); err != nil {
+		return nil, fmt.Errorf("[Content_Types].xml 解析失败: %w", err)
+	}
+	return &ct, nil
+}
+
+// relsPartName 部件对应的关系文件名，如 word/document.xml -> word/_rels/document.xml.rels
+func relsPartName(part string) string {
+	dir, file := path.Split(part)
+	return dir + "_rels/" + file + ".rels"
+}
+
+// merger 一次合并的状态
+type merger struct {
+	base, extra *mergePackage
+	baseCT      *mergeContentTypes
+	extraCT     *mergeContentTypes
+	types       []string          // 需要追加到 base 内容类型中的声明
+	copied      map[string]string // extra 部件名 -> base 中的新部件名
+}
+
+func (m *merger) merge() error {
+	var err error
+	if m.baseCT, err = m.base.contentTypes(); err != nil {
+		return err
+	}
+	if m.extraCT, err = m.extra.contentTypes(); err != nil {
+		return err
+	}
+	baseRels, err := m.base.rels(mergeDocumentPart)
+	if err != nil {
+		return err
+	}
+	extraRels, err := m.extra.rels(mergeDocumentPart)
+	if err != nil {
+		return err
+	}
+
+	baseDoc := string(m.base.parts[mergeDocumentPart])
+	extraDoc := string(m.extra.parts[mergeDocumentPart])
+	baseBody, baseSect, baseEnd, err := splitMergeBody(baseDoc)
+	if err != nil {
+		return fmt.Errorf("base 文档: %w", err)
+	}
+	extraStart, extraEnd := bodyContentRange(extraDoc)
+	if extraStart < 0 {
+		return fmt.Errorf("追加文档: 找不到 <w:body>")
+	}
+	extraBody := extraDoc[extraStart:extraEnd]
+
+	// 正文关系：共用部件跳过，其余复制并分配新 ID
+	ids := make(map[string]string)
+	next := nextRelNumber(baseRels)
+	for _, rel := range extraRels {
+		if mergeSharedRelTypes[rel.Type] {
+			continue
+		}
+		target := rel.Target
+		if rel.TargetMode != "External" {
+			newPart, err := m.copyPart(resolveRelTarget(mergeDocumentPart, rel.Target))
+			if err != nil {
+				return err
+			}
+			target = relativeTarget(mergeDocumentPart, newPart)
+		}
+		id := "rId" + strconv.Itoa(next)
+		next++
+		ids[rel.ID] = id
+		baseRels = append(baseRels, mergeRel{ID: id, Type: rel.Type, Target: target, TargetMode: rel.TargetMode})
+	}
+	extraBody = mergeRelRefPattern.ReplaceAllStringFunc(extraBody, func(s string) string {
+		sub := mergeRelRefPattern.FindStringSubmatch(s)
+		if id, ok := ids[sub[2]]; ok {
+			return sub[1] + id + sub[3]
+		}
+		return s
+	})
+
+	// 批注/书签/修订 ID 与图片 docPr ID 顺延
+	annotationOffset := maxMatch(mergeAnnotationPattern, baseDoc) + 1
+	if data, ok := m.partByRel(m.base, baseRels, relTypeComments); ok {
+		annotationOffset = max(annotationOffset, maxMatch(mergeAnnotationPattern, string(data))+1)
+	}
+	extraBody = offsetMatches(mergeAnnotationPattern, extraBody, annotationOffset)
+	extraBody = offsetMatches(mergeDocPrPattern, extraBody, maxMatch(mergeDocPrPattern, baseDoc))
+
+	if extraBody, err = m.mergeNumbering(&baseRels, extraRels, extraBody); err != nil {
+		return err
+	}
+	if err := m.mergeComments(&baseRels, extraRels, annotationOffset); err != nil {
+		return err
+	}
+
+	// base 最后一节的属性移入分节段落，追加的正文沿用自己的最后一节属性
+	var doc strings.Builder
+	doc.WriteString(mergeNamespaces(baseBody, extraDoc))
+	if baseSect != "" {
+		doc.WriteString(`
+        <w:p>
+            <w:pPr>` + baseSect + `</w:pPr>
+        </w:p>`)
+	}
+	doc.WriteString(extraBody)
+	doc.WriteString(baseEnd)
+	m.base.set(mergeDocumentPart, []byte(doc.String()))
+	m.base.setRels(mergeDocumentPart, baseRels)
+
+	if len(m.types) > 0 {
+		ct := string(m.base.parts["[Content_Types].xml"])
+		ct, _ = insertBeforeClosing(ct, "</Types>", strings.Join(m.types, ""))
+		m.base.set("[Content_Types].xml", []byte(ct))
+	}
+	return nil
+}
+
+// splitMergeBody 把 document.xml 拆为：正文末尾的 sectPr 之前的全部内容、该 sectPr、之后的结尾部分
+func splitMergeBody(doc string) (head, sect, tail string, err error) {
+	start, end := bodyContentRange(doc)
+	if start < 0 {
+		return "", "", "", fmt.Errorf("找不到 <w:body>")
+	}
+	content := doc[start:end]
+	i := strings.LastIndex(content, "<w:sectPr")
+	if i < 0 {
+		return doc[:end], "", doc[end:], nil
+	}
+	j := strings.Index(content[i:], "</w:sectPr>")
+	if j < 0 || strings.TrimSpace(content[i+j+len("</w:sectPr>"):]) != "" {
+		// 最后一个 sectPr 不是正文的直接子元素（位于段落属性中）
+		return doc[:end], "", doc[end:], nil
+	}
+	return doc[:start+i], content[i : i+j+len("</w:sectPr>")], doc[end:], nil
+}
+
+// bodyContentRange 返回 <w:body> 内容的起止位置，找不到时 start 为 -1
+func bodyContentRange(doc string) (start, end int) {
+	open := strings.Index(doc, "<w:body>")
+	closing := strings.LastIndex(doc, "</w:body>")
+	if open < 0 || closing < open {
+		return -1, -1
+	}
+	return open + len("<w:body>"), closing
+}
+
+// mergeNamespaces 把追加文档根元素上 base 没有声明的命名空间前缀补到 base 的根元素上
+func mergeNamespaces(head, extraDoc string) string {
+	rootStart := strings.Index(head, "<w:document")
+	if rootStart < 0 {
+		return head
+	}
+	rootEnd := rootStart + strings.Index(head[rootStart:], ">")
+	extraRoot := extraDoc
+	if i := strings.Index(extraDoc, "<w:body>"); i >= 0 {
+		extraRoot = extraDoc[:i]
+	}
+	declared := make(map[string]bool)
+	for _, m := range mergeNamespacePattern.FindAllStringSubmatch(head[rootStart:rootEnd], -1) {
+		declared[m[1]] = true
+	}
+	var missing strings.Builder
+	for _, m := range mergeNamespacePattern.FindAllStringSubmatch(extraRoot, -1) {
+		if !declared[m[1]] {
+			declared[m[1]] = true
+			missing.WriteString(" " + m[0])
+		}
+	}
+	if missing.Len() == 0 {
+		return head
+	}
+	return head[:rootEnd] + missing.String() + head[rootEnd:]
+}
+
+// copyPart 把追加文档的部件（连同它自己的关系部件）复制到 base，返回新部件名
+func (m *merger) copyPart(name string) (string, error) {
+	if newName, ok := m.copied[name]; ok {
+		return newName, nil
+	}
+	data, ok := m.extra.parts[name]
+	if !ok {
+		return "", fmt.Errorf("追加文档缺少部件 %s", name)
+	}
+	newName := m.freePartName(name)
+	m.copied[name] = newName
+	m.base.set(newName, data)
+	m.declareContentType(name, newName)
+
+	rels, err := m.extra.rels(name)
+	if err != nil {
+		return "", err
+	}
+	if rels == nil {
+		return newName, nil
+	}
+	for i, rel := range rels {
+		if rel.TargetMode == "External" {
+			continue
+		}
+		target, err := m.copyPart(resolveRelTarget(name, rel.Target))
+		if err != nil {
+			return "", err
+		}
+		rels[i].Target = relativeTarget(newName, target)
+	}
+	m.base.setRels(newName, rels)
+	return newName, nil
+}
+
+// freePartName 返回 base 中未被占用的部件名：同名时在扩展名前加序号
+func (m *merger) freePartName(name string) string {
+	if _, ok := m.base.parts[name]; !ok {
+		return name
+	}
+	ext := path.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	for n := 2; ; n++ {
+		candidate := stem + "_" + strconv.Itoa(n) + ext
+		if _, ok := m.base.parts[candidate]; !ok {
+			return candidate
+		}
+	}
+}
+
+// declareContentType 为复制的部件补上内容类型：沿用追加文档中的 Override，
+// 或在 base 缺少该扩展名的 Default 时补上
+func (m *merger) declareContentType(oldName, newName string) {
+	for _, o := range m.extraCT.Overrides {
+		if strings.EqualFold(strings.TrimPrefix(o.PartName, "/"), oldName) {
+			m.types = append(m.types, `
+    <Override PartName="/`+newName+`" ContentType="`+o.ContentType+`"/>`)
+			return
+		}
+	}
+	ext := strings.TrimPrefix(path.Ext(newName), ".")
+	for _, d := range m.baseCT.Defaults {
+		if strings.EqualFold(d.Extension, ext) {
+			return
+		}
+	}
+	for _, d := range m.extraCT.Defaults {
+		if strings.EqualFold(d.Extension, ext) {
+			m.types = append(m.types, `
+    <Default Extension="`+d.Extension+`" ContentType="`+d.ContentType+`"/>`)
+			m.baseCT.Defaults = append(m.baseCT.Defaults, d)
+			return
+		}
+	}
+}
+
+// partByRel 返回正文关系中第一个 relType 类型的部件内容
+func (m *merger) partByRel(pkg *mergePackage, rels []mergeRel, relType string) ([]byte, bool) {
+	name, ok := relPart(rels, relType)
+	if !ok {
+		return nil, false
+	}
+	data, ok := pkg.parts[name]
+	return data, ok
+}
+
+// relPart 返回正文关系中第一个 relType 类型的部件名
+func relPart(rels []mergeRel, relType string) (string, bool) {
+	for _, rel := range rels {
+		if rel.Type == relType && rel.TargetMode != "External" {
+			return resolveRelTarget(mergeDocumentPart, rel.Target), true
+		}
+	}
+	return "", false
+}
+
+// mergeNumbering 把追加文档的编号定义并入 base，编号 ID 顺延，返回更新了 numId 的正文
+func (m *merger) mergeNumbering(baseRels *[]mergeRel, extraRels []mergeRel, body string) (string, error) {
+	extraName, ok := relPart(extraRels, relTypeNumbering)
+	if !ok {
+		return body, nil
+	}
+	extraXML := string(m.extra.parts[extraName])
+	baseName, ok := relPart(*baseRels, relTypeNumbering)
+	if !ok {
+		// base 没有编号部件：原样复制
+		newName, err := m.copyPart(extraName)
+		if err != nil {
+			return "", err
+		}
+		*baseRels = append(*baseRels, mergeRel{
+			ID:     "rId" + strconv.Itoa(nextRelNumber(*baseRels)),
+			Type:   relTypeNumbering,
+			Target: relativeTarget(mergeDocumentPart, newName),
+		})
+		return body, nil
+	}
+
+	baseXML := string(m.base.parts[baseName])
+	numOffset := maxMatch(mergeNumDefPattern, baseXML)
+	abstractOffset := maxMatch(mergeAbstractIDPattern, baseXML) + 1
+
+	var abstracts, nums strings.Builder
+	for _, block := range mergeAbstractNumBlock.FindAllString(extraXML, -1) {
+		abstracts.WriteString(offsetMatches(mergeAbstractIDPattern, block, abstractOffset) + "\n    ")
+	}
+	for _, block := range mergeNumBlock.FindAllString(extraXML, -1) {
+		block = offsetMatches(mergeNumDefPattern, block, numOffset)
+		nums.WriteString("\n    " + offsetMatches(mergeAbstractIDPattern, block, abstractOffset))
+	}
+
+	merged, ok := insertBeforeClosing(baseXML, "</w:numbering>", nums.String())
+	if !ok {
+		return "", fmt.Errorf("%s 格式不正确", baseName)
+	}
+	// abstractNum 必须位于全部 num 之前
+	if loc := mergeNumBlock.FindStringIndex(merged); loc != nil {
+		merged = merged[:loc[0]] + abstracts.String() + merged[loc[0]:]
+	} else {
+		merged, _ = insertBeforeClosing(merged, "</w:numbering>", "\n    "+strings.TrimSuffix(abstracts.String(), "\n    "))
+	}
+	m.base.set(baseName, []byte(merged))
+
+	// numId 0 表示取消编号，保持不变
+	return mergeNumIDPattern.ReplaceAllStringFunc(body, func(s string) string {
+		sub := mergeNumIDPattern.FindStringSubmatch(s)
+		if sub[2] == "0" {
+			return s
+		}
+		n, _ := strconv.Atoi(sub[2])
+		return sub[1] + strconv.Itoa(n+numOffset) + sub[3]
+	}), nil
+}
+
+// mergeComments 把追加文档的批注并入 base 的批注部件，ID 与正文一同顺延
+func (m *merger) mergeComments(baseRels *[]mergeRel, extraRels []mergeRel, offset int) error {
+	extraName, ok := relPart(extraRels, relTypeComments)
+	if !ok {
+		return nil
+	}
+	extraXML := offsetMatches(mergeAnnotationPattern, string(m.extra.parts[extraName]), offset)
+	baseName, ok := relPart(*baseRels, relTypeComments)
+	if !ok {
+		newName := m.freePartName("word/comments.xml")
+		m.base.set(newName, []byte(extraXML))
+		m.declareContentType(extraName, newName)
+		*baseRels = append(*baseRels, mergeRel{
+			ID:     "rId" + strconv.Itoa(nextRelNumber(*baseRels)),
+			Type:   relTypeComments,
+			Target: relativeTarget(mergeDocumentPart, newName),
+		})
+		return nil
+	}
+
+	var comments strings.Builder
+	for _, block := range mergeCommentBlock.FindAllString(extraXML, -1) {
+		comments.WriteString("\n    " + block)
+	}
+	merged, ok := insertBeforeClosing(string(m.base.parts[baseName]), "</w:comments>", comments.String())
+	if !ok {
+		return fmt.Errorf("%s 格式不正确", baseName)
+	}
+	m.base.set(baseName, []byte(merged))
+	return nil
+}
+
+// insertBeforeClosing 把 content 插入到最后一个 closing 标签之前（该标签前的换行缩进之前）
+func insertBeforeClosing(s, closing, content string) (string, bool) {
+	i := strings.LastIndex(s, closing)
+	if i < 0 {
+		return s, false
+	}
+	i = len(strings.TrimRight(s[:i], " \t\r\n"))
+	return s[:i] + content + s[i:], true
+}
+
+// nextRelNumber 返回不与现有 rIdN 冲突的下一个序号
+func nextRelNumber(rels []mergeRel) int {
+	n := 0
+	for _, rel := range rels {
+		if v, err := strconv.Atoi(strings.TrimPrefix(rel.ID, "rId")); err == nil && v > n {
+			n = v
+		}
+	}
+	return n + 1
+}
+
+// relativeTarget 返回从 source 部件指向 target 部件的关系目标
+func relativeTarget(source, target string) string {
+	dir := path.Dir(source) + "/"
+	if strings.HasPrefix(target, dir) {
+		return strings.TrimPrefix(target, dir)
+	}
+	return "/" + target
+}
+
+// maxMatch 返回 pattern 第二个分组中数字的最大值，没有匹配时为 0
+func maxMatch(pattern *regexp.Regexp, s string) int {
+	n := 0
+	for _, m := range pattern.FindAllStringSubmatch(s, -1) {
+		if v, err := strconv.Atoi(m[2]); err == nil && v > n {
+			n = v
+		}
+	}
+	return n
+}
+
+// offsetMatches 把 pattern 第二个分组中的数字加上 offset
+func offsetMatches(pattern *regexp.Regexp, s string, offset int) string {
+	if offset == 0 {
+		return s
+	}
+	return pattern.ReplaceAllStringFunc(s, func(match string) string {
+		sub := pattern.FindStringSubmatch(match)
+		n, _ := strconv.Atoi(sub[2])
+		return sub[1] + strconv.Itoa(n+offset) + sub[3]
+	})
+}
@@ -0,0 +1,89 @@
+package docx
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"md2word/internal/config"
+)
+
+// saveMergeInput 生成带图片、超链接、批注与编号段落的文档
+func saveMergeInput(t *testing.T, name, text string) string {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.Page.PageNumber.Enabled = true
+	doc := NewDocument(cfg)
+
+	p := NewParagraph("")
+	p.AddRun(text)
+	link := p.AddHyperlink(doc.AddHyperlink("https://example.com/" + name))
+	link.AddRun("链接")
+	p.AddComment(doc.AddComment("md2word", "批注"+text))
+	doc.AddParagraph(p)
+
+	img := NewParagraph("")
+	img.AddImageRun(doc.AddImage([]byte("\x89PNG\r\n\x1a\n"+name), "image/png", 10, 10), 9525, 9525)
+	doc.AddParagraph(img)
+
+	num := ParseHeadingNumber("2.1 编号" + text)
+	instance := doc.GetNumberingState().GetOrCreateNumberingInstance(num)
+	item := NewParagraph("")
+	item.NumberingXML = GetNumberingXMLForParagraph(0, instance.NumId)
+	item.AddRun(num.Text)
+	doc.AddParagraph(item)
+
+	out := filepath.Join(t.TempDir(), name+".docx")
+	if err := doc.Save(out); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	return out
+}
+
+func TestMerge(t *testing.T) {
+	base := saveMergeInput(t, "base", "第一份")
+	extra := saveMergeInput(t, "extra", "第二份")
+	out := filepath.Join(t.TempDir(), "merged.docx")
+	if err := Merge(base, extra, out); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+
+	parts := readZipParts(t, out)
+	doc := parts["word/document.xml"]
+	for _, want := range []string{"第一份", "第二份", "编号第二份"} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("document.xml missing %q", want)
+		}
+	}
+	if n := strings.Count(doc, "<w:sectPr>"); n != 2 {
+		t.Errorf("sectPr count = %d, want 2 (section break + final)", n)
+	}
+	if _, ok := parts["word/media/image1_2.png"]; !ok {
+		t.Error("appended image not copied under a new name")
+	}
+	rels := parts["word/_rels/document.xml.rels"]
+	for _, want := range []string{"https://example.com/base", "https://example.com/extra", `Target="media/image1_2.png"`} {
+		if !strings.Contains(rels, want) {
+			t.Errorf("document.xml.rels missing %s", want)
+		}
+	}
+	if n := strings.Count(parts["word/comments.xml"], "<w:comment "); n != 2 {
+		t.Errorf("comment count = %d, want 2", n)
+	}
+	if n := strings.Count(doc, `<w:commentRangeStart w:id="`); n != 2 || strings.Count(doc, `<w:commentRangeStart w:id="1"/>`) != 1 {
+		t.Errorf("comment ids not renumbered:\n%s", doc)
+	}
+	numbering := parts["word/numbering.xml"]
+	if strings.LastIndex(numbering, "<w:abstractNum ") > strings.Index(numbering, "<w:num ") {
+		t.Error("abstractNum after num in numbering.xml")
+	}
+	baseNums := strings.Count(readZipParts(t, base)["word/numbering.xml"], "<w:num ")
+	if n := strings.Count(numbering, "<w:num "); n != 2*baseNums {
+		t.Errorf("num count = %d, want %d", n, 2*baseNums)
+	}
+	lastNumID := fmt.Sprintf(`<w:numId w:val="%d"/>`, 2*baseNums)
+	if !strings.Contains(doc, lastNumID) {
+		t.Errorf("appended paragraph does not reference renumbered %s", lastNumID)
+	}
+}
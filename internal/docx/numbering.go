@@ -21,6 +21,8 @@ type NumberingState struct {
 	lastLevel     int                      // 上一个标题的级别
 	numInstances  map[string]*NumInstance // 编号实例映射
 	nextNumId     int                      // 下一个可用的编号ID
+	listAbstracts []*listAbstract          // 列表的抽象编号，按格式去重
+	lists         []*ListNum               // 列表编号实例，按登记顺序
 }
 
 // NumInstance 编号实例
@@ -83,15 +85,15 @@ func ParseHeadingNumber(text string) *HeadingNumber {
 }
 
 // GenerateNumberingXML 生成 numbering.xml 内容
-// 支持多级列表编号，每级可以有不同的起始值
-func GenerateNumberingXML(numInstances map[string]*NumInstance) string {
+// 支持多级列表编号，每级可以有不同的起始值；lists 为列表的编号实例（见 AddListNumbering）
+func GenerateNumberingXML(numInstances map[string]*NumInstance, lists []*ListNum) string {
 	var buf bytes.Buffer
 
 	buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
 <w:numbering xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">`)
 
 	// 如果没有编号实例，创建一个默认的
-	if len(numInstances) == 0 {
+	if len(numInstances) == 0 && len(lists) == 0 {
 		buf.WriteString(`
     <!-- 默认抽象编号定义 -->
     <w:abstractNum w:abstractNumId="0">
@@ -152,6 +154,8 @@ func GenerateNumberingXML(numInstances map[string]*NumInstance) string {
     </w:abstractNum>`)
 			}
 		}
+		// abstractNum 必须位于全部 num 之前
+		writeListAbstracts(&buf, lists)
 
 		// 生成编号实例
 		for _, instance := range numInstances {
@@ -174,6 +178,7 @@ func GenerateNumberingXML(numInstances map[string]*NumInstance) string {
 			buf.WriteString(`
     </w:num>`)
 		}
+		writeListNums(&buf, lists)
 	}
 
 	buf.WriteString(`
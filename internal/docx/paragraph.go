@@ -2,8 +2,11 @@ package docx
 
 import (
 	"bytes"
+	"encoding/xml"
 	"fmt"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // Paragraph 段落
@@ -24,16 +27,80 @@ type Paragraph struct {
 	StyleID  string
 	Children []ParagraphChild
 
-	Align           string // left, center, right, justify
-	Indent          int    // 缩进(twips)
-	SpacingB        int    // 段后间距
-	SpacingA        int    // 段前间距
-	Shading         string // 背景色
-	Border          bool   // 是否添加边框
-	HorizontalRule  bool   // 是否是分隔线
-	LineHeight      int    // 行高 (twips)
-	FirstLineIndent int    // 首行缩进 (twips)
-	NumberingXML    string // 编号属性XML
+	Align           string           // left, center, right, justify
+	Indent          int              // 缩进(twips)
+	SpacingB        int              // 段后间距
+	SpacingA        int              // 段前间距
+	Shading         string           // 背景色
+	Border          bool             // 是否添加边框（四边浅灰细线，见 BorderStyle）
+	BorderStyle     *ParagraphBorder // 自定义边框，设置时优先于 Border
+	HorizontalRule  bool             // 是否是分隔线
+	LineHeight      int              // 行高 (twips)
+	FirstLineIndent int              // 首行缩进 (twips)
+	NumberingXML    string           // 编号属性XML
+	KeepNext        bool             // 与下一段保持在同一页
+	KeepLines       bool             // 段落内不分页
+	DropCap         int              // 首字下沉的行数：>0 时段落作为下沉首字的图文框，由下一段文字环绕
+}
+
+// 段落边框线型
+const (
+	BorderSingle = "single"
+	BorderDouble = "double"
+	BorderDashed = "dashed"
+)
+
+// ParagraphBorder 段落边框：边框所在的边、颜色、线宽与线型
+type ParagraphBorder struct {
+	Top, Left, Bottom, Right bool
+	Color                    string // RRGGBB，为空时为 C0C0C0
+	Width                    int    // 线宽（1/8 磅），为 0 时为 4
+	Style                    string // single、double、dashed，为空时为 single
+}
+
+// defaultBorder Paragraph.Border 使用的边框：四边浅灰细线
+var defaultBorder = ParagraphBorder{Top: true, Left: true, Bottom: true, Right: true}
+
+// border 返回段落实际使用的边框，没有边框时为 nil
+func (p *Paragraph) border() *ParagraphBorder {
+	if p.BorderStyle != nil {
+		return p.BorderStyle
+	}
+	if p.Border {
+		return &defaultBorder
+	}
+	return nil
+}
+
+// writeXML 把边框写为 w:pBdr；上下边距文字 1 磅，左右 4 磅
+func (b *ParagraphBorder) writeXML(buf *bytes.Buffer) {
+	style, width, color := b.Style, b.Width, strings.TrimPrefix(b.Color, "#")
+	if style == "" {
+		style = BorderSingle
+	}
+	if width <= 0 {
+		width = 4
+	}
+	if color == "" {
+		color = "C0C0C0"
+	}
+	buf.WriteString(`
+                <w:pBdr>`)
+	for _, side := range []struct {
+		name  string
+		on    bool
+		space string
+	}{{"top", b.Top, "1"}, {"left", b.Left, "4"}, {"bottom", b.Bottom, "1"}, {"right", b.Right, "4"}} {
+		if !side.on {
+			continue
+		}
+		buf.WriteString(`
+                    <w:` + side.name + ` w:val="` + style + `" w:sz="`)
+		writeInt(buf, width)
+		buf.WriteString(`" w:space="` + side.space + `" w:color="` + color + `"/>`)
+	}
+	buf.WriteString(`
+                </w:pBdr>`)
 }
 
 // Run 文本运行
@@ -47,11 +114,19 @@ type Run struct {
 	FontSize    float64
 	Color       string
 	Highlight   string
-	IsCode      bool
+	Shading     string // 底色；行内代码为空时使用 CodeChar 样式的底色
+	IsCode      bool   // 行内代码：引用 CodeChar 字符样式，FontName、Shading 等直接格式只在与样式不同时设置
 	IsImage     bool
 	ImageRelID  string
 	ImageWidth  int64 // EMUs (English Metric Units)
 	ImageHeight int64
+	Deleted     bool   // 修订中被删除的文字，以 w:delText 输出（位于 Revision 内）
+	Position    int    // 相对基线的垂直偏移（半磅），正数上移、负数下移
+	SVGRelID    string // 图片的 SVG 版本，支持 SVG 的 Word 优先显示，ImageRelID 作为后备图
+	Float       string // 浮动图片靠左 (left) 或靠右 (right)，文字四周环绕；为空时为嵌入行内的图片
+	SmallCaps   bool   // 小型大写字母
+	AllCaps     bool   // 全部大写
+	Spacing     int    // 字符间距调整 (twips)，正数加宽、负数紧缩
 }
 
 // NewParagraph 创建新段落
@@ -95,8 +170,9 @@ func (p *Paragraph) AddImageRun(relID string, width, height int64) *Run {
 
 // Hyperlink 超链接
 type Hyperlink struct {
-	ID   string
-	Runs []*Run
+	ID     string
+	Anchor string // 文档内书签名，设置时链接到书签而非 ID 对应的外部地址
+	Runs   []*Run
 }
 
 // AddRun 添加文本运行
@@ -133,12 +209,25 @@ func (h *Hyperlink) AddImageRun(relID string, width, height int64) *Run {
 // ToXML 转换为XML
 func (h *Hyperlink) ToXML() string {
 	var buf bytes.Buffer
-	buf.WriteString(fmt.Sprintf(`<w:hyperlink r:id="%s">`, h.ID))
-	for _, run := range h.Runs {
-		buf.WriteString(run.ToXML())
+	h.WriteXML(&buf)
+	return buf.String()
+}
+
+// WriteXML 把超链接XML写入 buf
+func (h *Hyperlink) WriteXML(buf *bytes.Buffer) {
+	if h.Anchor != "" {
+		buf.WriteString(`<w:hyperlink w:anchor="`)
+		writeEscaped(buf, h.Anchor)
+		buf.WriteString(`" w:history="1">`)
+	} else {
+		buf.WriteString(`<w:hyperlink r:id="`)
+		buf.WriteString(h.ID)
+		buf.WriteString(`">`)
+	}
+	for _, run := range mergeRuns(h.Runs) {
+		run.WriteXML(buf)
 	}
 	buf.WriteString(`</w:hyperlink>`)
-	return buf.String()
 }
 
 // AddHyperlink 添加超链接
@@ -151,21 +240,66 @@ func (p *Paragraph) AddHyperlink(id string) *Hyperlink {
 	return link
 }
 
+// AddAnchorLink 添加链接到文档内书签的超链接
+func (p *Paragraph) AddAnchorLink(anchor string) *Hyperlink {
+	link := &Hyperlink{
+		Anchor: anchor,
+		Runs:   make([]*Run, 0),
+	}
+	p.Children = append(p.Children, link)
+	return link
+}
+
+// Runs 返回段落中的所有文本运行（包括超链接内的运行）
+func (p *Paragraph) Runs() []*Run {
+	var runs []*Run
+	for _, child := range p.Children {
+		switch c := child.(type) {
+		case *Run:
+			runs = append(runs, c)
+		case *Hyperlink:
+			runs = append(runs, c.Runs...)
+		case *Revision:
+			runs = append(runs, c.Runs...)
+		}
+	}
+	return runs
+}
+
 // ToXML 转换为XML
 func (p *Paragraph) ToXML() string {
 	var buf bytes.Buffer
+	p.WriteXML(&buf)
+	return buf.String()
+}
 
+// WriteXML 把段落XML写入 buf
+func (p *Paragraph) WriteXML(buf *bytes.Buffer) {
 	buf.WriteString(`
         <w:p>`)
 
 	// 段落属性
-	if p.StyleID != "" || p.Align != "" || p.Indent > 0 || p.SpacingB > 0 || p.SpacingA > 0 || p.Shading != "" || p.Border || p.HorizontalRule || p.LineHeight > 0 || p.FirstLineIndent > 0 || p.NumberingXML != "" {
+	if p.StyleID != "" || p.Align != "" || p.Indent > 0 || p.SpacingB > 0 || p.SpacingA > 0 || p.Shading != "" || p.border() != nil || p.HorizontalRule || p.LineHeight > 0 || p.FirstLineIndent > 0 || p.NumberingXML != "" || p.KeepNext || p.KeepLines || p.DropCap > 0 {
 		buf.WriteString(`
             <w:pPr>`)
 		if p.StyleID != "" {
 			buf.WriteString(`
                 <w:pStyle w:val="` + p.StyleID + `"/>`)
 		}
+		if p.KeepNext {
+			buf.WriteString(`
+                <w:keepNext/>`)
+		}
+		if p.KeepLines {
+			buf.WriteString(`
+                <w:keepLines/>`)
+		}
+		if p.DropCap > 0 {
+			buf.WriteString(`
+                <w:framePr w:dropCap="drop" w:lines="`)
+			writeInt(buf, p.DropCap)
+			buf.WriteString(`" w:wrap="around" w:vAnchor="text" w:hAnchor="text"/>`)
+		}
 		// 编号属性(必须在其他属性之前)
 		if p.NumberingXML != "" {
 			buf.WriteString(`
@@ -184,16 +318,26 @@ func (p *Paragraph) ToXML() string {
 		if p.Indent > 0 || p.FirstLineIndent != 0 {
 			if p.FirstLineIndent < 0 {
 				// 悬挂缩进:使用hanging属性(负值转正)
-				buf.WriteString(fmt.Sprintf(`
-                <w:ind w:left="%d" w:hanging="%d"/>`, p.Indent, -p.FirstLineIndent))
+				buf.WriteString(`
+                <w:ind w:left="`)
+				writeInt(buf, p.Indent)
+				buf.WriteString(`" w:hanging="`)
+				writeInt(buf, -p.FirstLineIndent)
+				buf.WriteString(`"/>`)
 			} else if p.FirstLineIndent > 0 {
 				// 首行缩进
-				buf.WriteString(fmt.Sprintf(`
-                <w:ind w:left="%d" w:firstLine="%d"/>`, p.Indent, p.FirstLineIndent))
+				buf.WriteString(`
+                <w:ind w:left="`)
+				writeInt(buf, p.Indent)
+				buf.WriteString(`" w:firstLine="`)
+				writeInt(buf, p.FirstLineIndent)
+				buf.WriteString(`"/>`)
 			} else {
 				// 仅左缩进
-				buf.WriteString(fmt.Sprintf(`
-                <w:ind w:left="%d"/>`, p.Indent))
+				buf.WriteString(`
+                <w:ind w:left="`)
+				writeInt(buf, p.Indent)
+				buf.WriteString(`"/>`)
 			}
 		}
 		if p.SpacingB > 0 || p.SpacingA > 0 || p.LineHeight > 0 {
@@ -201,8 +345,14 @@ func (p *Paragraph) ToXML() string {
 			if p.LineHeight > 0 {
 				line = p.LineHeight
 			}
-			buf.WriteString(fmt.Sprintf(`
-                <w:spacing w:before="%d" w:after="%d" w:line="%d" w:lineRule="auto"/>`, p.SpacingA, p.SpacingB, line))
+			buf.WriteString(`
+                <w:spacing w:before="`)
+			writeInt(buf, p.SpacingA)
+			buf.WriteString(`" w:after="`)
+			writeInt(buf, p.SpacingB)
+			buf.WriteString(`" w:line="`)
+			writeInt(buf, line)
+			buf.WriteString(`" w:lineRule="auto"/>`)
 		}
 		if p.Shading != "" {
 			shading := strings.TrimPrefix(p.Shading, "#")
@@ -215,52 +365,47 @@ func (p *Paragraph) ToXML() string {
                     <w:bottom w:val="single" w:sz="6" w:space="1" w:color="A0A0A0"/>
                 </w:pBdr>`)
 		}
-		if p.Border {
-			buf.WriteString(`
-                <w:pBdr>
-                    <w:top w:val="single" w:sz="4" w:space="1" w:color="C0C0C0"/>
-                    <w:left w:val="single" w:sz="4" w:space="4" w:color="C0C0C0"/>
-                    <w:bottom w:val="single" w:sz="4" w:space="1" w:color="C0C0C0"/>
-                    <w:right w:val="single" w:sz="4" w:space="4" w:color="C0C0C0"/>
-                </w:pBdr>`)
+		if border := p.border(); border != nil {
+			border.writeXML(buf)
 		}
 		buf.WriteString(`
             </w:pPr>`)
 	}
 
-	// 运行
-	for _, child := range p.Children {
-		buf.WriteString(child.ToXML())
+	// 运行：相邻且格式相同的运行合并输出
+	for _, child := range mergeChildRuns(p.Children) {
+		writeElementXML(buf, child)
 	}
 
 	buf.WriteString(`
         </w:p>`)
-
-	return buf.String()
 }
 
 // ToXML 运行转换为XML
 func (r *Run) ToXML() string {
 	var buf bytes.Buffer
+	r.WriteXML(&buf)
+	return buf.String()
+}
 
+// WriteXML 把运行XML写入 buf
+func (r *Run) WriteXML(buf *bytes.Buffer) {
 	buf.WriteString(`
             <w:r>`)
 
-	// 运行属性
-	if r.Bold || r.Italic || r.Underline || r.Strike || r.FontName != "" || r.FontSize > 0 || r.Color != "" || r.IsCode {
+	// 运行属性，子元素按 CT_RPr 规定的顺序写出
+	if r.Bold || r.Italic || r.Underline || r.Strike || r.FontName != "" || r.FontSize > 0 || r.Color != "" || r.Highlight != "" || r.Shading != "" || r.IsCode || r.Position != 0 || r.SmallCaps || r.AllCaps || r.Spacing != 0 {
 		buf.WriteString(`
                 <w:rPr>`)
 
+		if r.IsCode {
+			buf.WriteString(`
+                    <w:rStyle w:val="` + CodeCharStyleID + `"/>`)
+		}
 		if r.FontName != "" {
 			buf.WriteString(`
                     <w:rFonts w:ascii="` + r.FontName + `" w:eastAsia="` + r.FontName + `" w:hAnsi="` + r.FontName + `"/>`)
 		}
-		if r.FontSize > 0 {
-			sz := int(r.FontSize * 2)
-			buf.WriteString(fmt.Sprintf(`
-                    <w:sz w:val="%d"/>
-                    <w:szCs w:val="%d"/>`, sz, sz))
-		}
 		if r.Bold {
 			buf.WriteString(`
                     <w:b/>`)
@@ -269,9 +414,13 @@ func (r *Run) ToXML() string {
 			buf.WriteString(`
                     <w:i/>`)
 		}
-		if r.Underline {
+		if r.AllCaps {
 			buf.WriteString(`
-                    <w:u w:val="single"/>`)
+                    <w:caps/>`)
+		}
+		if r.SmallCaps {
+			buf.WriteString(`
+                    <w:smallCaps/>`)
 		}
 		if r.Strike {
 			buf.WriteString(`
@@ -282,10 +431,39 @@ func (r *Run) ToXML() string {
 			buf.WriteString(`
                     <w:color w:val="` + color + `"/>`)
 		}
-		if r.IsCode {
+		if r.Spacing != 0 {
+			buf.WriteString(`
+                    <w:spacing w:val="`)
+			writeInt(buf, r.Spacing)
+			buf.WriteString(`"/>`)
+		}
+		if r.Position != 0 {
+			buf.WriteString(`
+                    <w:position w:val="`)
+			writeInt(buf, r.Position)
+			buf.WriteString(`"/>`)
+		}
+		if r.FontSize > 0 {
+			sz := int(r.FontSize * 2)
+			buf.WriteString(`
+                    <w:sz w:val="`)
+			writeInt(buf, sz)
+			buf.WriteString(`"/>
+                    <w:szCs w:val="`)
+			writeInt(buf, sz)
+			buf.WriteString(`"/>`)
+		}
+		if r.Highlight != "" {
+			buf.WriteString(`
+                    <w:highlight w:val="` + r.Highlight + `"/>`)
+		}
+		if r.Underline {
 			buf.WriteString(`
-                    <w:rFonts w:ascii="Consolas" w:hAnsi="Consolas"/>
-                    <w:shd w:val="clear" w:color="auto" w:fill="E8E8E8"/>`)
+                    <w:u w:val="single"/>`)
+		}
+		if r.Shading != "" {
+			buf.WriteString(`
+                    <w:shd w:val="clear" w:color="auto" w:fill="` + strings.TrimPrefix(r.Shading, "#") + `"/>`)
 		}
 
 		buf.WriteString(`
@@ -294,11 +472,33 @@ func (r *Run) ToXML() string {
 
 	// 内容
 	if r.IsImage {
-		buf.WriteString(fmt.Sprintf(`
+		drawing := "wp:inline"
+		if r.Float != "" {
+			// 浮动图片：水平方向靠栏左/右对齐，垂直方向与所在段落顶端对齐，四周留 0.125 英寸
+			drawing = "wp:anchor"
+			buf.WriteString(`
+                <w:drawing>
+                    <wp:anchor distT="0" distB="0" distL="114300" distR="114300" simplePos="0" relativeHeight="251658240" behindDoc="0" locked="0" layoutInCell="1" allowOverlap="1">
+                        <wp:simplePos x="0" y="0"/>
+                        <wp:positionH relativeFrom="column">
+                            <wp:align>` + r.Float + `</wp:align>
+                        </wp:positionH>
+                        <wp:positionV relativeFrom="paragraph">
+                            <wp:posOffset>0</wp:posOffset>
+                        </wp:positionV>`)
+		} else {
+			buf.WriteString(`
                 <w:drawing>
-                    <wp:inline distT="0" distB="0" distL="0" distR="0">
+                    <wp:inline distT="0" distB="0" distL="0" distR="0">`)
+		}
+		buf.WriteString(fmt.Sprintf(`
                         <wp:extent cx="%d" cy="%d"/>
-                        <wp:effectExtent l="0" t="0" r="0" b="0"/>
+                        <wp:effectExtent l="0" t="0" r="0" b="0"/>`, r.ImageWidth, r.ImageHeight))
+		if r.Float != "" {
+			buf.WriteString(`
+                        <wp:wrapSquare wrapText="bothSides"/>`)
+		}
+		buf.WriteString(fmt.Sprintf(`
                         <wp:docPr id="1" name="Picture"/>
                         <wp:cNvGraphicFramePr>
                             <a:graphicFrameLocks xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" noChangeAspect="1"/>
@@ -311,7 +511,7 @@ func (r *Run) ToXML() string {
                                         <pic:cNvPicPr/>
                                     </pic:nvPicPr>
                                     <pic:blipFill>
-                                        <a:blip r:embed="%s"/>
+                                        <a:blip r:embed="%s"%s
                                         <a:stretch>
                                             <a:fillRect/>
                                         </a:stretch>
@@ -328,29 +528,116 @@ func (r *Run) ToXML() string {
                                 </pic:pic>
                             </a:graphicData>
                         </a:graphic>
-                    </wp:inline>
-                </w:drawing>`, r.ImageWidth, r.ImageHeight, r.ImageRelID, r.ImageWidth, r.ImageHeight))
+                    </%s>
+                </w:drawing>`, r.ImageRelID, svgBlipXML(r.SVGRelID), r.ImageWidth, r.ImageHeight, drawing))
 	} else if r.Text != "" {
-		escapedText := XMLEscape(r.Text)
-		// 处理换行和空格
-		lines := strings.Split(escapedText, "\n")
+		// 处理换行、制表符和空格
+		// 需在转义前拆分：XMLEscape 会把 \n、\t 转成字符引用
+		lines := strings.Split(r.Text, "\n")
 		for i, line := range lines {
 			if i > 0 {
 				buf.WriteString(`
                 <w:br/>`)
 			}
-			if strings.HasPrefix(line, " ") || strings.HasSuffix(line, " ") || strings.Contains(line, "  ") {
-				buf.WriteString(`
-                <w:t xml:space="preserve">` + line + `</w:t>`)
-			} else {
-				buf.WriteString(`
-                <w:t>` + line + `</w:t>`)
+			segments := strings.Split(line, "\t")
+			for j, seg := range segments {
+				if j > 0 {
+					buf.WriteString(`
+                <w:tab/>`)
+				}
+				if seg == "" {
+					continue
+				}
+				if r.Deleted {
+					writeTextTag(buf, "w:delText", seg)
+				} else {
+					writeTextElement(buf, seg)
+				}
 			}
 		}
 	}
 
 	buf.WriteString(`
             </w:r>`)
+}
 
-	return buf.String()
+// svgBlipXML 返回 a:blip 起始标签的剩余部分，有 SVG 版本时以 asvg:svgBlip 扩展引用
+func svgBlipXML(relID string) string {
+	if relID == "" {
+		return "/>"
+	}
+	return `>
+                                            <a:extLst>
+                                                <a:ext uri="{96DAC541-7B7A-43D3-8B79-37D633B846F1}">
+                                                    <asvg:svgBlip xmlns:asvg="http://schemas.microsoft.com/office/drawing/2016/SVG/main" r:embed="` + relID + `"/>
+                                                </a:ext>
+                                            </a:extLst>
+                                        </a:blip>`
+}
+
+// writeTextElement 写入 <w:t>，首尾或连续空格时保留空白
+func writeTextElement(buf *bytes.Buffer, text string) {
+	writeTextTag(buf, "w:t", text)
+}
+
+// writeTextTag 以 tag（w:t 或修订删除用的 w:delText）写入文本
+func writeTextTag(buf *bytes.Buffer, tag, text string) {
+	buf.WriteString(`
+                <` + tag)
+	if strings.HasPrefix(text, " ") || strings.HasSuffix(text, " ") || strings.Contains(text, "  ") {
+		buf.WriteString(` xml:space="preserve"`)
+	}
+	buf.WriteString(`>`)
+	writeEscaped(buf, text)
+	buf.WriteString(`</` + tag + `>`)
+}
+
+// xmlWriter 可直接写入共享缓冲区的元素，避免生成中间字符串
+type xmlWriter interface {
+	WriteXML(buf *bytes.Buffer)
+}
+
+// writeElementXML 优先使用 WriteXML 写入元素，否则退回 ToXML
+func writeElementXML(buf *bytes.Buffer, elem interface{ ToXML() string }) {
+	if w, ok := elem.(xmlWriter); ok {
+		w.WriteXML(buf)
+		return
+	}
+	buf.WriteString(elem.ToXML())
+}
+
+// writeInt 写入十进制整数
+func writeInt(buf *bytes.Buffer, n int) {
+	var tmp [20]byte
+	buf.Write(strconv.AppendInt(tmp[:0], int64(n), 10))
+}
+
+// writeEscaped 转义XML特殊字符后写入，无需转义时直接写入原串
+func writeEscaped(buf *bytes.Buffer, s string) {
+	if !needsEscape(s) {
+		buf.WriteString(s)
+		return
+	}
+	xml.EscapeText(buf, []byte(s))
+}
+
+// needsEscape 判断 xml.EscapeText 是否会改写该字符串（特殊字符、控制字符、非法 UTF-8）
+func needsEscape(s string) bool {
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			switch {
+			case c < 0x20, c == '<', c == '>', c == '&', c == '\'', c == '"':
+				return true
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 || r >= 0xD800 && r <= 0xDFFF || r == 0xFFFE || r == 0xFFFF {
+			return true
+		}
+		i += size
+	}
+	return false
 }
@@ -0,0 +1,77 @@
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// rawXMLNamespaces 校验原始 OOXML 片段时使用的命名空间声明（与 document.xml 根元素一致）
+const rawXMLNamespaces = `xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"` +
+	` xmlns:wp="http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing"` +
+	` xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"` +
+	` xmlns:pic="http://schemas.openxmlformats.org/drawingml/2006/picture"` +
+	` xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"`
+
+// rawBlockElements 允许出现在片段顶层的块级元素（w:body 的合法子元素）
+var rawBlockElements = map[string]bool{
+	"p":             true,
+	"tbl":           true,
+	"sdt":           true,
+	"bookmarkStart": true,
+	"bookmarkEnd":   true,
+}
+
+// RawXML 原样插入文档主体的 OOXML 片段
+type RawXML struct {
+	XML string
+}
+
+// NewRawXML 校验片段并创建元素：
+// 片段必须是格式良好的 XML，顶层只能是 w:p、w:tbl、w:sdt、w:bookmarkStart/End 等块级元素，
+// 且顶层不能有非空白文本，否则插入 w:body 后文档无法打开
+func NewRawXML(fragment string) (*RawXML, error) {
+	dec := xml.NewDecoder(strings.NewReader("<root " + rawXMLNamespaces + ">" + fragment + "</root>"))
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("OOXML 片段格式错误: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			if depth == 2 && (t.Name.Space != nsWordprocessingML || !rawBlockElements[t.Name.Local]) {
+				return nil, fmt.Errorf("OOXML 片段顶层只允许 w:p、w:tbl、w:sdt、w:bookmarkStart、w:bookmarkEnd，发现 <%s>", rawElementName(t.Name))
+			}
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			if depth == 1 && len(bytes.TrimSpace(t)) > 0 {
+				return nil, fmt.Errorf("OOXML 片段顶层不能包含文本: %q", strings.TrimSpace(string(t)))
+			}
+		}
+	}
+	return &RawXML{XML: fragment}, nil
+}
+
+// rawElementName 以 w: 等常用前缀显示元素名
+func rawElementName(n xml.Name) string {
+	switch n.Space {
+	case nsWordprocessingML:
+		return "w:" + n.Local
+	case "":
+		return n.Local
+	}
+	return n.Space + ":" + n.Local
+}
+
+// ToXML 原样输出
+func (r *RawXML) ToXML() string {
+	return "\n" + r.XML
+}
@@ -0,0 +1,31 @@
+package docx
+
+import "testing"
+
+func TestNewRawXML(t *testing.T) {
+	tests := []struct {
+		name     string
+		fragment string
+		wantErr  bool
+	}{
+		{"paragraph", `<w:p><w:r><w:t>hi</w:t></w:r></w:p>`, false},
+		{"table", `<w:tbl><w:tr><w:tc><w:p/></w:tc></w:tr></w:tbl>`, false},
+		{"sdt", `<w:sdt><w:sdtContent><w:p/></w:sdtContent></w:sdt>`, false},
+		{"bookmark", `<w:bookmarkStart w:id="0" w:name="a"/><w:p/><w:bookmarkEnd w:id="0"/>`, false},
+		{"whitespace and comments", "\n  <!-- note -->\n  <w:p/>\n", false},
+		{"malformed", `<w:p><w:r></w:p>`, true},
+		{"bare run", `<w:r><w:t>hi</w:t></w:r>`, true},
+		{"top-level text", `hello <w:p/>`, true},
+		{"trailing text", `<w:p/> tail`, true},
+		{"foreign element", `<div/>`, true},
+		{"drawing namespace", `<a:graphic/>`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRawXML(tt.fragment)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewRawXML(%q) error = %v, wantErr %v", tt.fragment, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package docx
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// 修订类型
+const (
+	RevisionInsert = "ins"
+	RevisionDelete = "del"
+)
+
+// Revision 修订（Word 修订模式下的插入或删除），段落子元素
+type Revision struct {
+	Kind   string // RevisionInsert 或 RevisionDelete
+	ID     int
+	Author string
+	Date   string // ISO 8601，为空时不写
+	Runs   []*Run
+}
+
+// AddRevision 在段落末尾添加一处修订，id 由 Document.NextAnnotationID 分配
+func (p *Paragraph) AddRevision(kind string, id int, author, date string) *Revision {
+	rev := &Revision{Kind: kind, ID: id, Author: author, Date: date}
+	p.Children = append(p.Children, rev)
+	return rev
+}
+
+// AddRun 添加文本运行
+func (r *Revision) AddRun(text string) *Run {
+	run := &Run{Text: text, Deleted: r.Kind == RevisionDelete}
+	r.Runs = append(r.Runs, run)
+	return run
+}
+
+// AddFormattedRun 添加格式化文本运行
+func (r *Revision) AddFormattedRun(text string, bold, italic, code bool) *Run {
+	run := r.AddRun(text)
+	run.Bold = bold
+	run.Italic = italic
+	run.IsCode = code
+	return run
+}
+
+// AddImageRun 添加图片运行
+func (r *Revision) AddImageRun(relID string, width, height int64) *Run {
+	run := &Run{
+		IsImage:     true,
+		ImageRelID:  relID,
+		ImageWidth:  width,
+		ImageHeight: height,
+	}
+	r.Runs = append(r.Runs, run)
+	return run
+}
+
+// ToXML 转换为XML
+func (r *Revision) ToXML() string {
+	var buf bytes.Buffer
+	r.WriteXML(&buf)
+	return buf.String()
+}
+
+// WriteXML 把修订XML写入 buf
+func (r *Revision) WriteXML(buf *bytes.Buffer) {
+	buf.WriteString(`
+            <w:` + r.Kind + ` w:id="` + strconv.Itoa(r.ID) + `" w:author="`)
+	writeEscaped(buf, r.Author)
+	buf.WriteString(`"`)
+	if r.Date != "" {
+		buf.WriteString(` w:date="` + r.Date + `"`)
+	}
+	buf.WriteString(`>`)
+	for _, run := range mergeRuns(r.Runs) {
+		run.WriteXML(buf)
+	}
+	buf.WriteString(`
+            </w:` + r.Kind + `>`)
+}
+
+// NextAnnotationID 分配批注与修订共用的唯一ID
+func (d *Document) NextAnnotationID() int {
+	id := d.annotations
+	d.annotations++
+	return id
+}
@@ -0,0 +1,63 @@
+package docx
+
+import "strings"
+
+// mergeable 判断运行能否与相邻运行合并：图片与含换行的运行保持独立
+func (r *Run) mergeable() bool {
+	return !r.IsImage && !strings.Contains(r.Text, "\n")
+}
+
+// sameFormat 判断两个运行除文字外的属性是否完全相同（写出的 rPr 相同）
+func (r *Run) sameFormat(o *Run) bool {
+	a, b := *r, *o
+	a.Text, b.Text = "", ""
+	return a == b
+}
+
+// canMerge 判断相邻的 a、b 能否合并为一个运行
+func canMerge(a, b *Run) bool {
+	return a.mergeable() && b.mergeable() && a.sameFormat(b)
+}
+
+// mergeRuns 把相邻且格式相同的运行合并为一个，文字依次拼接；
+// 合并时使用副本，不修改原运行。没有可合并的运行时原样返回 runs
+func mergeRuns(runs []*Run) []*Run {
+	return mergeAdjacent(runs, func(r *Run) *Run { return r }, func(r *Run) *Run { return r })
+}
+
+// mergeChildRuns 对段落子元素中连续的运行执行 mergeRuns；
+// 超链接、批注范围、书签等其他子元素隔断合并
+func mergeChildRuns(children []ParagraphChild) []ParagraphChild {
+	asRun := func(child ParagraphChild) *Run {
+		r, _ := child.(*Run)
+		return r
+	}
+	return mergeAdjacent(children, asRun, func(r *Run) ParagraphChild { return r })
+}
+
+// mergeAdjacent 合并 items 中相邻的可合并运行；asRun 取出元素对应的运行（不是运行时为 nil），
+// wrap 把合并后的运行放回元素类型
+func mergeAdjacent[T any](items []T, asRun func(T) *Run, wrap func(*Run) T) []T {
+	mergesWith := func(a, b T) bool {
+		ra, rb := asRun(a), asRun(b)
+		return ra != nil && rb != nil && canMerge(ra, rb)
+	}
+	i := 1
+	for i < len(items) && !mergesWith(items[i-1], items[i]) {
+		i++
+	}
+	if i >= len(items) {
+		return items
+	}
+	merged := append(make([]T, 0, len(items)), items[:i]...)
+	for _, item := range items[i:] {
+		if last := merged[len(merged)-1]; mergesWith(last, item) {
+			joined := *asRun(last)
+			joined.Text += asRun(item).Text
+			merged[len(merged)-1] = wrap(&joined)
+			continue
+		}
+		merged = append(merged, item)
+	}
+	return merged
+}
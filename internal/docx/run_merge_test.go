@@ -0,0 +1,37 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMergeRuns(t *testing.T) {
+	p := NewParagraph("")
+	p.AddRun("func ").Color = "0000FF"
+	p.AddRun("main").Color = "0000FF"
+	p.AddRun("()")
+	p.AddRun(" {")
+	p.AddRun("\n")
+	p.AddRun("}")
+	p.AddImageRun("rId1", 10, 10)
+	p.AddImageRun("rId1", 10, 10)
+	link := p.AddHyperlink("rId2")
+	link.AddRun("链")
+	link.AddRun("接")
+
+	got := p.ToXML()
+	for _, want := range []string{">func main</w:t>", ">() {</w:t>", ">链接</w:t>"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("merged XML missing %s:\n%s", want, got)
+		}
+	}
+	if n := strings.Count(got, "<w:drawing>"); n != 2 {
+		t.Errorf("images = %d, want 2 (images are never merged)", n)
+	}
+	if n := strings.Count(got, "<w:r>"); n != 7 {
+		t.Errorf("runs = %d, want 7", n)
+	}
+	if p.Children[0].(*Run).Text != "func " || len(p.Children) != 9 {
+		t.Error("merging modified the paragraph")
+	}
+}
@@ -0,0 +1,147 @@
+package docx
+
+import (
+	"bytes"
+	"fmt"
+
+	"md2word/internal/config"
+)
+
+// Section 节属性
+// 文档由若干节组成：每个分节符段落携带其前一节的属性，body 末尾的 sectPr 描述最后一节。
+type Section struct {
+	PageNumberFormat string // 页码格式: decimal, upperRoman, lowerRoman, upperLetter, lowerLetter
+	PageNumberStart  int    // 页码起始值, 0 表示接续上一节
+	Landscape        bool   // 横向页面
+}
+
+// SectionBreak 分节符元素：结束当前节
+type SectionBreak struct {
+	doc     *Document
+	section *Section
+}
+
+// ToXML 分节符转换为XML（包含被结束节属性的空段落）
+func (b *SectionBreak) ToXML() string {
+	return `
+        <w:p>
+            <w:pPr>` + b.doc.sectionPropertiesXML(b.section) + `
+            </w:pPr>
+        </w:p>`
+}
+
+// CurrentSection 返回当前（尚未结束的）节
+func (d *Document) CurrentSection() *Section {
+	return d.section
+}
+
+// AddSectionBreak 结束当前节并以 next 开始新节
+func (d *Document) AddSectionBreak(next *Section) {
+	d.AddParagraph(&SectionBreak{doc: d, section: d.section})
+	d.section = next
+}
+
+// sectionPropertiesXML 生成节属性 <w:sectPr>
+// 子元素顺序遵循 schema: headerReference/footerReference, pgSz, pgMar, lnNumType, pgNumType, titlePg
+func (d *Document) sectionPropertiesXML(sec *Section) string {
+	var buf bytes.Buffer
+	buf.WriteString(`
+        <w:sectPr>`)
+
+	// 首页不同只作用于第一节；未给出首页页眉/页脚引用时首页对应位置为空白
+	titlePg := sec == d.firstSection && d.config.Page.Header.DifferentFirst
+	if d.headerRelID != "" {
+		buf.WriteString(fmt.Sprintf(`
+            <w:headerReference w:type="default" r:id="%s"/>`, d.headerRelID))
+	}
+	if titlePg && d.firstHeaderID != "" {
+		buf.WriteString(fmt.Sprintf(`
+            <w:headerReference w:type="first" r:id="%s"/>`, d.firstHeaderID))
+	}
+	// 奇偶页不同时 default 引用用于奇数页，偶数页需单独引用；页码页脚两侧共用同一部件
+	evenAndOdd := d.config.Page.Header.EvenAndOdd
+	if evenAndOdd && d.evenHeaderID != "" {
+		buf.WriteString(fmt.Sprintf(`
+            <w:headerReference w:type="even" r:id="%s"/>`, d.evenHeaderID))
+	}
+	if d.footerRelID != "" {
+		buf.WriteString(fmt.Sprintf(`
+            <w:footerReference w:type="default" r:id="%s"/>`, d.footerRelID))
+		if evenAndOdd {
+			buf.WriteString(fmt.Sprintf(`
+            <w:footerReference w:type="even" r:id="%s"/>`, d.footerRelID))
+		}
+	}
+
+	if sec.Landscape {
+		// 横向：纸张宽高互换，页边距随页面旋转
+		buf.WriteString(fmt.Sprintf(`
+            <w:pgSz w:w="%d" w:h="%d" w:orient="landscape"/>
+            <w:pgMar w:top="%d" w:right="%d" w:bottom="%d" w:left="%d" w:header="851" w:footer="992" w:gutter="%d"/>`,
+			PageHeightTwips, PageWidthTwips, MarginLeft, MarginTop, MarginRight, MarginBottom, d.config.Page.Margins.Gutter))
+	} else {
+		buf.WriteString(fmt.Sprintf(`
+            <w:pgSz w:w="%d" w:h="%d"/>
+            <w:pgMar w:top="%d" w:right="%d" w:bottom="%d" w:left="%d" w:header="851" w:footer="992" w:gutter="%d"/>`,
+			PageWidthTwips, PageHeightTwips, MarginTop, MarginRight, MarginBottom, MarginLeft, d.config.Page.Margins.Gutter))
+	}
+
+	if ln := d.config.Page.LineNumbers; ln.Enabled {
+		countBy := max(ln.CountBy, 1)
+		restart := ln.Restart
+		if restart == "" {
+			restart = config.LineNumbersContinuous
+		}
+		buf.WriteString(fmt.Sprintf(`
+            <w:lnNumType w:countBy="%d" w:restart="%s"/>`, countBy, restart))
+	}
+
+	if sec.PageNumberFormat != "" || sec.PageNumberStart > 0 {
+		buf.WriteString(`
+            <w:pgNumType`)
+		if sec.PageNumberFormat != "" {
+			buf.WriteString(` w:fmt="` + sec.PageNumberFormat + `"`)
+		}
+		if sec.PageNumberStart > 0 {
+			buf.WriteString(fmt.Sprintf(` w:start="%d"`, sec.PageNumberStart))
+		}
+		buf.WriteString(`/>`)
+	}
+
+	if titlePg {
+		buf.WriteString(`
+            <w:titlePg/>`)
+	}
+
+	buf.WriteString(`
+        </w:sectPr>`)
+	return buf.String()
+}
+
+// GeneratePageNumberFooter 生成居中显示页码 (PAGE 域) 的页脚
+func GeneratePageNumberFooter() string {
+	return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:ftr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+       xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+    <w:p>
+        <w:pPr>
+            <w:jc w:val="center"/>
+        </w:pPr>
+        <w:r>
+            <w:fldChar w:fldCharType="begin"/>
+        </w:r>
+        <w:r>
+            <w:instrText xml:space="preserve"> PAGE </w:instrText>
+        </w:r>
+        <w:r>
+            <w:fldChar w:fldCharType="separate"/>
+        </w:r>
+        <w:r>
+            <w:t>1</w:t>
+        </w:r>
+        <w:r>
+            <w:fldChar w:fldCharType="end"/>
+        </w:r>
+    </w:p>
+</w:ftr>`
+}
@@ -0,0 +1,78 @@
+package docx
+
+import (
+	"bytes"
+	"fmt"
+
+	"md2word/internal/config"
+)
+
+// GenerateSettings 生成 settings.xml 内容
+// 注意：CT_Settings 是有序序列，各元素必须按 schema 规定的先后顺序输出
+func GenerateSettings(cfg *config.Config) string {
+	s := cfg.Settings
+	var buf bytes.Buffer
+
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:settings xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">`)
+
+	if s.Zoom > 0 {
+		buf.WriteString(fmt.Sprintf(`
+    <w:zoom w:percent="%d"/>`, s.Zoom))
+	}
+
+	// 设置了页面背景色时，Word 需要该开关才会显示背景
+	if cfg.Page.Background != "" {
+		buf.WriteString(`
+    <w:displayBackgroundShape/>`)
+	}
+
+	if cfg.Fonts.Embed {
+		buf.WriteString(`
+    <w:embedTrueTypeFonts/>`)
+	}
+
+	// 对称页边距（装订线位于内侧）
+	if cfg.Page.Margins.Mirror {
+		buf.WriteString(`
+    <w:mirrorMargins/>`)
+	}
+
+	if s.HideSpellingErrors {
+		buf.WriteString(`
+    <w:hideSpellingErrors/>`)
+	}
+	if s.HideGrammaticalErrors {
+		buf.WriteString(`
+    <w:hideGrammaticalErrors/>`)
+	}
+
+	if s.DefaultTabStop > 0 {
+		buf.WriteString(fmt.Sprintf(`
+    <w:defaultTabStop w:val="%d"/>`, s.DefaultTabStop))
+	}
+
+	// 奇偶页使用不同的页眉页脚
+	if cfg.Page.Header.EvenAndOdd {
+		buf.WriteString(`
+    <w:evenAndOddHeaders/>`)
+	}
+
+	// 打开文档时刷新域（目录、题注编号等）
+	if s.UpdateFields {
+		buf.WriteString(`
+    <w:updateFields w:val="true"/>`)
+	}
+
+	if s.CompatibilityMode > 0 {
+		buf.WriteString(fmt.Sprintf(`
+    <w:compat>
+        <w:compatSetting w:name="compatibilityMode" w:uri="http://schemas.microsoft.com/office/word" w:val="%d"/>
+    </w:compat>`, s.CompatibilityMode))
+	}
+
+	buf.WriteString(`
+</w:settings>`)
+
+	return buf.String()
+}
@@ -0,0 +1,65 @@
+package docx
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+)
+
+// 自定义 XML 部件的关系类型
+const (
+	relTypeCustomXML      = relTypeBase + "customXml"
+	relTypeCustomXMLProps = relTypeBase + "customXmlProps"
+)
+
+// SourceNamespace 嵌入的 Markdown 源文件所在自定义 XML 部件的命名空间，读取方据此识别该部件
+const SourceNamespace = "urn:md2word:markdown-source"
+
+// sourceItemID 源文件部件在数据存储中的固定 ID
+const sourceItemID = "{5B0D2C8E-3F6A-4E1D-9A7B-6C2F8D4E1A30}"
+
+// EmbedSource 把 Markdown 源文件以 base64 存入自定义 XML 部件 customXml/item1.xml，
+// 供之后从 DOCX 还原源文件。流式模式下须在 StartStreaming 之前调用，内容类型随之声明
+func (d *Document) EmbedSource(markdown []byte) {
+	if d.sourceRelID == "" {
+		d.sourceRelID = d.addRelationship(relTypeCustomXML, "../customXml/item1.xml", "")
+	}
+	d.source = markdown
+}
+
+// writeSource 写入源文件的自定义 XML 部件及其属性部件；未嵌入源文件时不写
+func (d *Document) writeSource(w partCreator) error {
+	if d.sourceRelID == "" {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<source xmlns="` + SourceNamespace + `" encoding="base64">`)
+	buf.WriteString(base64.StdEncoding.EncodeToString(d.source))
+	buf.WriteString(`</source>`)
+
+	parts := []struct{ name, content string }{
+		{"customXml/item1.xml", buf.String()},
+		{"customXml/_rels/item1.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+    <Relationship Id="rId1" Type="` + relTypeCustomXMLProps + `" Target="itemProps1.xml"/>
+</Relationships>`},
+		{"customXml/itemProps1.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<ds:datastoreItem ds:itemID="` + sourceItemID + `" xmlns:ds="http://schemas.openxmlformats.org/officeDocument/2006/customXml">
+    <ds:schemaRefs>
+        <ds:schemaRef ds:uri="` + SourceNamespace + `"/>
+    </ds:schemaRefs>
+</ds:datastoreItem>`},
+	}
+	for _, part := range parts {
+		f, err := w.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package docx
+
+import (
+	"encoding/base64"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"md2word/internal/config"
+)
+
+func TestEmbedSource(t *testing.T) {
+	const markdown = "# 标题\n\n正文 <b>&amp;</b> ]]>\x01\n"
+	for _, streaming := range []bool{false, true} {
+		path := filepath.Join(t.TempDir(), "out.docx")
+		doc := NewDocument(config.DefaultConfig())
+		doc.EmbedSource([]byte(markdown))
+		if streaming {
+			if err := doc.StartStreaming(path); err != nil {
+				t.Fatal(err)
+			}
+		}
+		doc.AddParagraph(NewParagraph(""))
+		if err := doc.Save(path); err != nil {
+			t.Fatalf("streaming=%v: Save: %v", streaming, err)
+		}
+		if errs := Validate(path); len(errs) > 0 {
+			t.Errorf("streaming=%v: Validate: %v", streaming, errs)
+		}
+
+		parts := readZipParts(t, path)
+		m := regexp.MustCompile(`<source xmlns="` + SourceNamespace + `" encoding="base64">([^<]*)</source>`).FindStringSubmatch(parts["customXml/item1.xml"])
+		if m == nil {
+			t.Fatalf("streaming=%v: source part missing or malformed", streaming)
+		}
+		got, err := base64.StdEncoding.DecodeString(m[1])
+		if err != nil || string(got) != markdown {
+			t.Errorf("streaming=%v: decoded source = %q, %v", streaming, got, err)
+		}
+		if !strings.Contains(parts["word/_rels/document.xml.rels"], `Target="../customXml/item1.xml"`) {
+			t.Errorf("streaming=%v: document rels missing customXml relationship", streaming)
+		}
+		if !strings.Contains(parts["[Content_Types].xml"], `PartName="/customXml/itemProps1.xml"`) {
+			t.Errorf("streaming=%v: content types missing itemProps override", streaming)
+		}
+	}
+}
+
+func TestNoSourceByDefault(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.docx")
+	doc := NewDocument(config.DefaultConfig())
+	doc.AddParagraph(NewParagraph(""))
+	if err := doc.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	for name := range readZipParts(t, path) {
+		if strings.HasPrefix(name, "customXml/") {
+			t.Errorf("unexpected part %s", name)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package docx
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// documentStream 流式写入状态
+// 图片在加入时直接写入 zip；正文元素依次写入临时文件，保存时再拼接为 document.xml。
+// zip 同一时刻只能写一个条目，所以正文不能与图片交错直接写入 zip。
+type documentStream struct {
+	path    string
+	file    *os.File
+	zw      *zip.Writer
+	body    *os.File
+	bodyW   *bufio.Writer
+	elemBuf bytes.Buffer // 复用的元素XML缓冲区
+	types   []byte       // 已写入的 [Content_Types].xml，供保存前校验
+	images  []string     // 已写入的图片部件名
+	err     error        // 第一个写入错误，在保存时返回
+}
+
+// StartStreaming 开启流式写入：之后加入的元素与图片立即写入 path，
+// 文档不在内存中保留，Save 时补全其余部件。
+func (d *Document) StartStreaming(path string) error {
+	if d.stream != nil {
+		return fmt.Errorf("流式写入已开启")
+	}
+
+	// 内容类型与字体表依赖嵌入字体，提前读取以便尽早报错
+	embeds, embedOrder, err := loadEmbeddedFonts(d.config)
+	if err != nil {
+		return err
+	}
+	d.fontEmbeds, d.fontEmbedOrder = embeds, embedOrder
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("创建文件失败: %w", err)
+	}
+	body, err := os.CreateTemp("", "md2word-body-*.xml")
+	if err != nil {
+		file.Close()
+		os.Remove(path)
+		return fmt.Errorf("创建临时文件失败: %w", err)
+	}
+
+	d.stream = &documentStream{
+		path:  path,
+		file:  file,
+		zw:    zip.NewWriter(file),
+		body:  body,
+		bodyW: bufio.NewWriter(body),
+	}
+
+	// OPC 读取方期望 [Content_Types].xml 是第一个条目，须在任何图片之前写入。
+	// 它只取决于页眉页脚、批注部件与嵌入字体；批注可能在之后才出现，
+	// 因此流式模式总是登记批注部件（可能为空）。
+	d.ensureCommentsPart()
+	var types bytes.Buffer
+	if err := d.writeContentTypes(singlePart{&types}); err != nil {
+		d.Abort()
+		return err
+	}
+	f, err := d.stream.zw.Create("[Content_Types].xml")
+	if err == nil {
+		_, err = f.Write(types.Bytes())
+	}
+	if err != nil {
+		d.Abort()
+		return fmt.Errorf("写入内容类型失败: %w", err)
+	}
+	d.stream.types = types.Bytes()
+
+	// 开启前已加入的内容
+	for _, elem := range d.elements {
+		d.stream.writeElement(elem)
+	}
+	d.elements = nil
+	for name, img := range d.images {
+		d.stream.writeImage(name, img)
+	}
+	d.images = make(map[string]*ImageData)
+	return nil
+}
+
+// Abort 放弃流式写入并删除不完整的输出文件；非流式模式下无操作
+func (d *Document) Abort() {
+	if d.stream == nil {
+		return
+	}
+	d.stream.close()
+	os.Remove(d.stream.path)
+	d.stream = nil
+}
+
+// writeElement 把元素XML追加到正文临时文件
+func (s *documentStream) writeElement(elem Element) {
+	if s.err != nil {
+		return
+	}
+	s.elemBuf.Reset()
+	writeElementXML(&s.elemBuf, elem)
+	if _, err := s.bodyW.Write(s.elemBuf.Bytes()); err != nil {
+		s.err = fmt.Errorf("写入正文失败: %w", err)
+	}
+}
+
+// writeImage 把图片直接写入 zip
+func (s *documentStream) writeImage(name string, img *ImageData) {
+	if s.err != nil {
+		return
+	}
+	f, err := s.zw.Create("word/media/" + name)
+	if err == nil {
+		_, err = f.Write(img.Data)
+	}
+	if err != nil {
+		s.err = fmt.Errorf("写入图片失败: %w", err)
+		return
+	}
+	s.images = append(s.images, "word/media/"+name)
+}
+
+// singlePart 把唯一一个部件写入 buf 的 partCreator
+type singlePart struct {
+	buf *bytes.Buffer
+}
+
+func (p singlePart) Create(string) (io.Writer, error) {
+	return p.buf, nil
+}
+
+// close 释放文件句柄并删除正文临时文件
+func (s *documentStream) close() {
+	s.zw.Close()
+	s.file.Close()
+	s.body.Close()
+	os.Remove(s.body.Name())
+}
+
+// finishStreaming 写入其余部件并完成文件；校验失败时删除输出文件
+func (d *Document) finishStreaming() (err error) {
+	s := d.stream
+	defer func() {
+		if err != nil {
+			d.Abort()
+			return
+		}
+		s.body.Close()
+		os.Remove(s.body.Name())
+		d.stream = nil
+	}()
+
+	if s.err != nil {
+		return s.err
+	}
+	if err := s.bodyW.Flush(); err != nil {
+		return fmt.Errorf("写入正文失败: %w", err)
+	}
+
+	parts := newPackageParts()
+	if err := d.writePackageParts(parts); err != nil {
+		return err
+	}
+
+	// 正文从临时文件按需读取，图片只需校验是否存在与内容类型
+	header, trailer := d.documentHeaderXML(), d.documentTrailerXML()
+	openDocument := func() (io.Reader, error) {
+		if _, err := s.body.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		return io.MultiReader(strings.NewReader(header), s.body, strings.NewReader(trailer)), nil
+	}
+	sources := parts.sources()
+	sources["[Content_Types].xml"] = func() (io.Reader, error) { return bytes.NewReader(s.types), nil }
+	sources["word/document.xml"] = openDocument
+	for _, name := range s.images {
+		sources[name] = func() (io.Reader, error) { return strings.NewReader(""), nil }
+	}
+	if err := d.validate(sources); err != nil {
+		return err
+	}
+
+	if err := parts.writeTo(s.zw); err != nil {
+		return err
+	}
+	f, err := s.zw.Create("word/document.xml")
+	if err != nil {
+		return err
+	}
+	r, err := openDocument()
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		return err
+	}
+
+	if err := s.zw.Close(); err != nil {
+		return err
+	}
+	return s.file.Close()
+}
@@ -0,0 +1,57 @@
+package docx
+
+import (
+	"archive/zip"
+	"path/filepath"
+	"testing"
+
+	"md2word/internal/config"
+)
+
+func TestStreamingWritesContentTypesFirst(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.docx")
+	doc := NewDocument(config.DefaultConfig())
+	if err := doc.StartStreaming(path); err != nil {
+		t.Fatal(err)
+	}
+	rID := doc.AddImage([]byte("\x89PNG\r\n\x1a\n"), "image/png", 10, 10)
+	p := NewParagraph("")
+	p.AddImageRun(rID, 9525, 9525)
+	doc.AddParagraph(p)
+	if err := doc.Save(""); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	if got := zr.File[0].Name; got != "[Content_Types].xml" {
+		t.Errorf("first entry = %s, want [Content_Types].xml", got)
+	}
+	count := 0
+	for _, f := range zr.File {
+		if f.Name == "[Content_Types].xml" {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("[Content_Types].xml written %d times", count)
+	}
+}
+
+func TestStreamingComments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.docx")
+	doc := NewDocument(config.DefaultConfig())
+	if err := doc.StartStreaming(path); err != nil {
+		t.Fatal(err)
+	}
+	p := NewParagraph("")
+	p.AddRun("正文")
+	p.AddComment(doc.AddComment("md2word", "批注"))
+	doc.AddParagraph(p)
+	if err := doc.Save(""); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+}
@@ -3,10 +3,19 @@ package docx
 import (
 	"bytes"
 	"fmt"
+	"strings"
 
 	"md2word/internal/config"
 )
 
+// 转换器引用的段落样式 ID
+const (
+	BodyTextStyleID  = "BodyText"  // 正文
+	QuoteStyleID     = "Quote"     // 引用块
+	CodeBlockStyleID = "CodeBlock" // 代码块单元格中的代码行
+	CodeCharStyleID  = "CodeChar"  // 行内代码（字符样式）
+)
+
 // GenerateStyles 生成样式XML
 func GenerateStyles(cfg *config.Config) string {
 	var buf bytes.Buffer
@@ -17,8 +26,8 @@ func GenerateStyles(cfg *config.Config) string {
         <w:rPrDefault>
             <w:rPr>
                 <w:rFonts w:ascii="` + cfg.Styles.Body.Font + `" w:eastAsia="` + cfg.Styles.Body.Font + `" w:hAnsi="` + cfg.Styles.Body.Font + `"/>
-                <w:sz w:val="` + fmt.Sprintf("%d", int(cfg.Styles.Body.Size*2)) + `"/>
-                <w:szCs w:val="` + fmt.Sprintf("%d", int(cfg.Styles.Body.Size*2)) + `"/>
+                <w:sz w:val="` + fmt.Sprintf("%d", int(cfg.ScaleFont(float64(cfg.Styles.Body.Size))*2)) + `"/>
+                <w:szCs w:val="` + fmt.Sprintf("%d", int(cfg.ScaleFont(float64(cfg.Styles.Body.Size))*2)) + `"/>
             </w:rPr>
         </w:rPrDefault>
         <w:pPrDefault>
@@ -31,60 +40,231 @@ func GenerateStyles(cfg *config.Config) string {
 	// Normal样式
 	buf.WriteString(`
     <w:style w:type="paragraph" w:default="1" w:styleId="Normal">
-        <w:name w:val="Normal"/>
+        <w:name w:val="Normal"/>`)
+	if cfg.Styles.Body.Background != "" {
+		buf.WriteString(`
+        <w:pPr>` + paragraphShadingXML(cfg.Styles.Body.Background) + `
+        </w:pPr>`)
+	}
+	buf.WriteString(`
         <w:rPr>
-            <w:rFonts w:ascii="` + cfg.Styles.Body.Font + `" w:eastAsia="` + cfg.Styles.Body.Font + `" w:hAnsi="` + cfg.Styles.Body.Font + `"/>
-            <w:sz w:val="` + fmt.Sprintf("%d", int(cfg.Styles.Body.Size*2)) + `"/>
-            <w:szCs w:val="` + fmt.Sprintf("%d", int(cfg.Styles.Body.Size*2)) + `"/>
+            <w:rFonts w:ascii="` + cfg.Styles.Body.Font + `" w:eastAsia="` + cfg.Styles.Body.Font + `" w:hAnsi="` + cfg.Styles.Body.Font + `"/>`)
+	// rPr 子元素须按 CT_RPr 顺序：rFonts, b, i, caps/smallCaps, color, spacing, sz
+	if cfg.Styles.Body.Bold {
+		buf.WriteString(`
+            <w:b/>
+            <w:bCs/>`)
+	}
+	if cfg.Styles.Body.Italic {
+		buf.WriteString(`
+            <w:i/>
+            <w:iCs/>`)
+	}
+	if cfg.Styles.Body.Color != "" {
+		buf.WriteString(`
+            <w:color w:val="` + strings.TrimPrefix(cfg.Styles.Body.Color, "#") + `"/>`)
+	}
+	buf.WriteString(`
+            <w:sz w:val="` + fmt.Sprintf("%d", int(cfg.ScaleFont(float64(cfg.Styles.Body.Size))*2)) + `"/>
+            <w:szCs w:val="` + fmt.Sprintf("%d", int(cfg.ScaleFont(float64(cfg.Styles.Body.Size))*2)) + `"/>
         </w:rPr>
     </w:style>`)
 
+	// 正文样式（Word 内置 Body Text），带正文的间距与缩进，正文段落引用它
+	body := cfg.Styles.Body
+	buf.WriteString(`
+    <w:style w:type="paragraph" w:styleId="` + BodyTextStyleID + `">
+        <w:name w:val="Body Text"/>
+        <w:basedOn w:val="Normal"/>
+        <w:qFormat/>
+        <w:pPr>
+            <w:spacing w:before="` + fmt.Sprintf("%d", body.SpaceBefore) + `" w:after="` + fmt.Sprintf("%d", body.SpaceAfter) + `"`)
+	if body.LineHeight > 0 {
+		buf.WriteString(` w:line="` + fmt.Sprintf("%d", body.LineHeight) + `" w:lineRule="auto"`)
+	}
+	buf.WriteString(`/>`)
+	if body.FirstLineIndent > 0 {
+		buf.WriteString(`
+            <w:ind w:firstLine="` + fmt.Sprintf("%d", body.FirstLineIndent) + `"/>`)
+	} else if body.FirstLineIndent < 0 {
+		buf.WriteString(`
+            <w:ind w:hanging="` + fmt.Sprintf("%d", -body.FirstLineIndent) + `"/>`)
+	}
+	buf.WriteString(`
+        </w:pPr>
+    </w:style>`)
+
+	// 引用样式（Word 内置 Quote），带引用块的边框、底纹与缩进
+	buf.WriteString(`
+    <w:style w:type="paragraph" w:styleId="` + QuoteStyleID + `">
+        <w:name w:val="Quote"/>
+        <w:basedOn w:val="Normal"/>
+        <w:next w:val="` + BodyTextStyleID + `"/>
+        <w:qFormat/>
+        <w:pPr>
+            <w:pBdr>
+                <w:top w:val="single" w:sz="4" w:space="1" w:color="C0C0C0"/>
+                <w:left w:val="single" w:sz="4" w:space="4" w:color="C0C0C0"/>
+                <w:bottom w:val="single" w:sz="4" w:space="1" w:color="C0C0C0"/>
+                <w:right w:val="single" w:sz="4" w:space="4" w:color="C0C0C0"/>
+            </w:pBdr>
+            <w:shd w:val="clear" w:color="auto" w:fill="F0F0F0"/>`)
+	if body.LineHeight > 0 {
+		buf.WriteString(`
+            <w:spacing w:line="` + fmt.Sprintf("%d", body.LineHeight) + `" w:lineRule="auto"/>`)
+	}
+	buf.WriteString(`
+            <w:ind w:left="360"/>
+        </w:pPr>
+    </w:style>`)
+
 	// 各级标题样式
+	// 1~TOCMaxLevel 级设置大纲级别；更深的标题显式设为正文级别 (9)，
+	// 否则 Word 仍会按内置样式名 "heading N" 推出大纲级别，使其出现在导航窗格和目录中
+	tocMaxLevel := cfg.TOCMaxLevel()
 	for level := 1; level <= 9; level++ {
 		style := cfg.GetHeadingStyle(level)
 		styleID := fmt.Sprintf("Heading%d", level)
-		outlineLvl := level - 1
+		keepNext := ""
+		if cfg.Headings.KeepWithNext {
+			keepNext = `
+            <w:keepNext/>`
+		}
+		outlineLvl := 9
+		if level <= tocMaxLevel {
+			outlineLvl = level - 1
+		}
 
 		buf.WriteString(`
     <w:style w:type="paragraph" w:styleId="` + styleID + `">
         <w:name w:val="heading ` + fmt.Sprintf("%d", level) + `"/>
         <w:basedOn w:val="Normal"/>
         <w:next w:val="Normal"/>
-        <w:pPr>
-            <w:keepNext/>
-            <w:keepLines/>
-            <w:spacing w:before="240" w:after="120"/>
+        <w:pPr>` + keepNext + `
+            <w:keepLines/>` + paragraphShadingXML(style.Background) + `
+            <w:spacing w:before="` + fmt.Sprintf("%d", style.SpaceBefore) + `" w:after="` + fmt.Sprintf("%d", style.SpaceAfter) + `"/>
             <w:outlineLvl w:val="` + fmt.Sprintf("%d", outlineLvl) + `"/>
         </w:pPr>
         <w:rPr>
-            <w:rFonts w:ascii="` + style.Font + `" w:eastAsia="` + style.Font + `" w:hAnsi="` + style.Font + `"/>
-            <w:sz w:val="` + fmt.Sprintf("%d", int(style.Size*2)) + `"/>
-            <w:szCs w:val="` + fmt.Sprintf("%d", int(style.Size*2)) + `"/>`)
+            <w:rFonts w:ascii="` + style.Font + `" w:eastAsia="` + style.Font + `" w:hAnsi="` + style.Font + `"/>`)
 
 		if style.Bold {
 			buf.WriteString(`
             <w:b/>
             <w:bCs/>`)
 		}
+		if style.Italic {
+			buf.WriteString(`
+            <w:i/>
+            <w:iCs/>`)
+		}
+		if style.SmallCaps {
+			buf.WriteString(`
+            <w:smallCaps/>`)
+		}
+
+		if style.Color != "" {
+			buf.WriteString(`
+            <w:color w:val="` + strings.TrimPrefix(style.Color, "#") + `"/>`)
+		}
+		if style.LetterSpacing != 0 {
+			buf.WriteString(`
+            <w:spacing w:val="` + fmt.Sprintf("%d", style.LetterSpacing) + `"/>`)
+		}
 
 		buf.WriteString(`
+            <w:sz w:val="` + fmt.Sprintf("%d", int(cfg.ScaleFont(float64(style.Size))*2)) + `"/>
+            <w:szCs w:val="` + fmt.Sprintf("%d", int(cfg.ScaleFont(float64(style.Size))*2)) + `"/>
         </w:rPr>
     </w:style>`)
 	}
 
 	// 代码样式
+	codeFill := "F5F5F5"
+	if cfg.Styles.CodeBlock.Background != "" {
+		codeFill = strings.TrimPrefix(cfg.Styles.CodeBlock.Background, "#")
+	}
 	buf.WriteString(`
     <w:style w:type="paragraph" w:styleId="Code">
         <w:name w:val="Code"/>
         <w:basedOn w:val="Normal"/>
         <w:pPr>
-            <w:shd w:val="clear" w:color="auto" w:fill="F5F5F5"/>
+            <w:shd w:val="clear" w:color="auto" w:fill="` + codeFill + `"/>
             <w:spacing w:before="120" w:after="120"/>
         </w:pPr>
         <w:rPr>
             <w:rFonts w:ascii="Consolas" w:hAnsi="Consolas" w:cs="Consolas"/>
-            <w:sz w:val="` + fmt.Sprintf("%d", int(cfg.Styles.Code.Size*2)) + `"/>
-            <w:szCs w:val="` + fmt.Sprintf("%d", int(cfg.Styles.Code.Size*2)) + `"/>
+            <w:sz w:val="` + fmt.Sprintf("%d", int(cfg.ScaleFont(float64(cfg.Styles.Code.Size))*2)) + `"/>
+            <w:szCs w:val="` + fmt.Sprintf("%d", int(cfg.ScaleFont(float64(cfg.Styles.Code.Size))*2)) + `"/>
+        </w:rPr>
+    </w:style>`)
+
+	// 代码块样式：代码块单元格中每行代码的字体、字号与行距，高亮颜色仍按 run 设置
+	codeBlock := cfg.Styles.CodeBlock
+	codeFont, codeSize := codeBlock.Font, float64(codeBlock.Size)
+	if codeFont == "" {
+		codeFont = "Consolas"
+	}
+	if codeSize == 0 {
+		codeSize = 9.5
+	}
+	codeSize = cfg.ScaleFont(codeSize)
+	buf.WriteString(`
+    <w:style w:type="paragraph" w:styleId="` + CodeBlockStyleID + `">
+        <w:name w:val="Code Block"/>
+        <w:basedOn w:val="Normal"/>
+        <w:qFormat/>
+        <w:pPr>
+            <w:spacing w:before="` + fmt.Sprintf("%d", codeBlock.LineSpacing/2) + `" w:after="` + fmt.Sprintf("%d", codeBlock.LineSpacing/2) + `"`)
+	if codeBlock.LineHeight > 0 {
+		buf.WriteString(` w:line="` + fmt.Sprintf("%d", codeBlock.LineHeight) + `" w:lineRule="auto"`)
+	}
+	buf.WriteString(`/>
+        </w:pPr>
+        <w:rPr>
+            <w:rFonts w:ascii="` + codeFont + `" w:eastAsia="` + codeFont + `" w:hAnsi="` + codeFont + `" w:cs="` + codeFont + `"/>
+            <w:sz w:val="` + fmt.Sprintf("%d", int(codeSize*2)) + `"/>
+            <w:szCs w:val="` + fmt.Sprintf("%d", int(codeSize*2)) + `"/>
+        </w:rPr>
+    </w:style>`)
+
+	// 行内代码样式：字符样式，行内代码的 run 只引用样式，不再逐个写字体、字号与底色
+	inlineFont, inlineSize := cfg.InlineCodeFont()
+	inlineFill := "E8E8E8"
+	if cfg.Styles.Code.Background != "" {
+		inlineFill = strings.TrimPrefix(cfg.Styles.Code.Background, "#")
+	}
+	buf.WriteString(`
+    <w:style w:type="character" w:styleId="` + CodeCharStyleID + `">
+        <w:name w:val="Inline Code"/>
+        <w:rPr>
+            <w:rFonts w:ascii="` + inlineFont + `" w:eastAsia="` + inlineFont + `" w:hAnsi="` + inlineFont + `" w:cs="` + inlineFont + `"/>`)
+	if cfg.Styles.Code.Color != "" {
+		buf.WriteString(`
+            <w:color w:val="` + strings.TrimPrefix(cfg.Styles.Code.Color, "#") + `"/>`)
+	}
+	buf.WriteString(`
+            <w:sz w:val="` + fmt.Sprintf("%d", int(inlineSize*2)) + `"/>
+            <w:szCs w:val="` + fmt.Sprintf("%d", int(inlineSize*2)) + `"/>
+            <w:shd w:val="clear" w:color="auto" w:fill="` + inlineFill + `"/>
+        </w:rPr>
+    </w:style>`)
+
+	// 题注样式（Word 内置 caption），用于图片说明
+	buf.WriteString(`
+    <w:style w:type="paragraph" w:styleId="Caption">
+        <w:name w:val="caption"/>
+        <w:basedOn w:val="Normal"/>
+        <w:next w:val="Normal"/>
+        <w:pPr>
+            <w:jc w:val="center"/>
+            <w:spacing w:before="60" w:after="200"/>
+        </w:pPr>
+        <w:rPr>
+            <w:i/>
+            <w:iCs/>
+            <w:sz w:val="` + fmt.Sprintf("%d", int(cfg.ScaleFont(float64(cfg.Styles.Body.Size))*2)-2) + `"/>
+            <w:szCs w:val="` + fmt.Sprintf("%d", int(cfg.ScaleFont(float64(cfg.Styles.Body.Size))*2)-2) + `"/>
         </w:rPr>
     </w:style>`)
 
@@ -111,6 +291,16 @@ func GenerateStyles(cfg *config.Config) string {
 	return buf.String()
 }
 
+// paragraphShadingXML 段落底色 <w:shd>，color 为空时返回空串
+// 样式的 background 作用于整段（段落级底纹）；行内代码的底色由 Run.Shading 逐段设置（文字级底纹）
+func paragraphShadingXML(color string) string {
+	if color == "" {
+		return ""
+	}
+	return `
+            <w:shd w:val="clear" w:color="auto" w:fill="` + strings.TrimPrefix(color, "#") + `"/>`
+}
+
 // FontSizeToTwips 将磅值转换为Twips (1pt = 2 half-points)
 func FontSizeToTwips(pt float64) int {
 	return int(pt * 2)
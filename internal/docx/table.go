@@ -2,14 +2,17 @@ package docx
 
 import (
 	"bytes"
-	"fmt"
 )
 
 // Table 表格
 type Table struct {
-	Rows       []*TableRow
-	ColWidths  []int // 列宽(twips)
-	HasBorders bool
+	Rows        []*TableRow
+	ColWidths   []int // 列宽(twips)
+	HasBorders  bool  // 为 false 时去掉 TableGrid 样式的边框
+	FixedLayout bool  // 固定列宽布局，禁止 Word 按内容自动调整
+	CellMargin  int   // 单元格左右内边距 (twips)，0 表示使用 Word 默认值 (108)
+	Width       int   // 表格宽度：WidthPct 时以 1/50 个百分点计 (5000 = 100%)，否则为 twips；0 表示按内容自动
+	WidthPct    bool  // Width 为页面内容宽度的百分比
 }
 
 // TableRow 表格行
@@ -21,10 +24,17 @@ type TableRow struct {
 // TableCell 表格单元格
 type TableCell struct {
 	Paragraphs []*Paragraph
-	Width      int    // 单元格宽度(twips)
-	Align      string // left, center, right
-	VAlign     string // top, center, bottom
-	Shading    string // 背景色
+	Tables     []*NestedTable // 嵌套表格，按 After 插在段落之间
+	Width      int            // 单元格宽度(twips)
+	Align      string         // left, center, right
+	VAlign     string         // top, center, bottom
+	Shading    string         // 背景色
+}
+
+// NestedTable 单元格中的嵌套表格
+type NestedTable struct {
+	After int // 位于单元格第 After 个段落之后（0 表示位于所有段落之前）
+	Table *Table
 }
 
 // NewTable 创建新表格
@@ -60,6 +70,11 @@ func (c *TableCell) AddParagraph(p *Paragraph) {
 	c.Paragraphs = append(c.Paragraphs, p)
 }
 
+// AddTable 在单元格现有段落之后添加嵌套表格
+func (c *TableCell) AddTable(t *Table) {
+	c.Tables = append(c.Tables, &NestedTable{After: len(c.Paragraphs), Table: t})
+}
+
 // SetText 设置单元格文本
 func (c *TableCell) SetText(text string, bold bool) {
 	p := NewParagraph("")
@@ -71,13 +86,27 @@ func (c *TableCell) SetText(text string, bold bool) {
 // ToXML 表格转换为XML
 func (t *Table) ToXML() string {
 	var buf bytes.Buffer
+	t.WriteXML(&buf)
+	return buf.String()
+}
 
+// WriteXML 把表格XML写入 buf
+func (t *Table) WriteXML(buf *bytes.Buffer) {
 	buf.WriteString(`
         <w:tbl>
             <w:tblPr>
                 <w:tblStyle w:val="TableGrid"/>
-                <w:tblW w:w="0" w:type="auto"/>
-                <w:tblLook w:val="04A0" w:firstRow="1" w:lastRow="0" w:firstColumn="1" w:lastColumn="0" w:noHBand="0" w:noVBand="1"/>`)
+                <w:tblW w:w="`)
+	switch {
+	case t.Width <= 0:
+		buf.WriteString(`0" w:type="auto"/>`)
+	case t.WidthPct:
+		writeInt(buf, t.Width)
+		buf.WriteString(`" w:type="pct"/>`)
+	default:
+		writeInt(buf, t.Width)
+		buf.WriteString(`" w:type="dxa"/>`)
+	}
 
 	if t.HasBorders {
 		buf.WriteString(`
@@ -89,9 +118,38 @@ func (t *Table) ToXML() string {
                     <w:insideH w:val="single" w:sz="4" w:space="0" w:color="auto"/>
                     <w:insideV w:val="single" w:sz="4" w:space="0" w:color="auto"/>
                 </w:tblBorders>`)
+	} else {
+		buf.WriteString(`
+                <w:tblBorders>
+                    <w:top w:val="nil"/>
+                    <w:left w:val="nil"/>
+                    <w:bottom w:val="nil"/>
+                    <w:right w:val="nil"/>
+                    <w:insideH w:val="nil"/>
+                    <w:insideV w:val="nil"/>
+                </w:tblBorders>`)
+	}
+
+	if t.FixedLayout {
+		buf.WriteString(`
+                <w:tblLayout w:type="fixed"/>`)
+	}
+
+	if t.CellMargin > 0 {
+		buf.WriteString(`
+                <w:tblCellMar>
+                    <w:left w:w="`)
+		writeInt(buf, t.CellMargin)
+		buf.WriteString(`" w:type="dxa"/>
+                    <w:right w:w="`)
+		writeInt(buf, t.CellMargin)
+		buf.WriteString(`" w:type="dxa"/>
+                </w:tblCellMar>`)
 	}
 
+	// tblLook 在 schema 中位于 tblPr 末尾
 	buf.WriteString(`
+                <w:tblLook w:val="04A0" w:firstRow="1" w:lastRow="0" w:firstColumn="1" w:lastColumn="0" w:noHBand="0" w:noVBand="1"/>
             </w:tblPr>`)
 
 	// 列宽定义
@@ -99,8 +157,10 @@ func (t *Table) ToXML() string {
 		buf.WriteString(`
             <w:tblGrid>`)
 		for _, w := range t.ColWidths {
-			buf.WriteString(fmt.Sprintf(`
-                <w:gridCol w:w="%d"/>`, w))
+			buf.WriteString(`
+                <w:gridCol w:w="`)
+			writeInt(buf, w)
+			buf.WriteString(`"/>`)
 		}
 		buf.WriteString(`
             </w:tblGrid>`)
@@ -124,8 +184,10 @@ func (t *Table) ToXML() string {
                     <w:tcPr>`)
 
 			if cell.Width > 0 {
-				buf.WriteString(fmt.Sprintf(`
-                        <w:tcW w:w="%d" w:type="dxa"/>`, cell.Width))
+				buf.WriteString(`
+                        <w:tcW w:w="`)
+				writeInt(buf, cell.Width)
+				buf.WriteString(`" w:type="dxa"/>`)
 			}
 
 			if cell.Shading != "" {
@@ -141,18 +203,7 @@ func (t *Table) ToXML() string {
 			buf.WriteString(`
                     </w:tcPr>`)
 
-			if len(cell.Paragraphs) == 0 {
-				// 空单元格也需要一个段落
-				buf.WriteString(`
-                    <w:p/>`)
-			} else {
-				for _, p := range cell.Paragraphs {
-					if cell.Align != "" && p.Align == "" {
-						p.Align = cell.Align
-					}
-					buf.WriteString(p.ToXML())
-				}
-			}
+			cell.writeContent(buf)
 
 			buf.WriteString(`
                 </w:tc>`)
@@ -164,8 +215,30 @@ func (t *Table) ToXML() string {
 
 	buf.WriteString(`
         </w:tbl>`)
+}
 
-	return buf.String()
+// writeContent 按顺序写出单元格的段落与嵌套表格；
+// 单元格必须以段落结尾，空单元格或以表格结尾时补一个空段落
+func (c *TableCell) writeContent(buf *bytes.Buffer) {
+	next := 0
+	writeTables := func(after int) {
+		for ; next < len(c.Tables) && c.Tables[next].After <= after; next++ {
+			c.Tables[next].Table.WriteXML(buf)
+		}
+	}
+	writeTables(0)
+	for i, p := range c.Paragraphs {
+		if c.Align != "" && p.Align == "" {
+			p.Align = c.Align
+		}
+		p.WriteXML(buf)
+		writeTables(i + 1)
+	}
+	endsWithTable := len(c.Tables) > 0 && c.Tables[len(c.Tables)-1].After >= len(c.Paragraphs)
+	if len(c.Paragraphs) == 0 || endsWithTable {
+		buf.WriteString(`
+                    <w:p/>`)
+	}
 }
 
 // TableElement 表格元素（用于添加到文档）
@@ -182,3 +255,8 @@ func NewTableElement(t *Table) *TableElement {
 func (te *TableElement) ToXML() string {
 	return te.table.ToXML()
 }
+
+// WriteXML 把表格元素XML写入 buf
+func (te *TableElement) WriteXML(buf *bytes.Buffer) {
+	te.table.WriteXML(buf)
+}
@@ -0,0 +1,387 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+const (
+	nsWordprocessingML = "http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+	nsRelationships    = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+)
+
+// partCreator 按部件名创建写入目标，*zip.Writer 与 packageParts 均满足
+type partCreator interface {
+	Create(name string) (io.Writer, error)
+}
+
+// packageParts 内存中的包部件集合，校验通过后再整体写入 zip
+type packageParts struct {
+	names []string
+	data  map[string]*bytes.Buffer
+}
+
+func newPackageParts() *packageParts {
+	return &packageParts{data: make(map[string]*bytes.Buffer)}
+}
+
+// Create 新建（或覆盖）一个部件
+func (p *packageParts) Create(name string) (io.Writer, error) {
+	if _, ok := p.data[name]; !ok {
+		p.names = append(p.names, name)
+	}
+	buf := &bytes.Buffer{}
+	p.data[name] = buf
+	return buf, nil
+}
+
+// sources 返回供校验读取的部件集合
+func (p *packageParts) sources() map[string]partOpener {
+	m := make(map[string]partOpener, len(p.data))
+	for name, buf := range p.data {
+		data := buf.Bytes()
+		m[name] = func() (io.Reader, error) { return bytes.NewReader(data), nil }
+	}
+	return m
+}
+
+// writeTo 按创建顺序把部件写入目标
+func (p *packageParts) writeTo(w partCreator) error {
+	for _, name := range p.names {
+		f, err := w.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(p.data[name].Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validate 保存前的最小 OOXML 校验，避免生成 Word 无法打开的文件
+func (d *Document) validate(parts map[string]partOpener) error {
+	errs := validateParts(parts)
+	if len(errs) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Error()
+	}
+	return fmt.Errorf("文档校验失败:\n  %s", strings.Join(msgs, "\n  "))
+}
+
+// Validate 校验已生成的 docx 文件，返回发现的全部问题，没有问题时返回 nil。
+// 除保存前的检查（见 validateParts）外，还检查必需部件是否存在、部件名是否重复
+func Validate(path string) []error {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return []error{err}
+	}
+	defer zr.Close()
+
+	var errs []error
+	parts := make(map[string]partOpener, len(zr.File))
+	for _, f := range zr.File {
+		if _, dup := parts[f.Name]; dup {
+			errs = append(errs, fmt.Errorf("部件 %s 重复", f.Name))
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s 读取失败: %w", f.Name, err))
+			continue
+		}
+		data, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s 读取失败: %w", f.Name, err))
+			continue
+		}
+		parts[f.Name] = func() (io.Reader, error) { return bytes.NewReader(data), nil }
+	}
+	for _, name := range []string{"_rels/.rels", "word/document.xml"} {
+		if _, ok := parts[name]; !ok {
+			errs = append(errs, fmt.Errorf("缺少 %s", name))
+		}
+	}
+	return append(errs, validateParts(parts)...)
+}
+
+// partOpener 打开一个部件的内容以供读取
+type partOpener func() (io.Reader, error)
+
+// readPart 读取整个部件（仅用于内容类型、关系等小部件）
+func readPart(open partOpener) ([]byte, error) {
+	r, err := open()
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+// packageRel 关系文件中的一条关系
+type packageRel struct {
+	ID         string `xml:"Id,attr"`
+	Target     string `xml:"Target,attr"`
+	TargetMode string `xml:"TargetMode,attr"`
+}
+
+// contentTypes [Content_Types].xml 的内容
+type contentTypes struct {
+	Defaults []struct {
+		Extension string `xml:"Extension,attr"`
+	} `xml:"Default"`
+	Overrides []struct {
+		PartName string `xml:"PartName,attr"`
+	} `xml:"Override"`
+}
+
+// validateParts 校验包内所有部件：
+//   - XML 部件格式良好
+//   - r:id / r:embed 等引用在对应 .rels 中存在，内部关系目标部件存在
+//   - 每个部件都声明了内容类型
+//   - 表格单元格以段落结尾
+//   - 书签起止成对，同一部件内书签名不重复
+//   - 运行属性 w:rPr 的子元素按 CT_RPr 规定的顺序排列
+func validateParts(parts map[string]partOpener) []error {
+	var errs []error
+
+	names := make([]string, 0, len(parts))
+	for name := range parts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// 内容类型
+	ctOpen, ok := parts["[Content_Types].xml"]
+	if !ok {
+		errs = append(errs, errors.New("缺少 [Content_Types].xml"))
+	} else {
+		var ct contentTypes
+		ctData, err := readPart(ctOpen)
+		if err == nil {
+			err = xml.Unmarshal(ctData, &ct)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("[Content_Types].xml 解析失败: %w", err))
+		} else {
+			defaults := make(map[string]bool)
+			for _, def := range ct.Defaults {
+				defaults[strings.ToLower(def.Extension)] = true
+			}
+			overrides := make(map[string]bool)
+			for _, o := range ct.Overrides {
+				overrides[strings.ToLower(o.PartName)] = true
+				if _, ok := parts[strings.TrimPrefix(o.PartName, "/")]; !ok {
+					errs = append(errs, fmt.Errorf("内容类型声明了不存在的部件 %s", o.PartName))
+				}
+			}
+			for _, name := range names {
+				if name == "[Content_Types].xml" {
+					continue
+				}
+				ext := strings.ToLower(strings.TrimPrefix(path.Ext(name), "."))
+				if !overrides["/"+strings.ToLower(name)] && !defaults[ext] {
+					errs = append(errs, fmt.Errorf("部件 %s 未声明内容类型", name))
+				}
+			}
+		}
+	}
+
+	// 关系文件
+	rels := make(map[string]map[string]packageRel)
+	for _, name := range names {
+		if !strings.HasSuffix(name, ".rels") {
+			continue
+		}
+		var doc struct {
+			Rels []packageRel `xml:"Relationship"`
+		}
+		data, err := readPart(parts[name])
+		if err == nil {
+			err = xml.Unmarshal(data, &doc)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s 解析失败: %w", name, err))
+			continue
+		}
+		source := relsSourcePart(name)
+		ids := make(map[string]packageRel, len(doc.Rels))
+		for _, rel := range doc.Rels {
+			if _, dup := ids[rel.ID]; dup {
+				errs = append(errs, fmt.Errorf("%s 中关系 ID %s 重复", name, rel.ID))
+			}
+			ids[rel.ID] = rel
+			if rel.TargetMode == "External" {
+				continue
+			}
+			target := resolveRelTarget(source, rel.Target)
+			if _, ok := parts[target]; !ok {
+				errs = append(errs, fmt.Errorf("%s 中关系 %s 指向不存在的部件 %s", name, rel.ID, target))
+			}
+		}
+		rels[source] = ids
+	}
+
+	// XML 部件
+	for _, name := range names {
+		if !strings.HasSuffix(name, ".xml") || name == "[Content_Types].xml" {
+			continue
+		}
+		r, err := parts[name]()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s 读取失败: %w", name, err))
+			continue
+		}
+		errs = append(errs, validateXMLPart(name, r, rels[name])...)
+	}
+
+	return errs
+}
+
+// validateXMLPart 逐个 token 解析部件，检查关系引用、单元格结构与书签配对
+func validateXMLPart(name string, r io.Reader, rels map[string]packageRel) []error {
+	var errs []error
+	dec := xml.NewDecoder(r)
+
+	// 未结束的书签 ID 与已出现的书签名
+	openBookmarks := make(map[string]bool)
+	bookmarkNames := make(map[string]bool)
+
+	// 每层元素记录元素名与最后一个子元素名，用于判断 w:tc 是否以 w:p 结尾、w:rPr 子元素是否有序
+	var parents, lastChild []string
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s 解析失败: %w", name, err))
+			break
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if len(lastChild) > 0 {
+				prev := lastChild[len(lastChild)-1]
+				if parents[len(parents)-1] == nsWordprocessingML+" rPr" && !rPrOrdered(prev, t.Name) {
+					errs = append(errs, fmt.Errorf("%s 中 <w:rPr> 的子元素 <w:%s> 应在 <w:%s> 之前", name, t.Name.Local, strings.TrimPrefix(prev, nsWordprocessingML+" ")))
+				}
+				lastChild[len(lastChild)-1] = elementKey(t.Name)
+			}
+			parents = append(parents, elementKey(t.Name))
+			lastChild = append(lastChild, "")
+			for _, attr := range t.Attr {
+				if attr.Name.Space != nsRelationships {
+					continue
+				}
+				if _, ok := rels[attr.Value]; !ok {
+					errs = append(errs, fmt.Errorf("%s 中 <%s> 引用了不存在的关系 %s", name, t.Name.Local, attr.Value))
+				}
+			}
+			if t.Name.Space == nsWordprocessingML {
+				switch t.Name.Local {
+				case "bookmarkStart":
+					id, bookmark := wordAttr(t, "id"), wordAttr(t, "name")
+					if openBookmarks[id] {
+						errs = append(errs, fmt.Errorf("%s 中书签 ID %s 重复", name, id))
+					}
+					openBookmarks[id] = true
+					if bookmarkNames[bookmark] {
+						errs = append(errs, fmt.Errorf("%s 中书签名 %s 重复", name, bookmark))
+					}
+					bookmarkNames[bookmark] = true
+				case "bookmarkEnd":
+					id := wordAttr(t, "id")
+					if !openBookmarks[id] {
+						errs = append(errs, fmt.Errorf("%s 中书签终点 %s 没有对应的起点", name, id))
+					}
+					delete(openBookmarks, id)
+				}
+			}
+		case xml.EndElement:
+			last := lastChild[len(lastChild)-1]
+			lastChild = lastChild[:len(lastChild)-1]
+			parents = parents[:len(parents)-1]
+			if t.Name.Space == nsWordprocessingML && t.Name.Local == "tc" && last != nsWordprocessingML+" p" {
+				errs = append(errs, fmt.Errorf("%s 中表格单元格未以段落结尾", name))
+			}
+		}
+	}
+	for _, id := range sortedKeys(openBookmarks) {
+		errs = append(errs, fmt.Errorf("%s 中书签 %s 没有终点", name, id))
+	}
+	return errs
+}
+
+// rPrOrder CT_RPr（含段落标记的 CT_ParaRPr）子元素的规定顺序
+var rPrOrder = func() map[string]int {
+	names := []string{
+		"ins", "del", "moveFrom", "moveTo",
+		"rStyle", "rFonts", "b", "bCs", "i", "iCs", "caps", "smallCaps", "strike", "dstrike",
+		"outline", "shadow", "emboss", "imprint", "noProof", "snapToGrid", "vanish", "webHidden",
+		"color", "spacing", "w", "kern", "position", "sz", "szCs", "highlight", "u", "effect",
+		"bdr", "shd", "fitText", "vertAlign", "rtl", "cs", "em", "lang", "eastAsianLayout",
+		"specVanish", "oMath", "rPrChange",
+	}
+	order := make(map[string]int, len(names))
+	for i, n := range names {
+		order[nsWordprocessingML+" "+n] = i
+	}
+	return order
+}()
+
+// rPrOrdered 判断 w:rPr 中 next 可以跟在 prev（上一个子元素的键，首个子元素时为空）之后；未知元素不检查
+func rPrOrdered(prev string, next xml.Name) bool {
+	p, ok1 := rPrOrder[prev]
+	n, ok2 := rPrOrder[elementKey(next)]
+	return !ok1 || !ok2 || p <= n
+}
+
+// wordAttr 返回 w: 命名空间下的属性值
+func wordAttr(el xml.StartElement, local string) string {
+	for _, a := range el.Attr {
+		if a.Name.Space == nsWordprocessingML && a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// sortedKeys 返回按字典序排列的键，使错误顺序稳定
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func elementKey(n xml.Name) string {
+	return n.Space + " " + n.Local
+}
+
+// relsSourcePart 由关系文件名推出其所属部件，如 word/_rels/document.xml.rels -> word/document.xml；
+// 包级关系 _rels/.rels 返回空字符串
+func relsSourcePart(relsName string) string {
+	dir, file := path.Split(relsName)
+	dir = strings.TrimSuffix(strings.TrimSuffix(dir, "/"), "_rels")
+	return strings.TrimPrefix(dir+strings.TrimSuffix(file, ".rels"), "/")
+}
+
+// resolveRelTarget 把关系目标解析为包内部件名
+func resolveRelTarget(source, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return strings.TrimPrefix(path.Join(path.Dir(source), target), "/")
+}
@@ -0,0 +1,102 @@
+package docx
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"md2word/internal/config"
+)
+
+func TestValidateSavedDocument(t *testing.T) {
+	// 所有可选的运行属性同时开启，检查样式与运行的 rPr 子元素顺序
+	cfg := config.DefaultConfig()
+	cfg.Styles.Body.Bold, cfg.Styles.Body.Italic, cfg.Styles.Body.Color = true, true, "#333333"
+	cfg.Styles.Heading1.Italic, cfg.Styles.Heading1.SmallCaps = true, true
+	cfg.Styles.Heading1.Color, cfg.Styles.Heading1.LetterSpacing = "#1F4E79", 40
+	doc := NewDocument(cfg)
+	p := NewParagraph("Heading1")
+	p.Children = append(p.Children, &BookmarkStart{ID: 1, Name: "intro"})
+	run := p.AddRun("引言")
+	run.Bold, run.Italic, run.Underline, run.Strike, run.SmallCaps, run.AllCaps = true, true, true, true, true, true
+	run.FontName, run.FontSize, run.Color, run.Highlight, run.Shading = "宋体", 12, "C00000", "yellow", "EEEEEE"
+	run.IsCode, run.Position, run.Spacing = true, -4, 20
+	p.Children = append(p.Children, &BookmarkEnd{ID: 1})
+	doc.AddParagraph(p)
+	out := filepath.Join(t.TempDir(), "out.docx")
+	if err := doc.Save(out); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if errs := Validate(out); errs != nil {
+		t.Errorf("Validate = %v, want no errors", errs)
+	}
+}
+
+// writeZip 把 parts 写成 zip 文件并返回路径
+func writeZip(t *testing.T, parts map[string]string) string {
+	t.Helper()
+	out := filepath.Join(t.TempDir(), "broken.docx")
+	f, err := os.Create(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for name, content := range parts {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	return out
+}
+
+func TestValidateReportsBrokenPackage(t *testing.T) {
+	out := writeZip(t, map[string]string{
+		"[Content_Types].xml": `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+    <Default Extension="xml" ContentType="application/xml"/>
+</Types>`,
+		"word/document.xml": `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>
+    <w:p><w:bookmarkStart w:id="1" w:name="a"/><w:bookmarkStart w:id="2" w:name="a"/><w:bookmarkEnd w:id="3"/></w:p>
+    <w:tbl><w:tr><w:tc></w:tc></w:tr></w:tbl>
+    <w:p><w:r><w:rPr><w:sz w:val="20"/><w:b/></w:rPr><w:t>x</w:t></w:r></w:p>
+</w:body></w:document>`,
+		"word/media/image1.png": "",
+	})
+
+	var msgs []string
+	for _, err := range Validate(out) {
+		msgs = append(msgs, err.Error())
+	}
+	got := strings.Join(msgs, "\n")
+	for _, want := range []string{
+		"缺少 _rels/.rels",
+		"部件 word/media/image1.png 未声明内容类型",
+		"书签名 a 重复",
+		"书签终点 3 没有对应的起点",
+		"书签 1 没有终点",
+		"书签 2 没有终点",
+		"表格单元格未以段落结尾",
+		"<w:rPr> 的子元素 <w:b> 应在 <w:sz> 之前",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("errors missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestValidateNotZip(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "plain.docx")
+	if err := os.WriteFile(out, []byte("not a zip"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if errs := Validate(out); len(errs) != 1 {
+		t.Errorf("Validate = %v, want one error", errs)
+	}
+}
@@ -0,0 +1,106 @@
+package docx
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+// largeDocumentElements 构造一份约 2000 段落、200 个表格的文档正文，
+// 段落混合普通、加粗、代码、超链接等运行
+func largeDocumentElements() []Element {
+	var elems []Element
+	for i := 0; i < 2000; i++ {
+		p := NewParagraph("")
+		p.LineHeight = 360
+		p.FirstLineIndent = 420
+		p.AddRun(fmt.Sprintf("第 %d 段：正文内容 plain text with <escaped> & characters, ", i))
+		bold := p.AddRun("加粗文字")
+		bold.Bold = true
+		code := p.AddRun("fmt.Println(x)")
+		code.IsCode = true
+		code.FontName = "Consolas"
+		code.FontSize = 8
+		link := p.AddHyperlink("rId9")
+		lr := link.AddRun("https://example.com")
+		lr.Color = "0563C1"
+		lr.Underline = true
+		p.AddRun("\tcolumn\tafter tab\nnext line")
+		elems = append(elems, p)
+
+		if i%10 == 0 {
+			table := NewTable()
+			for r := 0; r < 5; r++ {
+				row := table.AddRow(r == 0)
+				for c := 0; c < 4; c++ {
+					row.AddCell().SetText(fmt.Sprintf("单元格 %d-%d", r, c), r == 0)
+				}
+			}
+			elems = append(elems, NewTableElement(table))
+		}
+	}
+	return elems
+}
+
+// BenchmarkDocumentXML 对比两种生成 document.xml 正文的方式：
+//   - ToXML:    旧方式，每个元素先生成独立字符串再拼接
+//   - WriteXML: 元素直接写入共享缓冲区
+func BenchmarkDocumentXML(b *testing.B) {
+	elems := largeDocumentElements()
+
+	b.Run("ToXML", func(b *testing.B) {
+		b.ReportAllocs()
+		var buf bytes.Buffer
+		for i := 0; i < b.N; i++ {
+			buf.Reset()
+			for _, elem := range elems {
+				buf.WriteString(elem.ToXML())
+			}
+		}
+		b.SetBytes(int64(buf.Len()))
+	})
+
+	b.Run("WriteXML", func(b *testing.B) {
+		b.ReportAllocs()
+		var buf bytes.Buffer
+		for i := 0; i < b.N; i++ {
+			buf.Reset()
+			for _, elem := range elems {
+				writeElementXML(&buf, elem)
+			}
+		}
+		b.SetBytes(int64(buf.Len()))
+	})
+}
+
+// BenchmarkRunXML 单个运行的生成开销
+func BenchmarkRunXML(b *testing.B) {
+	run := &Run{Text: "text with\ttab and <escape>", Bold: true, FontName: "宋体", FontSize: 10.5, Color: "333333"}
+
+	b.Run("ToXML", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = run.ToXML()
+		}
+	})
+
+	b.Run("WriteXML", func(b *testing.B) {
+		b.ReportAllocs()
+		var buf bytes.Buffer
+		for i := 0; i < b.N; i++ {
+			buf.Reset()
+			run.WriteXML(&buf)
+		}
+	})
+}
+
+// TestWriteXMLMatchesToXML 两种方式输出必须一致
+func TestWriteXMLMatchesToXML(t *testing.T) {
+	for _, elem := range largeDocumentElements()[:50] {
+		var buf bytes.Buffer
+		writeElementXML(&buf, elem)
+		if buf.String() != elem.ToXML() {
+			t.Fatalf("WriteXML and ToXML differ for %T", elem)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Package i18n 提供面向用户的文字（错误、提示、占位、题注）的多语言消息表
+package i18n
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 支持的语言
+const (
+	LangZH = "zh"
+	LangEN = "en"
+)
+
+// DefaultLang 未配置语言时使用的语言
+const DefaultLang = LangZH
+
+// 消息键
+const (
+	// 转换过程
+	ConvertFailed     = "convertFailed"
+	ProcessingMermaid = "processingMermaid"
+	MermaidFailed     = "mermaidFailed"
+	MathFailed        = "mathFailed"
+	MathSizeInvalid   = "mathSizeInvalid"
+	ImageLoadFailed   = "imageLoadFailed"
+	SVGRejected       = "svgRejected"
+	ImageCachePrune   = "imageCachePrune"
+	ImageCacheStore   = "imageCacheStore"
+	UndefinedVariable = "undefinedVariable"
+	SectionFormat     = "sectionFormat"
+	SectionStart      = "sectionStart"
+	ColorInvalid      = "colorInvalid"
+	BorderInvalid     = "borderInvalid"
+	A11yImageAlt      = "a11yImageAlt"
+	A11yHeadingSkip   = "a11yHeadingSkip"
+	A11yTableHeader   = "a11yTableHeader"
+	A11yFailed        = "a11yFailed"
+	CreateDirFailed   = "createDirFailed"
+
+	// 渲染失败占位
+	PlaceholderMermaid = "placeholderMermaid"
+	PlaceholderMath    = "placeholderMath"
+	PlaceholderImage   = "placeholderImage"
+
+	// 题注标签
+	CaptionFigure   = "captionFigure"
+	CaptionTable    = "captionTable"
+	CaptionEquation = "captionEquation"
+
+	// 命令行
+	LoadedConfig   = "loadedConfig"
+	ReadFailed     = "readFailed"
+	WatchFileOnly  = "watchFileOnly"
+	WatchFailed    = "watchFailed"
+	Watching       = "watching"
+	ConvertCancel  = "convertCancel"
+	ConvertSuccess = "convertSuccess"
+	ConvertDone    = "convertDone"
+	FileFailed     = "fileFailed"
+)
+
+// messages 各语言的消息格式，按 fmt 规则格式化
+var messages = map[string]map[string]string{
+	LangZH: {
+		ConvertFailed:     "转换失败: %w",
+		ProcessingMermaid: "正在处理 Mermaid 流程图...",
+		MermaidFailed:     "Mermaid 渲染错误: %v",
+		MathFailed:        "公式渲染失败: %s, %v",
+		MathSizeInvalid:   "公式图片尺寸异常: %s",
+		ImageLoadFailed:   "图片加载失败: %s, %v",
+		SVGRejected:       "未嵌入 SVG 图片 (images.svg: reject): %s",
+		ImageCachePrune:   "图片缓存清理失败: %v",
+		ImageCacheStore:   "图片缓存写入失败: %v",
+		UndefinedVariable: "未定义的变量: {{%s}}",
+		SectionFormat:     "section 指令: 不支持的页码格式 %q",
+		SectionStart:      "section 指令: 无效的起始页码 %q",
+		ColorInvalid:      "color 指令: 无效的颜色 %q",
+		BorderInvalid:     "border 指令: 无效的参数 %q",
+		A11yImageAlt:      "无障碍: 图片缺少替代文字: %s",
+		A11yHeadingSkip:   "无障碍: 标题层级跳跃 (H%d → H%d): %s",
+		A11yTableHeader:   "无障碍: 第 %d 个表格缺少表头行",
+		A11yFailed:        "无障碍检查未通过: %d 个问题",
+		CreateDirFailed:   "创建目录失败: %w",
+
+		PlaceholderMermaid: "[流程图渲染失败]",
+		PlaceholderMath:    "[公式渲染失败]",
+		PlaceholderImage:   "[图片加载失败]",
+
+		CaptionFigure:   "图",
+		CaptionTable:    "表",
+		CaptionEquation: "公式",
+
+		LoadedConfig:   "加载配置: %s",
+		ReadFailed:     "读取文件失败: %v",
+		WatchFileOnly:  "错误: 监视模式只支持单个文件",
+		WatchFailed:    "监视失败: %v",
+		Watching:       "监视中: %s (Ctrl+C 退出)",
+		ConvertCancel:  "转换已取消",
+		ConvertSuccess: "转换成功: %s -> %s",
+		ConvertDone:    "转换完成: %d 个文件成功, %d 个失败 (%s -> %s)",
+		FileFailed:     "转换失败: %s: %v",
+	},
+	LangEN: {
+		ConvertFailed:     "conversion failed: %w",
+		ProcessingMermaid: "Processing Mermaid diagrams...",
+		MermaidFailed:     "Mermaid rendering error: %v",
+		MathFailed:        "formula rendering failed: %s, %v",
+		MathSizeInvalid:   "formula image has invalid size: %s",
+		ImageLoadFailed:   "failed to load image: %s, %v",
+		SVGRejected:       "SVG image not embedded (images.svg: reject): %s",
+		ImageCachePrune:   "failed to prune image cache: %v",
+		ImageCacheStore:   "failed to write image cache: %v",
+		UndefinedVariable: "undefined variable: {{%s}}",
+		SectionFormat:     "section directive: unsupported page number format %q",
+		SectionStart:      "section directive: invalid start page %q",
+		ColorInvalid:      "color directive: invalid color %q",
+		BorderInvalid:     "border directive: invalid argument %q",
+		A11yImageAlt:      "accessibility: image has no alt text: %s",
+		A11yHeadingSkip:   "accessibility: heading level skipped (H%d → H%d): %s",
+		A11yTableHeader:   "accessibility: table %d has no header row",
+		A11yFailed:        "accessibility check failed: %d issue(s)",
+		CreateDirFailed:   "failed to create directory: %w",
+
+		PlaceholderMermaid: "[Diagram rendering failed]",
+		PlaceholderMath:    "[Formula rendering failed]",
+		PlaceholderImage:   "[Image failed to load]",
+
+		CaptionFigure:   "Figure",
+		CaptionTable:    "Table",
+		CaptionEquation: "Equation",
+
+		LoadedConfig:   "Loaded config: %s",
+		ReadFailed:     "failed to read file: %v",
+		WatchFileOnly:  "error: watch mode only supports a single file",
+		WatchFailed:    "watch failed: %v",
+		Watching:       "Watching: %s (Ctrl+C to quit)",
+		ConvertCancel:  "conversion canceled",
+		ConvertSuccess: "Converted: %s -> %s",
+		ConvertDone:    "Done: %d file(s) converted, %d failed (%s -> %s)",
+		FileFailed:     "conversion failed: %s: %v",
+	},
+}
+
+// Supported 判断 lang 是否为支持的语言；空值表示默认语言
+func Supported(lang string) bool {
+	if lang == "" {
+		return true
+	}
+	_, ok := messages[lang]
+	return ok
+}
+
+// format 返回 key 在 lang 下的消息格式；语言未知或缺少译文时回退到默认语言
+func format(lang, key string) string {
+	if msg, ok := messages[lang][key]; ok {
+		return msg
+	}
+	return messages[DefaultLang][key]
+}
+
+// T 返回按 lang 翻译并以 args 格式化的消息
+func T(lang, key string, args ...any) string {
+	if len(args) == 0 {
+		return format(lang, key)
+	}
+	return fmt.Sprintf(format(lang, key), args...)
+}
+
+// Errorf 返回按 lang 翻译的错误，消息格式中的 %w 照常包装 args 中的错误
+func Errorf(lang, key string, args ...any) error {
+	if len(args) == 0 {
+		return errors.New(format(lang, key))
+	}
+	return fmt.Errorf(format(lang, key), args...)
+}
@@ -0,0 +1,41 @@
+package i18n
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMessagesComplete(t *testing.T) {
+	for lang, table := range messages {
+		for key := range messages[DefaultLang] {
+			if table[key] == "" {
+				t.Errorf("%s: missing message %q", lang, key)
+			}
+		}
+		for key := range table {
+			if _, ok := messages[DefaultLang][key]; !ok {
+				t.Errorf("%s: unknown message %q", lang, key)
+			}
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	if got := T(LangEN, CaptionFigure); got != "Figure" {
+		t.Errorf("en caption = %q", got)
+	}
+	if got := T("", CaptionTable); got != "表" {
+		t.Errorf("default caption = %q", got)
+	}
+	if got := T("fr", Watching, "a.md"); got != "监视中: a.md (Ctrl+C 退出)" {
+		t.Errorf("unknown lang fallback = %q", got)
+	}
+	cause := errors.New("disk full")
+	err := Errorf(LangEN, ConvertFailed, cause)
+	if err.Error() != "conversion failed: disk full" || !errors.Is(err, cause) {
+		t.Errorf("Errorf = %v", err)
+	}
+	if !Supported("") || !Supported(LangEN) || Supported("fr") {
+		t.Error("Supported mismatch")
+	}
+}
@@ -0,0 +1,112 @@
+package parser
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// CriticKind CriticMarkup 标记类型
+type CriticKind int
+
+const (
+	CriticInsertion    CriticKind = iota // {++新增++}
+	CriticDeletion                       // {--删除--}
+	CriticSubstitution                   // {~~原文~>新文~~}，子节点为一个删除与一个新增
+	CriticHighlight                      // {==高亮==}
+	CriticComment                        // {>>批注<<}，紧跟在高亮之后时批注该高亮文字
+)
+
+// KindCriticMarkup CriticMarkup 节点的 NodeKind
+var KindCriticMarkup = ast.NewNodeKind("CriticMarkup")
+
+// CriticMarkup 行内审阅标记，子节点为标记内的文本
+type CriticMarkup struct {
+	ast.BaseInline
+	Critic CriticKind
+}
+
+// Kind 实现 ast.Node
+func (n *CriticMarkup) Kind() ast.NodeKind {
+	return KindCriticMarkup
+}
+
+// Dump 实现 ast.Node
+func (n *CriticMarkup) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Critic": criticNames[n.Critic]}, nil)
+}
+
+var criticNames = map[CriticKind]string{
+	CriticInsertion:    "insertion",
+	CriticDeletion:     "deletion",
+	CriticSubstitution: "substitution",
+	CriticHighlight:    "highlight",
+	CriticComment:      "comment",
+}
+
+// criticDelimiters 各类型的开闭标记
+var criticDelimiters = []struct {
+	kind        CriticKind
+	open, close string
+}{
+	{CriticInsertion, "{++", "++}"},
+	{CriticDeletion, "{--", "--}"},
+	{CriticSubstitution, "{~~", "~~}"},
+	{CriticHighlight, "{==", "==}"},
+	{CriticComment, "{>>", "<<}"},
+}
+
+// criticParser 解析同一行内的 CriticMarkup 标记；找不到闭合标记时返回 nil，原文按普通文本处理
+type criticParser struct{}
+
+// NewCriticParser 返回解析 CriticMarkup 的 InlineParser
+func NewCriticParser() parser.InlineParser {
+	return &criticParser{}
+}
+
+func (p *criticParser) Trigger() []byte {
+	return []byte{'{'}
+}
+
+func (p *criticParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, segment := block.PeekLine()
+	for _, d := range criticDelimiters {
+		if !bytes.HasPrefix(line, []byte(d.open)) {
+			continue
+		}
+		end := bytes.Index(line[len(d.open):], []byte(d.close))
+		if end <= 0 {
+			return nil
+		}
+		start := segment.Start + len(d.open)
+		stop := start + end
+
+		node := &CriticMarkup{Critic: d.kind}
+		if d.kind == CriticSubstitution {
+			arrow := bytes.Index(line[len(d.open):len(d.open)+end], []byte("~>"))
+			if arrow < 0 {
+				return nil
+			}
+			node.AppendChild(node, criticText(CriticDeletion, start, start+arrow))
+			node.AppendChild(node, criticText(CriticInsertion, start+arrow+2, stop))
+		} else {
+			node.AppendChild(node, ast.NewTextSegment(text.NewSegment(start, stop)))
+		}
+		block.Advance(len(d.open) + end + len(d.close))
+		return node
+	}
+	return nil
+}
+
+// criticText 创建只含一段文本的标记节点
+func criticText(kind CriticKind, start, stop int) *CriticMarkup {
+	node := &CriticMarkup{Critic: kind}
+	if stop > start {
+		node.AppendChild(node, ast.NewTextSegment(text.NewSegment(start, stop)))
+	}
+	return node
+}
+
+func (p *criticParser) CloseBlock(parent ast.Node, pc parser.Context) {}
@@ -0,0 +1,69 @@
+package parser
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// KindIndexEntry IndexEntry 节点的 NodeKind
+var KindIndexEntry = ast.NewNodeKind("IndexEntry")
+
+// IndexEntry 行内索引项标记 {index: 术语}，本身不显示文字；
+// {INDEX} 解析为 Term 为空的节点，表示在此生成索引
+type IndexEntry struct {
+	ast.BaseInline
+	Term []byte // 索引项，可用 主项:子项 表示二级索引
+}
+
+// Kind 实现 ast.Node
+func (n *IndexEntry) Kind() ast.NodeKind {
+	return KindIndexEntry
+}
+
+// Dump 实现 ast.Node
+func (n *IndexEntry) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Term": string(n.Term)}, nil)
+}
+
+var (
+	indexEntryOpen   = []byte("{index:")
+	indexPlaceholder = []byte("{INDEX}")
+)
+
+// indexParser 解析同一行内的 {index: 术语} 与 {INDEX}
+type indexParser struct{}
+
+// NewIndexParser 返回解析索引标记的 InlineParser
+func NewIndexParser() parser.InlineParser {
+	return &indexParser{}
+}
+
+func (p *indexParser) Trigger() []byte {
+	return []byte{'{'}
+}
+
+func (p *indexParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	if bytes.HasPrefix(line, indexPlaceholder) {
+		block.Advance(len(indexPlaceholder))
+		return &IndexEntry{}
+	}
+	if !bytes.HasPrefix(line, indexEntryOpen) {
+		return nil
+	}
+	end := bytes.IndexByte(line, '}')
+	if end < 0 {
+		return nil
+	}
+	term := bytes.TrimSpace(line[len(indexEntryOpen):end])
+	if len(term) == 0 {
+		return nil
+	}
+	block.Advance(end + 1)
+	return &IndexEntry{Term: append([]byte(nil), term...)}
+}
+
+func (p *indexParser) CloseBlock(parent ast.Node, pc parser.Context) {}
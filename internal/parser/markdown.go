@@ -16,8 +16,37 @@ type MarkdownParser struct {
 	md goldmark.Markdown
 }
 
+// Option 解析器选项
+type Option func(*options)
+
+type options struct {
+	criticMarkup bool
+	wikiLinks    bool
+	index        bool
+}
+
+// WithCriticMarkup 启用 CriticMarkup 审阅标记 ({++新增++}、{--删除--}、{~~原文~>新文~~}、{==高亮==}、{>>批注<<})
+func WithCriticMarkup() Option {
+	return func(o *options) { o.criticMarkup = true }
+}
+
+// WithWikiLinks 启用 Obsidian 风格的 [[链接]] 与 ![[图片]] 嵌入
+func WithWikiLinks() Option {
+	return func(o *options) { o.wikiLinks = true }
+}
+
+// WithIndex 启用索引标记：{index: 术语} 标记索引项，{INDEX} 标记索引位置
+func WithIndex() Option {
+	return func(o *options) { o.index = true }
+}
+
 // NewMarkdownParser 创建新的解析器
-func NewMarkdownParser() *MarkdownParser {
+func NewMarkdownParser(opts ...Option) *MarkdownParser {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	md := goldmark.New(
 		goldmark.WithExtensions(
 			extension.GFM,           // GitHub Flavored Markdown
@@ -37,8 +66,20 @@ func NewMarkdownParser() *MarkdownParser {
 					600,
 				),
 			),
+			// {{name}} 变量占位符，未定义时由转换器按原文输出
+			parser.WithInlineParsers(util.Prioritized(NewVariableParser(), 100)),
 		),
 	)
+	if o.criticMarkup {
+		md.Parser().AddOptions(parser.WithInlineParsers(util.Prioritized(NewCriticParser(), 100)))
+	}
+	if o.wikiLinks {
+		// 先于普通链接解析器 (200)，[[ 不会被当作链接文字的开始
+		md.Parser().AddOptions(parser.WithInlineParsers(util.Prioritized(NewWikiLinkParser(), 150)))
+	}
+	if o.index {
+		md.Parser().AddOptions(parser.WithInlineParsers(util.Prioritized(NewIndexParser(), 100)))
+	}
 	return &MarkdownParser{md: md}
 }
 
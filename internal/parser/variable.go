@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"regexp"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// KindVariable Variable 节点的 NodeKind
+var KindVariable = ast.NewNodeKind("Variable")
+
+// Variable {{name}} 变量占位符，转换时替换为变量值；
+// 原文保存在 Segment 中，未定义的变量按原文显示
+type Variable struct {
+	ast.BaseInline
+	Name    []byte
+	Segment text.Segment
+}
+
+// Kind 实现 ast.Node
+func (n *Variable) Kind() ast.NodeKind {
+	return KindVariable
+}
+
+// Dump 实现 ast.Node
+func (n *Variable) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Name": string(n.Name)}, nil)
+}
+
+// variablePattern 行首的 {{name}}，名称两侧允许空白
+var variablePattern = regexp.MustCompile(`^\{\{\s*([A-Za-z_][\w.-]*)\s*\}\}`)
+
+// variableParser 解析 {{name}} 占位符
+type variableParser struct{}
+
+// NewVariableParser 返回解析变量占位符的 InlineParser
+func NewVariableParser() parser.InlineParser {
+	return &variableParser{}
+}
+
+func (p *variableParser) Trigger() []byte {
+	return []byte{'{'}
+}
+
+func (p *variableParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, segment := block.PeekLine()
+	m := variablePattern.FindSubmatchIndex(line)
+	if m == nil {
+		return nil
+	}
+	node := &Variable{
+		Name:    append([]byte(nil), line[m[2]:m[3]]...),
+		Segment: text.NewSegment(segment.Start, segment.Start+m[1]),
+	}
+	block.Advance(m[1])
+	return node
+}
+
+func (p *variableParser) CloseBlock(parent ast.Node, pc parser.Context) {}
@@ -0,0 +1,79 @@
+package parser
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// KindWikiLink WikiLink 节点的 NodeKind
+var KindWikiLink = ast.NewNodeKind("WikiLink")
+
+// WikiLink Obsidian 风格的 [[目标]] / [[目标|显示文字]] 链接与 ![[图片]] 嵌入
+type WikiLink struct {
+	ast.BaseInline
+	Target []byte // | 之前的部分，可带 #标题
+	Label  []byte // | 之后的显示文字，未指定时为 nil
+	Embed  bool   // ![[...]] 嵌入
+}
+
+// Kind 实现 ast.Node
+func (n *WikiLink) Kind() ast.NodeKind {
+	return KindWikiLink
+}
+
+// Dump 实现 ast.Node
+func (n *WikiLink) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{
+		"Target": string(n.Target),
+		"Label":  string(n.Label),
+	}, nil)
+}
+
+// wikiLinkParser 解析同一行内的 [[...]] 与 ![[...]]；格式不完整时返回 nil，交给普通链接解析
+type wikiLinkParser struct{}
+
+// NewWikiLinkParser 返回解析 WikiLink 的 InlineParser
+func NewWikiLinkParser() parser.InlineParser {
+	return &wikiLinkParser{}
+}
+
+func (p *wikiLinkParser) Trigger() []byte {
+	return []byte{'!', '['}
+}
+
+func (p *wikiLinkParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	embed := len(line) > 0 && line[0] == '!'
+	open := 2
+	if embed {
+		open = 3
+	}
+	if !bytes.HasPrefix(line[open-2:], []byte("[[")) {
+		return nil
+	}
+	end := bytes.Index(line[open:], []byte("]]"))
+	if end <= 0 {
+		return nil
+	}
+	content := line[open : open+end]
+	if bytes.ContainsAny(content, "[]") {
+		return nil
+	}
+
+	node := &WikiLink{Embed: embed}
+	target, label, hasLabel := bytes.Cut(content, []byte("|"))
+	node.Target = bytes.TrimSpace(target)
+	if hasLabel {
+		node.Label = bytes.TrimSpace(label)
+	}
+	if len(node.Target) == 0 {
+		return nil
+	}
+	block.Advance(open + end + 2)
+	return node
+}
+
+func (p *wikiLinkParser) CloseBlock(parent ast.Node, pc parser.Context) {}